@@ -3,6 +3,7 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/urfave/cli/v2"
 
+	"github.com/gruntwork-io/boilerplate/lockfile"
 	"github.com/gruntwork-io/boilerplate/manifest"
 	"github.com/gruntwork-io/boilerplate/options"
 	"github.com/gruntwork-io/boilerplate/pkg/logging"
@@ -58,7 +60,7 @@ func CreateBoilerplateCli() *cli.App {
 	app.UsageText = "boilerplate [OPTIONS]"
 	app.Version = version.GetVersion()
 	app.Action = runApp
-	app.Commands = []*cli.Command{newInputsCommand()}
+	app.Commands = []*cli.Command{newInputsCommand(), newLockCommand(), newVarsCommand()}
 
 	app.Flags = []cli.Flag{
 		&cli.StringFlag{
@@ -77,9 +79,25 @@ func CreateBoilerplateCli() *cli.App {
 			Name:  options.OptVar,
 			Usage: "Use `NAME=VALUE` to set variable NAME to VALUE. May be specified more than once.",
 		},
+		&cli.StringSliceFlag{
+			Name:  options.OptSet,
+			Usage: "Use `PATH=VALUE` to set a single key of a map or object variable, e.g., Tags.env=prod, merging it into the rest of the variable's value. Prefix PATH with dependency names to target a dependency, e.g., dep1.dep2.Config.port=8080. May be specified more than once.",
+		},
 		&cli.StringSliceFlag{
 			Name:  options.OptVarFile,
-			Usage: "Load variable values from the YAML file `FILE`. May be specified more than once.",
+			Usage: "Load variable values from the file `FILE`. YAML, JSON, TOML, .env, .tfvars and .tfvars.json files are supported, detected by extension. Use tfoutput:[PREFIX=]FILE to load the output of terraform output -json. May be specified more than once.",
+		},
+		&cli.BoolFlag{
+			Name:  options.OptStrictVars,
+			Usage: fmt.Sprintf("Exit with an error if a variable passed in via --%s, --%s, --%s or a BOILERPLATE_ environment variable is not declared in the template or any of its dependencies.", options.OptVar, options.OptVarFile, options.OptSet),
+		},
+		&cli.BoolFlag{
+			Name:  options.OptReuseAnswers,
+			Usage: fmt.Sprintf("Use the variable values recorded in the manifest of a previous run, in --%s or --%s, as the defaults for prompts and for --%s.", options.OptOutputFolder, options.OptManifestFile, options.OptNonInteractive),
+		},
+		&cli.StringFlag{
+			Name:  options.OptSaveAnswers,
+			Usage: fmt.Sprintf("Write the values entered at prompts, and the answers to the prompts for dependencies, hooks and shell commands, to the var file `FILE`, which can be replayed with --%s and --%s.", options.OptVarFile, options.OptNonInteractive),
 		},
 		&cli.StringFlag{
 			Name:  options.OptMissingKeyAction,
@@ -109,10 +127,14 @@ func CreateBoilerplateCli() *cli.App {
 			Name:  options.OptManifestFile,
 			Usage: "Write the manifest to `FILE` instead of the default location. Implies --manifest. Format is auto-detected from extension (.yaml/.yml for YAML, otherwise JSON).",
 		},
+		&cli.StringFlag{
+			Name:  options.OptLockFile,
+			Usage: fmt.Sprintf("Download remote templates from the sources pinned in the lock file `FILE`, written by boilerplate lock, and exit with an error if a remote template is not in it or does not match it. Default: %s, if it exists.", lockfile.DefaultFilename),
+		},
 		&cli.IntFlag{
 			Name:  options.OptParallelism,
 			Value: runtime.NumCPU(),
-			Usage: "Maximum number of parallel operations Boilerplate will perform at once (default: number of CPUs).",
+			Usage: "Maximum number of parallel operations Boilerplate will perform at once (default: the parallelism in a defaults file, or the number of CPUs).",
 		},
 	}
 
@@ -145,6 +167,13 @@ func runApp(cliContext *cli.Context) error {
 	l := logging.New(os.Stdout, logging.LevelInfo)
 
 	result, err := templates.ProcessTemplateWithContext(ctx, l, opts, opts, &emptyDep)
+
+	// Save the answers even if processing failed, so the prompts don't have to be answered again after fixing the
+	// problem
+	if saveErr := saveAnswers(cliContext.String(options.OptSaveAnswers), opts.SaveAnswers); saveErr != nil {
+		return errors.Join(err, saveErr)
+	}
+
 	if err != nil {
 		return err
 	}
@@ -157,12 +186,7 @@ func runApp(cliContext *cli.Context) error {
 
 		m := manifest.NewManifest(opts.TemplateURL, opts.OutputFolder, result.SourceChecksum, files, result.Variables, result.Dependencies)
 
-		manifestPath := filepath.Join(opts.OutputFolder, manifest.DefaultManifestFilename)
-		if opts.ManifestFile != "" {
-			manifestPath = opts.ManifestFile
-		}
-
-		if err := manifest.WriteManifest(manifestPath, m); err != nil {
+		if err := manifest.WriteManifest(manifestPathForOptions(opts), m); err != nil {
 			return err
 		}
 	}
@@ -170,6 +194,34 @@ func runApp(cliContext *cli.Context) error {
 	return nil
 }
 
+// manifestPathForOptions returns the path of the manifest for the given options: --manifest-file if set, or the
+// default manifest file in the output folder otherwise.
+func manifestPathForOptions(opts *options.BoilerplateOptions) string {
+	if opts.ManifestFile != "" {
+		return opts.ManifestFile
+	}
+
+	return filepath.Join(opts.OutputFolder, manifest.DefaultManifestFilename)
+}
+
+// saveAnswers writes the answers recorded with --save-answers to the var file at the given path.
+func saveAnswers(path string, recorder *variables.AnswerRecorder) error {
+	if recorder == nil {
+		return nil
+	}
+
+	varFile, err := recorder.VarFile()
+	if err != nil {
+		return SaveAnswersError{Err: err, Path: path}
+	}
+
+	if err := os.WriteFile(path, varFile, 0600); err != nil {
+		return SaveAnswersError{Err: err, Path: path}
+	}
+
+	return nil
+}
+
 // computeChecksums streams each generated file through a SHA256 hasher.
 func computeChecksums(outputDir string, relativePaths []string) ([]manifest.GeneratedFile, error) {
 	files := make([]manifest.GeneratedFile, 0, len(relativePaths))
@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gruntwork-io/boilerplate/util"
+	"github.com/gruntwork-io/boilerplate/variables"
+)
+
+// ProjectDefaultsFileName is the name of the project defaults file, which is looked up in the output folder and each
+// of its parent folders.
+const ProjectDefaultsFileName = ".boilerplate.yml"
+
+// DefaultsFile holds the answers and settings that stay the same from run to run, such as the name of the author or
+// the default region, so they don't have to be passed in every time. It is read from the user defaults file,
+// ~/.config/boilerplate/defaults.yml, and from the nearest project defaults file, .boilerplate.yml.
+type DefaultsFile struct {
+	// Vars are the default values of variables, which are overridden by --var, --var-file and BOILERPLATE_
+	// environment variables
+	Vars map[string]any `yaml:"vars"`
+	// TrustedTemplates are the patterns of the remote template URLs that may be used. If there are none, any template
+	// may be used.
+	TrustedTemplates []string `yaml:"trusted_templates"`
+	// Parallelism is the default for --parallelism
+	Parallelism int `yaml:"parallelism"`
+}
+
+// loadDefaultsFiles loads the user defaults file and the project defaults file nearest to the given output folder, if
+// they exist, and merges them. The project defaults file takes precedence over the user defaults file, except that
+// the trusted templates of both are used.
+func loadDefaultsFiles(outputFolder string) (*DefaultsFile, error) {
+	defaults := &DefaultsFile{Vars: map[string]any{}}
+
+	userPath, err := userDefaultsFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	projectPath, err := findProjectDefaultsFile(outputFolder)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range []string{userPath, projectPath} {
+		if path == "" {
+			continue
+		}
+
+		defaultsFile, err := parseDefaultsFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		defaults.Vars = util.MergeMaps(defaults.Vars, defaultsFile.Vars)
+		defaults.TrustedTemplates = append(defaults.TrustedTemplates, defaultsFile.TrustedTemplates...)
+
+		if defaultsFile.Parallelism > 0 {
+			defaults.Parallelism = defaultsFile.Parallelism
+		}
+	}
+
+	return defaults, nil
+}
+
+// userDefaultsFilePath returns the path of the user defaults file: boilerplate/defaults.yml in $XDG_CONFIG_HOME, or in
+// ~/.config if it is not set. Returns an empty string if there is no home folder to look in.
+func userDefaultsFilePath() (string, error) {
+	if configHome := os.Getenv("XDG_CONFIG_HOME"); configHome != "" {
+		return filepath.Join(configHome, "boilerplate", "defaults.yml"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", nil
+	}
+
+	return filepath.Join(home, ".config", "boilerplate", "defaults.yml"), nil
+}
+
+// findProjectDefaultsFile returns the path of the project defaults file in the given output folder or the nearest of
+// its parent folders, or an empty string if there is none. The output folder doesn't have to exist yet.
+func findProjectDefaultsFile(outputFolder string) (string, error) {
+	if outputFolder == "" {
+		return "", nil
+	}
+
+	folder, err := filepath.Abs(outputFolder)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		path := filepath.Join(folder, ProjectDefaultsFileName)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, nil
+		}
+
+		parent := filepath.Dir(folder)
+		if parent == folder {
+			return "", nil
+		}
+
+		folder = parent
+	}
+}
+
+// parseDefaultsFile parses the defaults file at the given path. Unknown keys are an error, to catch typos.
+func parseDefaultsFile(path string) (*DefaultsFile, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultsFile := &DefaultsFile{}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(contents))
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(defaultsFile); err != nil && !errors.Is(err, io.EOF) {
+		return nil, InvalidDefaultsFile{Err: err, Path: path}
+	}
+
+	converted, err := variables.ConvertYAMLToStringMap(defaultsFile.Vars)
+	if err != nil {
+		return nil, InvalidDefaultsFile{Err: err, Path: path}
+	}
+
+	defaultsFile.Vars, _ = converted.(map[string]any)
+
+	return defaultsFile, nil
+}
+
+// Custom error types
+
+type InvalidDefaultsFile struct {
+	Err  error
+	Path string
+}
+
+func (err InvalidDefaultsFile) Error() string {
+	return fmt.Sprintf("Error parsing defaults file %s: %v", err.Path, err.Err)
+}
+
+func (err InvalidDefaultsFile) Unwrap() error {
+	return err.Err
+}
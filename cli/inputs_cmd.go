@@ -31,8 +31,14 @@ The output schema is:
           "name": "<input_name>",
           "declared_in": "<template_path>",
           "files": ["relative/path/to/file1", ...],
-          "type": "string|bool|int|list|map|...",
-          "description": "<from boilerplate.yml if present>"
+          "type": "string|bool|int|list|map|object|list(object)|list(int)|map(string)|...",
+          "description": "<from boilerplate.yml if present>",
+          "fields": [
+            { "name": "<field_name>", "type": "<field_type>", "description": "..." }
+          ],
+          "when": "<from boilerplate.yml if present>",
+          "computed": true,
+          "sensitive": true
         }
       },
       "files": {
@@ -46,6 +52,16 @@ The output schema is:
       ]
     }
 
+The "fields" array is only present for object and list(object) inputs and
+describes each declared field, recursively for nested objects.
+
+The "when" string is only present for inputs that declare a condition and
+holds the Go template expression that decides whether they are prompted for.
+The "computed" flag is only present for inputs that declare a "value" and
+are derived from other inputs instead of being prompted for.
+The "sensitive" flag is only present for inputs that hold secrets, whose
+values should be masked.
+
 Keys in "inputs" are fully-qualified as <template_path>:<input_name>, where
 <template_path> is "." for the root template and the dependency's
 output-folder path (relative to the root output) for nested templates.
@@ -85,7 +101,7 @@ func newInputsCommand() *cli.Command {
 					},
 					&cli.StringSliceFlag{
 						Name:  options.OptVarFile,
-						Usage: "Load variable values from the YAML file `FILE`. May be specified more than once.",
+						Usage: "Load variable values from the file `FILE`. YAML, JSON, TOML, .env, .tfvars and .tfvars.json files are supported, detected by extension. Use tfoutput:[PREFIX=]FILE to load the output of terraform output -json. May be specified more than once.",
 					},
 					&cli.BoolFlag{
 						Name:  options.OptIncludeBundle,
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/gruntwork-io/boilerplate/getterhelper"
+	"github.com/gruntwork-io/boilerplate/lockfile"
+	"github.com/gruntwork-io/boilerplate/options"
+	"github.com/gruntwork-io/boilerplate/pkg/logging"
+	"github.com/gruntwork-io/boilerplate/templates"
+	"github.com/gruntwork-io/boilerplate/variables"
+)
+
+const lockHelpText = `Usage: boilerplate lock [OPTIONS]
+
+Resolve the remote template at --template-url, and the remote templates of all
+its dependencies, to an immutable source, and write them to a lock file. Git
+sources are locked to the commit their ref points to, and other sources to a
+checksum of their contents. Local templates are not locked.
+
+Later runs of boilerplate use the lock file in the current working directory,
+or the one passed in with --lock-file: git sources are downloaded at the locked
+commit, and it is an error for a remote template not to be in the lock file, or
+not to match its checksum.
+
+The template-url of each dependency is rendered with the variables passed in
+with --var and --var-file, and with the variables in the defaults files, once
+per item for dependencies with for_each. Pass in the same variables as the runs
+that use the lock file, so the same templates are locked.`
+
+func newLockCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "lock",
+		Usage:       "Pin the remote templates of a template and its dependencies in a lock file.",
+		Description: lockHelpText,
+		Action:      runLock,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     options.OptTemplateURL,
+				Usage:    "Lock the remote templates used by the template at `URL`. Same resolution rules as `boilerplate template`.",
+				Required: true,
+			},
+			&cli.StringSliceFlag{
+				Name:  options.OptVar,
+				Usage: "Use `NAME=VALUE` to set variable NAME to VALUE. Used to render the template-url of dependencies. May be specified more than once.",
+			},
+			&cli.StringSliceFlag{
+				Name:  options.OptVarFile,
+				Usage: "Load variable values from the file `FILE`. Used to render the template-url of dependencies. May be specified more than once.",
+			},
+			&cli.StringFlag{
+				Name:  options.OptLockFile,
+				Value: lockfile.DefaultFilename,
+				Usage: "Write the lock file to `FILE`.",
+			},
+		},
+	}
+}
+
+func runLock(c *cli.Context) error {
+	vars, err := variables.ParseVars(c.StringSlice(options.OptVar), c.StringSlice(options.OptVarFile))
+	if err != nil {
+		return err
+	}
+
+	defaults, err := loadDefaultsFiles(".")
+	if err != nil {
+		return err
+	}
+
+	templateURL, templateFolder, err := getterhelper.DetermineTemplateConfig(c.String(options.OptTemplateURL))
+	if err != nil {
+		return err
+	}
+
+	opts := &options.BoilerplateOptions{
+		Vars:             vars,
+		DefaultsFileVars: defaults.Vars,
+		TemplateURL:      templateURL,
+		TemplateFolder:   templateFolder,
+		TrustedTemplates: defaults.TrustedTemplates,
+		NonInteractive:   true,
+		NoHooks:          true,
+		NoShell:          true,
+		OnMissingKey:     options.ExitWithError,
+		OnMissingConfig:  options.Exit,
+	}
+
+	stderr := io.Writer(os.Stderr)
+	if c.App != nil && c.App.ErrWriter != nil {
+		stderr = c.App.ErrWriter
+	}
+
+	lock, err := templates.LockTemplates(context.Background(), logging.New(stderr, logging.LevelInfo), opts)
+	if err != nil {
+		return err
+	}
+
+	path := c.String(options.OptLockFile)
+	if err := lock.Write(path); err != nil {
+		return fmt.Errorf("write lock file %s: %w", path, err)
+	}
+
+	return nil
+}
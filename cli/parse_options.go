@@ -1,16 +1,46 @@
 package cli
 
 import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+
 	"github.com/urfave/cli/v2"
 
 	"github.com/gruntwork-io/boilerplate/getterhelper"
+	"github.com/gruntwork-io/boilerplate/lockfile"
+	"github.com/gruntwork-io/boilerplate/manifest"
 	"github.com/gruntwork-io/boilerplate/options"
 	"github.com/gruntwork-io/boilerplate/variables"
 )
 
 // ParseCLIContext parses the command line context provided by the user and returns the BoilerplateOptions struct.
 func ParseCLIContext(cliContext *cli.Context) (*options.BoilerplateOptions, error) {
-	vars, err := variables.ParseVars(cliContext.StringSlice(options.OptVar), cliContext.StringSlice(options.OptVarFile))
+	vars, sensitiveVars, err := variables.ParseVarsWithSensitive(cliContext.StringSlice(options.OptVar), cliContext.StringSlice(options.OptVarFile))
+	if err != nil {
+		return nil, err
+	}
+
+	// A var file written by --save-answers also records the answers to the prompts for dependencies, hooks and shell
+	// commands, which are not variables
+	decisions, err := variables.ExtractDecisions(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	// Variables set in the defaults files are only used as defaults, so they are not merged into vars
+	defaults, err := loadDefaultsFiles(cliContext.String(options.OptOutputFolder))
+	if err != nil {
+		return nil, err
+	}
+
+	parallelism := cliContext.Int(options.OptParallelism)
+	if defaults.Parallelism > 0 && !cliContext.IsSet(options.OptParallelism) {
+		parallelism = defaults.Parallelism
+	}
+
+	overrides, err := variables.ParseVariableOverrides(cliContext.StringSlice(options.OptSet))
 	if err != nil {
 		return nil, err
 	}
@@ -42,29 +72,88 @@ func ParseCLIContext(cliContext *cli.Context) (*options.BoilerplateOptions, erro
 
 	opts := &options.BoilerplateOptions{
 		Vars:                    vars,
+		SensitiveVars:           sensitiveVars,
+		DefaultsFileVars:        defaults.Vars,
+		Overrides:               overrides,
 		ShellCommandAnswers:     make(map[string]bool),
+		HookAnswers:             make(map[string]bool),
+		DependencyAnswers:       make(map[string]bool),
 		TemplateURL:             templateURL,
 		TemplateFolder:          templateFolder,
 		OutputFolder:            cliContext.String(options.OptOutputFolder),
 		OnMissingKey:            missingKeyAction,
 		OnMissingConfig:         missingConfigAction,
 		NonInteractive:          cliContext.Bool(options.OptNonInteractive),
+		TrustedTemplates:        defaults.TrustedTemplates,
+		StrictVars:              cliContext.Bool(options.OptStrictVars),
 		NoHooks:                 cliContext.Bool(options.OptNoHooks),
 		NoShell:                 cliContext.Bool(options.OptNoShell),
 		DisableDependencyPrompt: cliContext.Bool(options.OptDisableDependencyPrompt),
 		ExecuteAllShellCommands: false,
 		Manifest:                cliContext.Bool(options.OptManifest) || cliContext.String(options.OptManifestFile) != "",
 		ManifestFile:            cliContext.String(options.OptManifestFile),
-		Parallelism:             cliContext.Int(options.OptParallelism),
+		Parallelism:             parallelism,
+	}
+
+	if decisions != nil {
+		maps.Copy(opts.ShellCommandAnswers, decisions.ShellCommands)
+		maps.Copy(opts.HookAnswers, decisions.Hooks)
+		maps.Copy(opts.DependencyAnswers, decisions.Dependencies)
+	}
+
+	if cliContext.String(options.OptSaveAnswers) != "" {
+		opts.SaveAnswers = variables.NewAnswerRecorder()
 	}
 
 	if err := validateOptions(opts); err != nil {
 		return nil, err
 	}
 
+	if cliContext.Bool(options.OptReuseAnswers) {
+		opts.PreviousAnswers, err = loadPreviousAnswers(manifestPathForOptions(opts))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	opts.LockFile, err = loadLockFile(cliContext.String(options.OptLockFile))
+	if err != nil {
+		return nil, err
+	}
+
 	return opts, nil
 }
 
+// loadLockFile loads the lock file at the given path or, if no path is given, the lock file in the current working
+// directory. It is only an error for the lock file not to exist if its path was given.
+func loadLockFile(path string) (*lockfile.LockFile, error) {
+	if path != "" {
+		return lockfile.Load(path)
+	}
+
+	lock, err := lockfile.Load(lockfile.DefaultFilename)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	return lock, err
+}
+
+// loadPreviousAnswers loads the variable values recorded in the manifest at the given path. If there is no manifest,
+// because the template has not been run into this output folder before, there are no previous answers.
+func loadPreviousAnswers(manifestPath string) (*variables.PreviousAnswers, error) {
+	previousManifest, err := manifest.ParseManifestFile(manifestPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, InvalidPreviousManifest{Err: err, Path: manifestPath}
+	}
+
+	return manifest.PreviousAnswers(previousManifest), nil
+}
+
 // validateOptions checks that the options have reasonable values and returns an error if they don't.
 func validateOptions(opts *options.BoilerplateOptions) error {
 	if opts.TemplateURL == "" {
@@ -81,3 +170,31 @@ func validateOptions(opts *options.BoilerplateOptions) error {
 
 	return nil
 }
+
+// Custom error types
+
+type InvalidPreviousManifest struct {
+	Err  error
+	Path string
+}
+
+func (err InvalidPreviousManifest) Error() string {
+	return fmt.Sprintf("Could not read the answers of the previous run from the manifest %s for --%s: %v", err.Path, options.OptReuseAnswers, err.Err)
+}
+
+func (err InvalidPreviousManifest) Unwrap() error {
+	return err.Err
+}
+
+type SaveAnswersError struct {
+	Err  error
+	Path string
+}
+
+func (err SaveAnswersError) Error() string {
+	return fmt.Sprintf("Could not save the answers to %s for --%s: %v", err.Path, options.OptSaveAnswers, err.Err)
+}
+
+func (err SaveAnswersError) Unwrap() error {
+	return err.Err
+}
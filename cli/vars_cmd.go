@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/gruntwork-io/boilerplate/getterhelper"
+	"github.com/gruntwork-io/boilerplate/options"
+	"github.com/gruntwork-io/boilerplate/pkg/logging"
+	"github.com/gruntwork-io/boilerplate/templates"
+	"github.com/gruntwork-io/boilerplate/variables"
+)
+
+const varsExampleHelpText = `Usage: boilerplate vars example [OPTIONS]
+
+Print an example var file for the template at --template-url. The var file is
+YAML and sets every variable of the template and its dependencies, in the order
+boilerplate asks for them, to its default value or to an empty value of the
+right type. Each variable is documented in comments with its description, type,
+options, validations, and whether it is required.
+
+Variables of dependencies are namespaced with the name of the dependency, such
+as "backend.Port". Variables that a dependency inherits from its parent are only
+listed once, and computed variables are listed commented out.
+
+If the template-url of a dependency uses variables, pass them in with --var or
+--var-file to include the variables of that dependency.`
+
+const varsSchemaHelpText = `Usage: boilerplate vars schema [OPTIONS]
+
+Print a JSON Schema for var files of the template at --template-url, which
+editors can use to autocomplete and check var files. The schema describes the
+type, options, default and validations of every variable of the template and
+its dependencies. Validations that JSON Schema can't express, such as expr(),
+are listed in the description of the variable.
+
+Unknown keys are rejected, unless the variables of some dependency could not be
+determined, e.g. because its template-url uses variables that were not passed
+in with --var or --var-file.`
+
+func newVarsCommand() *cli.Command {
+	flags := []cli.Flag{
+		&cli.StringFlag{
+			Name:     options.OptTemplateURL,
+			Usage:    "Document the variables of the template at `URL`. Same resolution rules as `boilerplate template`.",
+			Required: true,
+		},
+		&cli.StringSliceFlag{
+			Name:  options.OptVar,
+			Usage: "Use `NAME=VALUE` to set variable NAME to VALUE. Used to render the template-url of dependencies. May be specified more than once.",
+		},
+		&cli.StringSliceFlag{
+			Name:  options.OptVarFile,
+			Usage: "Load variable values from the file `FILE`. Used to render the template-url of dependencies. May be specified more than once.",
+		},
+	}
+
+	return &cli.Command{
+		Name:  "vars",
+		Usage: "Document the variables of a template.",
+		Subcommands: []*cli.Command{
+			{
+				Name:        "example",
+				Usage:       "Print a commented example var file that sets every variable.",
+				Description: varsExampleHelpText,
+				Action:      runVarsExample,
+				Flags:       flags,
+			},
+			{
+				Name:        "schema",
+				Usage:       "Print a JSON Schema for var files.",
+				Description: varsSchemaHelpText,
+				Action:      runVarsSchema,
+				Flags:       flags,
+			},
+		},
+	}
+}
+
+func runVarsExample(c *cli.Context) error {
+	declared, err := loadDeclaredVariables(c)
+	if err != nil {
+		return err
+	}
+
+	example, err := declared.ExampleVarFile()
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(appWriter(c), example)
+
+	return err
+}
+
+func runVarsSchema(c *cli.Context) error {
+	declared, err := loadDeclaredVariables(c)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(appWriter(c))
+	enc.SetIndent("", "  ")
+
+	if encErr := enc.Encode(declared.VarFileSchema()); encErr != nil {
+		return fmt.Errorf("encode schema: %w", encErr)
+	}
+
+	return nil
+}
+
+// loadDeclaredVariables loads the variables declared in the template passed in via --template-url and its dependencies.
+func loadDeclaredVariables(c *cli.Context) (*templates.DeclaredVariables, error) {
+	vars, err := variables.ParseVars(c.StringSlice(options.OptVar), c.StringSlice(options.OptVarFile))
+	if err != nil {
+		return nil, err
+	}
+
+	templateURL, templateFolder, err := getterhelper.DetermineTemplateConfig(c.String(options.OptTemplateURL))
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &options.BoilerplateOptions{
+		Vars:            vars,
+		TemplateURL:     templateURL,
+		TemplateFolder:  templateFolder,
+		NonInteractive:  true,
+		NoHooks:         true,
+		NoShell:         true,
+		OnMissingKey:    options.ExitWithError,
+		OnMissingConfig: options.Exit,
+	}
+
+	stderr := io.Writer(os.Stderr)
+	if c.App != nil && c.App.ErrWriter != nil {
+		stderr = c.App.ErrWriter
+	}
+
+	return templates.LoadDeclaredVariables(context.Background(), logging.New(stderr, logging.LevelWarn), opts)
+}
+
+// appWriter returns the writer for the output of a command, which tests can inject through c.App.
+func appWriter(c *cli.Context) io.Writer {
+	if c.App != nil && c.App.Writer != nil {
+		return c.App.Writer
+	}
+
+	return os.Stdout
+}
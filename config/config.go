@@ -6,6 +6,7 @@ import (
 	"net/url"
 	"os"
 	"path"
+	"slices"
 	"strings"
 
 	"github.com/gruntwork-io/boilerplate/version"
@@ -30,6 +31,9 @@ type BoilerplateConfig struct {
 	Partials        []string
 	SkipFiles       []variables.SkipFile
 	Engines         []variables.Engine
+	// Outputs are the template expressions, by name, that are rendered after this template, and that the template
+	// that depends on it can read with .Deps.<name>.Outputs
+	Outputs map[string]string
 }
 
 // GetVariablesMap returns a map that maps variable names to the variable config.
@@ -89,6 +93,11 @@ func (config *BoilerplateConfig) UnmarshalYAML(unmarshal func(any) error) error
 		return err
 	}
 
+	outputs, err := variables.UnmarshalMapOfStrings(fields, "outputs")
+	if err != nil {
+		return err
+	}
+
 	*config = BoilerplateConfig{
 		RequiredVersion: requiredVersion,
 		Variables:       vars,
@@ -97,6 +106,7 @@ func (config *BoilerplateConfig) UnmarshalYAML(unmarshal func(any) error) error
 		Partials:        partials,
 		SkipFiles:       skipFiles,
 		Engines:         engines,
+		Outputs:         outputs,
 	}
 
 	return nil
@@ -188,6 +198,10 @@ func (config *BoilerplateConfig) MarshalYAML() (any, error) {
 		configYml["engines"] = enginesYml
 	}
 
+	if len(config.Outputs) > 0 {
+		configYml["outputs"] = config.Outputs
+	}
+
 	return configYml, nil
 }
 
@@ -204,7 +218,14 @@ func LoadBoilerplateConfig(l logging.Logger, opts *options.BoilerplateOptions) (
 			return nil, err
 		}
 
-		return ParseBoilerplateConfig(bytes)
+		boilerplateConfig, err := ParseBoilerplateConfig(bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		markSensitiveVariables(boilerplateConfig, opts.SensitiveVars)
+
+		return boilerplateConfig, nil
 	case opts.OnMissingConfig == options.Ignore:
 		l.Warnf("boilerplate config file not found at %s. The %s flag is set, so ignoring. Note that no variables will be available while generating.", configPath, options.OptMissingConfigAction)
 		return &BoilerplateConfig{}, nil
@@ -214,6 +235,16 @@ func LoadBoilerplateConfig(l logging.Logger, opts *options.BoilerplateOptions) (
 	}
 }
 
+// Mark the variables declared in the given config whose values came from a source that marked them as sensitive, such
+// as the sensitive outputs of `terraform output -json`, as sensitive.
+func markSensitiveVariables(boilerplateConfig *BoilerplateConfig, sensitiveVars []string) {
+	for i, variable := range boilerplateConfig.Variables {
+		if slices.Contains(sensitiveVars, variable.Name()) {
+			boilerplateConfig.Variables[i] = variable.WithSensitive(true)
+		}
+	}
+}
+
 // ParseBoilerplateConfig parses the given configContents as a boilerplate.yml config file.
 func ParseBoilerplateConfig(configContents []byte) (*BoilerplateConfig, error) {
 	boilerplateConfig := &BoilerplateConfig{}
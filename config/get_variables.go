@@ -2,12 +2,16 @@ package config
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"maps"
+	"slices"
 	"sort"
+	"strings"
 
 	"github.com/gruntwork-io/boilerplate/options"
 	"github.com/gruntwork-io/boilerplate/pkg/logging"
+	"github.com/gruntwork-io/boilerplate/prompt"
 	"github.com/gruntwork-io/boilerplate/render"
 	"github.com/gruntwork-io/boilerplate/variables"
 	"github.com/hashicorp/go-multierror"
@@ -27,6 +31,11 @@ func GetVariables(l logging.Logger, opts *options.BoilerplateOptions, boilerplat
 //
 // The value for a variable can come from the user (if the non-interactive option isn't set), the default value in the
 // config, or a command line option.
+//
+// In non-interactive mode, all the variables that are missing a value or have an invalid value are returned together
+// in an InvalidVariables error. In that case, the variables that could be rendered are returned along with the error.
+// Values that don't match the type of their variable are only found once they are rendered, so they are reported in
+// an InvalidVariables error in interactive mode too.
 func GetVariablesWithContext(ctx context.Context, l logging.Logger, opts *options.BoilerplateOptions, boilerplateConfig, rootBoilerplateConfig *BoilerplateConfig, thisDep *variables.Dependency) (map[string]any, error) {
 	renderedVariables := map[string]any{}
 
@@ -64,6 +73,9 @@ func GetVariablesWithContext(ctx context.Context, l logging.Logger, opts *option
 	// Collect the variable values that are defined in the config and get the value.
 	variablesInConfig := boilerplateConfig.GetVariablesMap()
 
+	// Values may still be passed in under the old names of renamed variables
+	applyAliases(l, variablesInConfig, variablesToRender)
+
 	// Prior to prompting the user for the defined variable values, we sort the variables
 	// by user-defined presentation order. Users may specify the order: value when defining
 	// their variables in boilerplate.yml. This order value is an int, and it is used to
@@ -91,41 +103,472 @@ func GetVariablesWithContext(ctx context.Context, l logging.Logger, opts *option
 		return keyAndOrderPairs[i].Order < keyAndOrderPairs[j].Order
 	})
 
+	// Keep the variables in each group together, so they are prompted for under a single heading
+	keyAndOrderPairs = groupKeyAndOrderPairs(keyAndOrderPairs, variablesInConfig)
+
 	// Now, instead of just iterating through the map keys naively,
 	// iterate through the slice of KeyOrderPairs, which are sorted by order
 	// which means that in each iteration of the loop, we can fetch the next variable
 	// by looking up its key in the original config-provided variables map
-	for _, keyOrderPair := range keyAndOrderPairs {
-		variable := variablesInConfig[keyOrderPair.Key]
+	// Keep track of the variables whose "when" condition is false, as their validations don't apply
+	inactiveVariables := map[string]bool{}
+
+	// Collect the variables that are missing or invalid in non-interactive mode
+	invalidVariables := InvalidVariables{}
+
+	// The state before each question the user answered, so they can go back to it
+	answeredQuestions := []answeredQuestion{}
+
+	for i := 0; i < len(keyAndOrderPairs); i++ {
+		variable := variablesInConfig[keyAndOrderPairs[i].Key]
 
-		unmarshalled, err := GetValueForVariable(l, variable, variablesInConfig, variablesToRender, opts, 0)
+		active, err := isVariableActive(ctx, l, opts, variable, variablesInConfig, variablesToRender, renderedVariables)
 		if err != nil {
 			return nil, err
 		}
 
+		if !active {
+			l.Debugf("Condition for variable '%s' evaluated to false, using its default value: %v", variable.FullName(), variables.RedactValue(variable, variable.Default()))
+			variablesToRender[variable.Name()] = variable.Default()
+			inactiveVariables[variable.Name()] = true
+			opts.SaveAnswers.ForgetVariable(variable)
+
+			continue
+		}
+
+		// Options that depend on earlier answers are rendered now, so the prompt and the type conversion use them
+		variable, err = renderVariableOptions(ctx, l, opts, variable, variablesInConfig, variablesToRender, renderedVariables)
+		if err != nil {
+			return nil, err
+		}
+
+		variablesInConfig[variable.Name()] = variable
+
+		before := answeredQuestion{
+			index:             i,
+			variablesToRender: maps.Clone(variablesToRender),
+			inactiveVariables: maps.Clone(inactiveVariables),
+		}
+		state := &promptState{canGoBack: len(answeredQuestions) > 0}
+
+		unmarshalled, err := getValueForVariable(l, variable, variablesInConfig, variablesToRender, opts, 0, state)
+		if errors.Is(err, prompt.ErrGoBack) && len(answeredQuestions) > 0 {
+			// Forget the answer to the previous question, and everything that came after it, and ask it again
+			previous := answeredQuestions[len(answeredQuestions)-1]
+			answeredQuestions = answeredQuestions[:len(answeredQuestions)-1]
+			variablesToRender = previous.variablesToRender
+			inactiveVariables = previous.inactiveVariables
+
+			for _, pair := range keyAndOrderPairs[previous.index:] {
+				opts.SaveAnswers.ForgetVariable(variablesInConfig[pair.Key])
+			}
+
+			i = previous.index - 1
+
+			continue
+		}
+
+		if state.prompted {
+			answeredQuestions = append(answeredQuestions, before)
+		}
+
+		if err != nil {
+			// In non-interactive mode, keep going so that all the missing and invalid variables can be reported at once
+			issue, isIssue := toVariableIssue(variable, err)
+			if !opts.NonInteractive || !isIssue {
+				return nil, err
+			}
+
+			invalidVariables.Issues = append(invalidVariables.Issues, issue)
+		}
+
 		variablesToRender[variable.Name()] = unmarshalled
 	}
 
-	// Pass all the user provided variables through a rendering pipeline to ensure they are evaluated down to
-	// primitives.
+	for {
+		// Even if there are issues already, the variables that do have values are still rendered, so that the caller
+		// can go on to check the variables of dependencies and report all the missing and invalid variables at once.
+		conversionIssues, err := renderAndConvertVariables(ctx, l, opts, variablesInConfig, variablesToRender, renderedVariables)
+		if err != nil && len(invalidVariables.Issues) > 0 {
+			return nil, invalidVariables
+		} else if err != nil {
+			return nil, err
+		}
+
+		invalidVariables.Issues = appendNewIssues(invalidVariables.Issues, conversionIssues)
+
+		// Expression validations can refer to any variable, so they can only be evaluated once all the variables have
+		// their final values. They are skipped for the variables that already have an issue.
+		skipExpressions := maps.Clone(inactiveVariables)
+		for _, issue := range invalidVariables.Issues {
+			skipExpressions[issue.VariableName] = true
+		}
+
+		issues, err := validateExpressions(ctx, l, opts, variablesInConfig, skipExpressions, renderedVariables)
+		if err != nil && len(invalidVariables.Issues) > 0 {
+			// The expression may refer to a variable that is missing or invalid, so report those issues instead
+			l.Debugf("Not checking the remaining expression validations, as they failed to evaluate: %v", err)
+			return renderedVariables, invalidVariables
+		} else if err != nil {
+			return nil, err
+		}
+
+		if len(invalidVariables.Issues) > 0 || (opts.NonInteractive && len(issues) > 0) {
+			invalidVariables.Issues = append(invalidVariables.Issues, expressionValidationIssues(issues)...)
+			return renderedVariables, invalidVariables
+		}
+
+		if len(issues) == 0 {
+			break
+		}
+
+		// Prompt the user again for each variable that failed an expression validation, showing which ones failed
+		for _, name := range slices.Sorted(maps.Keys(issues)) {
+			variable := variablesInConfig[name]
+			if variable.Value() != nil {
+				return nil, InvalidVariables{Issues: expressionValidationIssues(map[string]variables.ValidationIssue{name: issues[name]})}
+			}
+
+			value, err := getVariableFromUser(l, opts.GetPrompter(), variable, variables.InvalidEntries{Issues: []variables.ValidationIssue{issues[name]}}, false)
+			if err != nil {
+				return nil, err
+			}
+
+			opts.SaveAnswers.RecordVariable(variable, value)
+			variablesToRender[name] = value
+		}
+	}
+
+	return renderedVariables, nil
+}
+
+// applyAliases moves each value in the given vars that was passed in under an alias of one of the given variables to
+// the name of that variable, with a warning, unless a value was also passed in under its name, which takes precedence.
+// It also warns about each deprecated variable that a value was passed in for.
+func applyAliases(l logging.Logger, variablesInConfig map[string]variables.Variable, vars map[string]any) {
+	for _, name := range slices.Sorted(maps.Keys(variablesInConfig)) {
+		variable := variablesInConfig[name]
+
+		for _, alias := range variable.Aliases() {
+			value, hasValue := vars[alias]
+			if !hasValue {
+				continue
+			}
+
+			delete(vars, alias)
+
+			if _, alreadySet := vars[name]; alreadySet {
+				l.Warnf("Ignoring the value passed in for variable '%s' under its old name '%s', as a value was also passed in under its new name", variable.FullName(), alias)
+				continue
+			}
+
+			l.Warnf("Variable '%s' was renamed to '%s'. Please pass in its value under the new name.", alias, name)
+
+			vars[name] = value
+		}
+
+		if _, hasValue := vars[name]; hasValue && variable.Deprecated() != "" {
+			l.Warnf("Variable '%s' is deprecated: %s", variable.FullName(), variable.Deprecated())
+		}
+	}
+}
+
+// lookupPreviousAnswer returns the answer recorded for the given variable in the manifest of a previous run, under its
+// name or, with a warning, under one of its aliases.
+func lookupPreviousAnswer(l logging.Logger, variable variables.Variable, previousAnswers *variables.PreviousAnswers) (any, bool) {
+	if answer, hasAnswer := previousAnswers.Lookup(variable.Name()); hasAnswer {
+		return answer, true
+	}
+
+	for _, alias := range variable.Aliases() {
+		if answer, hasAnswer := previousAnswers.Lookup(alias); hasAnswer {
+			l.Warnf("Using the answer from the previous run for variable '%s', which was recorded under its old name '%s'", variable.FullName(), alias)
+			return answer, true
+		}
+	}
+
+	return nil, false
+}
+
+// answeredQuestion is the state of GetVariablesWithContext before the user answered the question at index, which it
+// goes back to when the user asks to go back to that question.
+type answeredQuestion struct {
+	variablesToRender map[string]any
+	inactiveVariables map[string]bool
+	index             int
+}
+
+// groupKeyAndOrderPairs returns the given pairs, sorted by order, with the variables of each group moved up to right
+// after the first variable of that group, so that the variables of a group are prompted for together. Otherwise, the
+// order of the variables is kept.
+func groupKeyAndOrderPairs(keyAndOrderPairs []KeyAndOrderPair, variablesInConfig map[string]variables.Variable) []KeyAndOrderPair {
+	grouped := make([]KeyAndOrderPair, 0, len(keyAndOrderPairs))
+	seenGroups := map[string]bool{}
+
+	for _, pair := range keyAndOrderPairs {
+		group := variablesInConfig[pair.Key].Group()
+
+		switch {
+		case group == "":
+			grouped = append(grouped, pair)
+		case !seenGroups[group]:
+			seenGroups[group] = true
+
+			for _, other := range keyAndOrderPairs {
+				if variablesInConfig[other.Key].Group() == group {
+					grouped = append(grouped, other)
+				}
+			}
+		}
+	}
+
+	return grouped
+}
+
+// renderAndConvertVariables passes all the user provided variables through a rendering pipeline to ensure they are
+// evaluated down to primitives, converts them to match the type definition in the boilerplate config, and stores them
+// in renderedVariables. Each variable whose value doesn't match its type, such as a value that is not one of the
+// options of an enum, is returned as an issue, sorted by variable name, and stored as rendered.
+func renderAndConvertVariables(
+	ctx context.Context,
+	l logging.Logger,
+	opts *options.BoilerplateOptions,
+	variablesInConfig map[string]variables.Variable,
+	variablesToRender map[string]any,
+	renderedVariables map[string]any,
+) ([]VariableIssue, error) {
 	newlyRenderedVariables, err := render.RenderVariablesWithContext(ctx, l, opts, variablesToRender, renderedVariables)
 	if err != nil {
 		return nil, err
 	}
 
-	// Convert all the rendered variables to match the type definition in the boilerplate config.
-	for _, variable := range variablesInConfig {
-		renderedValue := newlyRenderedVariables[variable.Name()]
+	var issues []VariableIssue
 
-		renderedValueWithType, err := variables.ConvertType(renderedValue, variable)
+	for _, name := range slices.Sorted(maps.Keys(variablesInConfig)) {
+		renderedValue := newlyRenderedVariables[name]
+
+		renderedValueWithType, err := variables.ConvertType(renderedValue, variablesInConfig[name])
 		if err != nil {
-			return nil, err
+			issues = append(issues, VariableIssue{VariableName: name, Err: err})
+			renderedVariables[name] = renderedValue
+
+			continue
 		}
 
-		renderedVariables[variable.Name()] = renderedValueWithType
+		renderedVariables[name] = renderedValueWithType
 	}
 
-	return renderedVariables, nil
+	return issues, nil
+}
+
+// appendNewIssues appends the given new issues to the given issues, other than those for variables that already have
+// an issue, as a value that fails its validations often can't be converted to its type either.
+func appendNewIssues(issues []VariableIssue, newIssues []VariableIssue) []VariableIssue {
+	for _, newIssue := range newIssues {
+		hasIssue := slices.ContainsFunc(issues, func(issue VariableIssue) bool {
+			return issue.VariableName == newIssue.VariableName
+		})
+
+		if !hasIssue {
+			issues = append(issues, newIssue)
+		}
+	}
+
+	return issues
+}
+
+// validateExpressions evaluates the expr() validations of each variable, other than the given variables to skip,
+// against the rendered values of all the variables. The result has a validation issue for each variable with at least
+// one expression that doesn't evaluate to "true".
+func validateExpressions(
+	ctx context.Context,
+	l logging.Logger,
+	opts *options.BoilerplateOptions,
+	variablesInConfig map[string]variables.Variable,
+	skipVariables map[string]bool,
+	renderedVariables map[string]any,
+) (map[string]variables.ValidationIssue, error) {
+	issues := map[string]variables.ValidationIssue{}
+
+	for _, name := range slices.Sorted(maps.Keys(variablesInConfig)) {
+		variable := variablesInConfig[name]
+		if skipVariables[name] {
+			continue
+		}
+
+		validationMap := map[string]bool{}
+		hasFailures := false
+
+		for _, customValidation := range variable.Validations() {
+			if !customValidation.IsExpression() {
+				continue
+			}
+
+			rendered, err := render.RenderTemplateFromStringWithContext(ctx, l, opts.TemplateFolder, customValidation.Expression, renderedVariables, opts)
+			if err != nil {
+				return nil, InvalidValidationExpression{VariableName: name, Expression: customValidation.Expression, Err: err}
+			}
+
+			passed := strings.TrimSpace(rendered) == "true"
+			if !passed {
+				hasFailures = true
+			}
+
+			validationMap[customValidation.DescriptionText()] = passed
+		}
+
+		if hasFailures {
+			issues[name] = variables.ValidationIssue{
+				Value:         variables.RedactValue(variable, renderedVariables[name]),
+				ValidationMap: validationMap,
+			}
+		}
+	}
+
+	return issues, nil
+}
+
+// expressionValidationIssues converts the given expression validation issues, keyed by variable name, to variable
+// issues, sorted by variable name.
+func expressionValidationIssues(issues map[string]variables.ValidationIssue) []VariableIssue {
+	result := []VariableIssue{}
+
+	for _, name := range slices.Sorted(maps.Keys(issues)) {
+		var failed []string
+
+		for description, passed := range issues[name].ValidationMap {
+			if !passed {
+				failed = append(failed, description)
+			}
+		}
+
+		slices.Sort(failed)
+
+		result = append(result, VariableIssue{
+			VariableName: name,
+			Err:          ExpressionValidationFailed{VariableName: name, Failed: failed},
+		})
+	}
+
+	return result
+}
+
+// toVariableIssue returns the issue to report for the given error from GetValueForVariable, if the error means the
+// variable is missing a value or has an invalid value.
+func toVariableIssue(variable variables.Variable, err error) (VariableIssue, bool) {
+	var missingErr MissingVariableWithNonInteractiveMode
+	if errors.As(err, &missingErr) {
+		return VariableIssue{VariableName: variable.Name(), Missing: true, Err: err}, true
+	}
+
+	var validationErr VariableValidationFailed
+	if errors.As(err, &validationErr) {
+		return VariableIssue{VariableName: variable.Name(), Err: err}, true
+	}
+
+	return VariableIssue{}, false
+}
+
+// isVariableActive returns true if the given variable should be prompted for, which is the case if it has no "when"
+// condition or if its condition evaluates to "true". The condition is rendered against the builtin variables plus the
+// values of the variables resolved so far, converted to their declared types.
+func isVariableActive(
+	ctx context.Context,
+	l logging.Logger,
+	opts *options.BoilerplateOptions,
+	variable variables.Variable,
+	variablesInConfig map[string]variables.Variable,
+	valuesForPreviousVariables map[string]any,
+	builtinVariables map[string]any,
+) (bool, error) {
+	if variable.When() == "" {
+		return true, nil
+	}
+
+	conditionVariables, err := renderPreviousVariables(ctx, l, opts, variablesInConfig, valuesForPreviousVariables, builtinVariables)
+	if err != nil {
+		return false, InvalidVariableCondition{VariableName: variable.Name(), Condition: variable.When(), Err: err}
+	}
+
+	rendered, err := render.RenderTemplateFromStringWithContext(ctx, l, opts.TemplateFolder, variable.When(), conditionVariables, opts)
+	if err != nil {
+		return false, InvalidVariableCondition{VariableName: variable.Name(), Condition: variable.When(), Err: err}
+	}
+
+	l.Debugf("Condition for variable '%s' evaluated to '%s'", variable.FullName(), rendered)
+
+	return strings.TrimSpace(rendered) == "true", nil
+}
+
+// renderVariableOptions returns a copy of the given variable with the options that its options expression renders to,
+// or the variable itself if its options are static. Like a "when" condition, the expression is rendered against the
+// builtin variables plus the values of the variables resolved so far, converted to their declared types.
+func renderVariableOptions(
+	ctx context.Context,
+	l logging.Logger,
+	opts *options.BoilerplateOptions,
+	variable variables.Variable,
+	variablesInConfig map[string]variables.Variable,
+	valuesForPreviousVariables map[string]any,
+	builtinVariables map[string]any,
+) (variables.Variable, error) {
+	if variable.OptionsExpression() == "" {
+		return variable, nil
+	}
+
+	optionsVariables, err := renderPreviousVariables(ctx, l, opts, variablesInConfig, valuesForPreviousVariables, builtinVariables)
+	if err != nil {
+		return nil, InvalidOptionsExpression{VariableName: variable.Name(), Expression: variable.OptionsExpression(), Err: err}
+	}
+
+	rendered, err := render.RenderTemplateFromStringWithContext(ctx, l, opts.TemplateFolder, variable.OptionsExpression(), optionsVariables, opts)
+	if err != nil {
+		return nil, InvalidOptionsExpression{VariableName: variable.Name(), Expression: variable.OptionsExpression(), Err: err}
+	}
+
+	renderedOptions, err := variables.ParseRenderedOptions(rendered)
+	if err != nil {
+		return nil, InvalidOptionsExpression{VariableName: variable.Name(), Expression: variable.OptionsExpression(), Err: err}
+	}
+
+	if len(renderedOptions) == 0 || len(renderedOptions) < variable.MinSelections() {
+		return nil, NotEnoughRenderedOptions{VariableName: variable.Name(), Expression: variable.OptionsExpression(), Options: renderedOptions, MinSelections: variable.MinSelections()}
+	}
+
+	l.Debugf("Options for variable '%s' rendered to %v", variable.FullName(), renderedOptions)
+
+	return variables.WithRenderedOptions(variable, renderedOptions), nil
+}
+
+// renderPreviousVariables returns the builtin variables plus the given values of the variables resolved so far,
+// rendered and converted to their declared types, for evaluating the expressions of the next variable.
+func renderPreviousVariables(
+	ctx context.Context,
+	l logging.Logger,
+	opts *options.BoilerplateOptions,
+	variablesInConfig map[string]variables.Variable,
+	valuesForPreviousVariables map[string]any,
+	builtinVariables map[string]any,
+) (map[string]any, error) {
+	renderedPreviousVariables, err := render.RenderVariablesWithContext(ctx, l, opts, valuesForPreviousVariables, builtinVariables)
+	if err != nil {
+		return nil, err
+	}
+
+	previousVariables := map[string]any{}
+	maps.Copy(previousVariables, builtinVariables)
+
+	for name, value := range renderedPreviousVariables {
+		if previousVariable, isDeclared := variablesInConfig[name]; isDeclared {
+			if converted, convertErr := variables.ConvertType(value, previousVariable); convertErr == nil {
+				value = converted
+			}
+		}
+
+		previousVariables[name] = value
+	}
+
+	return previousVariables, nil
 }
 
 func GetValueForVariable(
@@ -135,12 +578,55 @@ func GetValueForVariable(
 	valuesForPreviousVariables map[string]any,
 	opts *options.BoilerplateOptions,
 	referenceDepth int,
+) (any, error) {
+	return getValueForVariable(l, variable, variablesInConfig, valuesForPreviousVariables, opts, referenceDepth, &promptState{})
+}
+
+// promptState tracks whether the user is prompted while getting the value for a variable.
+type promptState struct {
+	// canGoBack is true if the user can go back to the previous question from the prompt for the variable
+	canGoBack bool
+	// prompted is set to true if the user was prompted for the value of the variable
+	prompted bool
+}
+
+func getValueForVariable(
+	l logging.Logger,
+	variable variables.Variable,
+	variablesInConfig map[string]variables.Variable,
+	valuesForPreviousVariables map[string]any,
+	opts *options.BoilerplateOptions,
+	referenceDepth int,
+	state *promptState,
 ) (any, error) {
 	if referenceDepth > MaxReferenceDepth {
 		return nil, CyclicalReference{VariableName: variable.Name(), ReferenceName: variable.Reference()}
 	}
 
+	if variable.Value() != nil {
+		return getComputedValue(l, variable, valuesForPreviousVariables), nil
+	}
+
+	// Values set in defaults files only stand in for a default the variable doesn't declare
+	if fallback, hasFallback := opts.DefaultsFileVars[variable.Name()]; hasFallback {
+		variable = variables.WithFallbackDefault(variable, fallback)
+	}
+
+	if answer, hasAnswer := lookupPreviousAnswer(l, variable, opts.PreviousAnswers); hasAnswer {
+		l.Debugf("Using the answer from the previous run as the default for variable '%s'", variable.FullName())
+		variable = variables.WithPreviousAnswer(variable, answer)
+	}
+
+	if overrides := variables.OverridesForVariable(opts.Overrides, variable.Name()); len(overrides) > 0 {
+		return getOverriddenValue(l, variable, valuesForPreviousVariables, overrides)
+	}
+
 	value, alreadyExists := valuesForPreviousVariables[variable.Name()]
+	if alreadyExists && opts.NonInteractive {
+		// Values passed in via --var or --var-file never go through a prompt, so check them here
+		return value, validateVariableValue(value, variable)
+	}
+
 	if alreadyExists {
 		return value, nil
 	}
@@ -156,45 +642,194 @@ func GetValueForVariable(
 			return nil, MissingReference{VariableName: variable.Name(), ReferenceName: variable.Reference()}
 		}
 
-		return GetValueForVariable(l, reference, variablesInConfig, valuesForPreviousVariables, opts, referenceDepth+1)
+		return getValueForVariable(l, reference, variablesInConfig, valuesForPreviousVariables, opts, referenceDepth+1, state)
 	}
 
 	// Run the value we receive from getVariable through validations, ensuring values provided by --var-files will also be checked
-	value, err := getVariable(l, variable, opts)
+	value, err := getVariable(l, variable, opts, state)
 	if err != nil {
 		return value, err
 	}
 
+	return value, validateVariableValue(value, variable)
+}
+
+// getOverriddenValue returns the value of a variable that has values passed in for it via --set. These are merged into
+// the value passed in via --var or --var-file or, if there is none, the default value, so the user is not prompted for
+// the variable.
+func getOverriddenValue(l logging.Logger, variable variables.Variable, valuesForPreviousVariables map[string]any, overrides []variables.VariableOverride) (any, error) {
+	value, alreadyExists := valuesForPreviousVariables[variable.Name()]
+	if !alreadyExists {
+		value = variable.Default()
+	}
+
+	value, err := variables.ApplyVariableOverrides(variable.Name(), value, overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	l.Debugf("Using value specified via --set for variable '%s': %v", variable.FullName(), variables.RedactValue(variable, value))
+
+	return value, validateVariableValue(value, variable)
+}
+
+// getComputedValue returns the value of a computed variable. The value passed in for the variable via --var or
+// --var-file, if any, is only used if the variable is overridable; otherwise it is ignored with a warning. The computed value may contain Go template syntax,
+// which is rendered along with all the other variable values.
+func getComputedValue(l logging.Logger, variable variables.Variable, valuesForPreviousVariables map[string]any) any {
+	value, alreadyExists := valuesForPreviousVariables[variable.Name()]
+
+	switch {
+	case alreadyExists && variable.Overridable():
+		l.Debugf("Using value specified via command line options for computed variable '%s': %v", variable.FullName(), variables.RedactValue(variable, value))
+		return value
+	case alreadyExists:
+		l.Warnf("Ignoring the value passed in for variable '%s', as it is computed from its value expression and is not overridable", variable.FullName())
+	}
+
+	return variable.Value()
+}
+
+// validateVariableValue runs the value through any defined validations for the variable. For object and list(object)
+// variables, the validations defined on each field are also run against that field of every object in the value.
+// Any failures are returned as a VariableValidationFailed error.
+func validateVariableValue(value any, variable variables.Variable) error {
+	if err := runVariableValidations(value, variable); err != nil {
+		return VariableValidationFailed{VariableName: variable.FullName(), Err: err}
+	}
+
+	return nil
+}
+
+// runVariableValidations runs the validations of validateVariableValue and returns the failures as a multierror.
+func runVariableValidations(value any, variable variables.Variable) error {
 	var result *multierror.Error
-	// Run the value through any defined validations for the variable
+
 	for _, customValidation := range variable.Validations() {
 		// Run the specific validation against the user-provided value and store it in the map
-		err := runValidator(value, customValidation.Validator)
-		result = multierror.Append(result, err)
+		for _, valueToValidate := range valuesToValidate(value, variable) {
+			result = multierror.Append(result, runValidator(valueToValidate, customValidation.Validator))
+		}
 	}
 
-	return value, result.ErrorOrNil()
+	if variable.Type().IsObject() {
+		result = multierror.Append(result, validateObjectFields(value, variable))
+	}
+
+	if result != nil {
+		result.ErrorFormat = joinErrors
+	}
+
+	return result.ErrorOrNil()
+}
+
+// joinErrors formats the errors of a multierror on a single line, so that they fit in the list of invalid variables.
+func joinErrors(errs []error) string {
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// valuesToValidate returns the values that the validations of the given variable apply to. Validations on typed lists
+// and maps, such as list(int) or map(string), apply to each element, while all other validations apply to the value as
+// a whole.
+func valuesToValidate(value any, variable variables.Variable) []any {
+	elementType := variable.Type().ElementType()
+	if elementType == "" || variable.Type().IsObject() {
+		return []any{value}
+	}
+
+	converted, err := variables.ConvertType(value, variable)
+	if err != nil {
+		// Type errors are reported when the value is converted, so there is nothing to validate here.
+		return nil
+	}
+
+	switch typed := converted.(type) {
+	case []any:
+		return typed
+	case map[string]any:
+		elements := make([]any, 0, len(typed))
+		for _, key := range slices.Sorted(maps.Keys(typed)) {
+			elements = append(elements, typed[key])
+		}
+
+		return elements
+	default:
+		return nil
+	}
+}
+
+// validateObjectFields runs the validations of each field declared on the given object or list(object) variable
+// against the corresponding field of the value, after applying field defaults.
+func validateObjectFields(value any, variable variables.Variable) error {
+	converted, err := variables.ConvertType(value, variable)
+	if err != nil || converted == nil {
+		// The value may still contain template syntax that has not been rendered yet. Type errors are reported when
+		// the rendered value is converted, so there is nothing to validate here.
+		return nil
+	}
+
+	objectsByPath := map[string]any{variable.Name(): converted}
+
+	if asList, isList := converted.([]any); isList {
+		objectsByPath = map[string]any{}
+		for i, object := range asList {
+			objectsByPath[fmt.Sprintf("%s[%d]", variable.Name(), i)] = object
+		}
+	}
+
+	var result *multierror.Error
+
+	for _, objectPath := range slices.Sorted(maps.Keys(objectsByPath)) {
+		asMap, isMap := objectsByPath[objectPath].(map[string]any)
+		if !isMap {
+			continue
+		}
+
+		for _, field := range variable.Fields() {
+			if fieldErr := runVariableValidations(asMap[field.Name()], field); fieldErr != nil {
+				result = multierror.Append(result, InvalidObjectField{FieldPath: objectPath + "." + field.Name(), Err: fieldErr})
+			}
+		}
+	}
+
+	if result != nil {
+		result.ErrorFormat = joinErrors
+	}
+
+	return result.ErrorOrNil()
 }
 
 // Get a value for the given variable. The value can come from the user (if the non-interactive option isn't set), the
 // default value in the config, or a command line option.
-func getVariable(l logging.Logger, variable variables.Variable, opts *options.BoilerplateOptions) (any, error) {
+func getVariable(l logging.Logger, variable variables.Variable, opts *options.BoilerplateOptions, state *promptState) (any, error) {
 	valueFromVars, valueSpecifiedInVars := getVariableFromVars(variable, opts)
 
 	switch {
 	case valueSpecifiedInVars:
-		l.Debugf("Using value specified via command line options for variable '%s': %s", variable.FullName(), valueFromVars)
+		l.Debugf("Using value specified via command line options for variable '%s': %v", variable.FullName(), variables.RedactValue(variable, valueFromVars))
 		return valueFromVars, nil
 	case opts.NonInteractive && variable.Default() != nil:
-		l.Debugf("Using default value for variable '%s': %v", variable.FullName(), variable.Default())
+		l.Debugf("Using default value for variable '%s': %v", variable.FullName(), variables.RedactValue(variable, variable.Default()))
 		return variable.Default(), nil
 	case opts.NonInteractive:
 		return nil, MissingVariableWithNonInteractiveMode(variable.FullName())
 	case variable.Default() != nil && !variable.Confirm():
-		l.Debugf("Using default value for variable '%s': %v", variable.FullName(), variable.Default())
+		l.Debugf("Using default value for variable '%s': %v", variable.FullName(), variables.RedactValue(variable, variable.Default()))
 		return variable.Default(), nil
 	default:
-		return getVariableFromUser(l, variable, variables.InvalidEntries{})
+		state.prompted = true
+
+		value, err := getVariableFromUser(l, opts.GetPrompter(), variable, variables.InvalidEntries{}, state.canGoBack)
+		if err == nil {
+			opts.SaveAnswers.RecordVariable(variable, value)
+		}
+
+		return value, err
 	}
 }
 
@@ -241,6 +876,149 @@ func (err CyclicalReference) Error() string {
 	return fmt.Sprintf("Variable %s seems to have an cyclical reference with variable %s", err.VariableName, err.ReferenceName)
 }
 
+type InvalidObjectField struct {
+	Err       error
+	FieldPath string
+}
+
+func (err InvalidObjectField) Error() string {
+	return fmt.Sprintf("Field %s is invalid: %v", err.FieldPath, err.Err)
+}
+
+func (err InvalidObjectField) Unwrap() error {
+	return err.Err
+}
+
+type InvalidVariableCondition struct {
+	Err          error
+	VariableName string
+	Condition    string
+}
+
+func (err InvalidVariableCondition) Error() string {
+	return fmt.Sprintf("Failed to evaluate the when condition '%s' of variable %s: %v", err.Condition, err.VariableName, err.Err)
+}
+
+func (err InvalidVariableCondition) Unwrap() error {
+	return err.Err
+}
+
+type InvalidOptionsExpression struct {
+	Err          error
+	VariableName string
+	Expression   string
+}
+
+func (err InvalidOptionsExpression) Error() string {
+	return fmt.Sprintf("Failed to render the options '%s' of variable %s: %v", err.Expression, err.VariableName, err.Err)
+}
+
+func (err InvalidOptionsExpression) Unwrap() error {
+	return err.Err
+}
+
+type NotEnoughRenderedOptions struct {
+	VariableName  string
+	Expression    string
+	Options       []string
+	MinSelections int
+}
+
+func (err NotEnoughRenderedOptions) Error() string {
+	if len(err.Options) == 0 {
+		return fmt.Sprintf("The options '%s' of variable %s rendered to an empty list. There must be at least one option.", err.Expression, err.VariableName)
+	}
+
+	return fmt.Sprintf("The options '%s' of variable %s rendered to %v, which is fewer than its min_selections of %d.", err.Expression, err.VariableName, err.Options, err.MinSelections)
+}
+
+type InvalidValidationExpression struct {
+	Err          error
+	VariableName string
+	Expression   string
+}
+
+func (err InvalidValidationExpression) Error() string {
+	return fmt.Sprintf("Failed to evaluate the validation expression '%s' of variable %s: %v", err.Expression, err.VariableName, err.Err)
+}
+
+func (err InvalidValidationExpression) Unwrap() error {
+	return err.Err
+}
+
+// InvalidVariables is returned in non-interactive mode when variables are missing a value or have a value that fails
+// their validations. It collects every such variable, including those of dependencies, so they can all be fixed at
+// once.
+type InvalidVariables struct {
+	Issues []VariableIssue
+}
+
+// VariableIssue describes a single variable that is missing a value or has an invalid value. The names of the
+// variables of dependencies are prefixed with the dependency name, using the same syntax as --var.
+type VariableIssue struct {
+	Err          error
+	VariableName string
+	Missing      bool
+}
+
+func (err InvalidVariables) Error() string {
+	lines := make([]string, 0, len(err.Issues)+1)
+	lines = append(lines, fmt.Sprintf("%d variable(s) are missing or invalid:", len(err.Issues)))
+
+	for _, issue := range err.Issues {
+		problem := "invalid"
+		if issue.Missing {
+			problem = "missing"
+		}
+
+		lines = append(lines, fmt.Sprintf("  - %s (%s): %v", issue.VariableName, problem, issue.Err))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (err InvalidVariables) Unwrap() []error {
+	errs := make([]error, 0, len(err.Issues))
+	for _, issue := range err.Issues {
+		errs = append(errs, issue.Err)
+	}
+
+	return errs
+}
+
+// ForDependency returns a copy of these issues with the variable names prefixed with the given dependency name.
+func (err InvalidVariables) ForDependency(dependencyName string) InvalidVariables {
+	issues := make([]VariableIssue, 0, len(err.Issues))
+	for _, issue := range err.Issues {
+		issue.VariableName = dependencyName + "." + issue.VariableName
+		issues = append(issues, issue)
+	}
+
+	return InvalidVariables{Issues: issues}
+}
+
+type VariableValidationFailed struct {
+	Err          error
+	VariableName string
+}
+
+func (err VariableValidationFailed) Error() string {
+	return fmt.Sprintf("Value of variable %s is invalid: %v", err.VariableName, err.Err)
+}
+
+func (err VariableValidationFailed) Unwrap() error {
+	return err.Err
+}
+
+type ExpressionValidationFailed struct {
+	VariableName string
+	Failed       []string
+}
+
+func (err ExpressionValidationFailed) Error() string {
+	return fmt.Sprintf("Value of variable %s is invalid: %s", err.VariableName, strings.Join(err.Failed, "; "))
+}
+
 type UnsupportedManualInputType struct {
 	VariableName string
 	Type         string
@@ -3,18 +3,28 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 
-	"github.com/AlecAivazis/survey/v2"
 	ozzo "github.com/go-ozzo/ozzo-validation"
+	"github.com/hashicorp/go-multierror"
+	"gopkg.in/yaml.v3"
 
 	"github.com/gruntwork-io/boilerplate/internal/color"
 	"github.com/gruntwork-io/boilerplate/pkg/logging"
+	"github.com/gruntwork-io/boilerplate/prompt"
+	"github.com/gruntwork-io/boilerplate/util"
 	"github.com/gruntwork-io/boilerplate/variables"
 )
 
-// Get the value for the given variable by prompting the user.
-func getVariableFromUser(l logging.Logger, variable variables.Variable, invalidEntries variables.InvalidEntries) (any, error) {
+// Get the value for the given variable by prompting the user with the given prompter. If canGoBack is true, the user
+// can go back to the previous question, in which case prompt.ErrGoBack is returned.
+func getVariableFromUser(l logging.Logger, prompter prompt.Prompter, variable variables.Variable, invalidEntries variables.InvalidEntries, canGoBack bool) (any, error) {
+	prompter.Group(variable.Group())
+
 	// Add a newline for legibility and padding
 	fmt.Println()
 
@@ -22,7 +32,7 @@ func getVariableFromUser(l logging.Logger, variable variables.Variable, invalidE
 	// with their input
 	renderVariablePrompts(variable, invalidEntries)
 
-	value, err := getUserInput(variable)
+	value, err := getUserInput(prompter, variable, canGoBack)
 	if err != nil {
 		return value, err
 	}
@@ -35,59 +45,207 @@ func getVariableFromUser(l logging.Logger, variable variables.Variable, invalidE
 		ie := variables.InvalidEntries{
 			Issues: []variables.ValidationIssue{
 				{
-					Value:         value,
+					Value:         variables.RedactValue(variable, value),
 					ValidationMap: validationMap,
 				},
 			},
 		}
 
-		return getVariableFromUser(l, variable, ie)
+		return getVariableFromUser(l, prompter, variable, ie, canGoBack)
 	}
 
 	if value == "" {
 		// TODO: what if the user wanted an empty string instead of the default?
-		l.Debugf("Using default value for variable '%s': %v", variable.FullName(), variable.Default())
+		l.Debugf("Using default value for variable '%s': %v", variable.FullName(), variables.RedactValue(variable, variable.Default()))
 		return variable.Default(), nil
 	}
 
 	return value, nil
 }
 
-func getUserInput(variable variables.Variable) (string, error) {
-	// Display rich prompts to the user, based on the type of variable we're asking for
-	value := ""
+// Ask the user for the value of the given variable, with the prompt that suits its type: a select prompt for enums, a
+// yes or no toggle for bools, a multi-select prompt for lists whose elements must be one of a set of options, several
+// lines of input for other lists, maps and objects, and a single line of input otherwise. Returns an empty string if
+// the user entered nothing.
+func getUserInput(prompter prompt.Prompter, variable variables.Variable, canGoBack bool) (any, error) {
+	question := prompt.Question{
+		Name:      variable.Name(),
+		Sensitive: variable.Sensitive(),
+		CanGoBack: canGoBack,
+		Validate: func(answer any) error {
+			return inlineValidationError(answer, variable)
+		},
+	}
 
-	var prompt survey.Prompt
+	if variable.Default() != nil {
+		question.Default = fmt.Sprint(variable.Default())
+	}
+
+	switch variable.Type().BaseType() {
+	case variables.Enum:
+		question.Message = "Please select " + variable.FullName()
+		question.Options = variable.Options()
 
-	switch variable.Type() {
-	case variables.String, variables.Int, variables.Float, variables.Bool, variables.List, variables.Map:
-		msg := fmt.Sprintf("Enter a value [type %s]", variable.Type())
-		if variable.Default() != nil {
-			msg = fmt.Sprintf("%s (default: %v)", msg, variable.Default())
+		return prompter.Select(question)
+	case variables.Bool:
+		question.Message = variable.FullName() + "?"
+
+		confirmed, err := prompter.Confirm(question)
+		if err != nil {
+			return nil, err
 		}
 
-		prompt = &survey.Input{Message: msg}
-	case variables.Enum:
-		prompt = &survey.Select{
-			Message: "Please select " + variable.FullName(),
-			Options: variable.Options(),
+		return confirmed, nil
+	case variables.List:
+		if options := listElementOptions(variable); len(options) > 0 {
+			question.Message = "Select the values of " + variable.FullName()
+			question.Options = options
+			question.Defaults = util.ToStringList(toAnyList(variable.Default()))
+
+			selected, err := prompter.MultiSelect(question)
+			if err != nil {
+				return nil, err
+			}
+
+			return toAnyList(selected), nil
+		}
+
+		question.Message = inputMessage(variable, "one item per line, then an empty line to finish")
+		question.Validate = func(answer any) error {
+			return inlineValidationError(parseMultilineInput(answer, variable), variable)
 		}
+
+		answer, err := prompter.Multiline(question)
+
+		return parseMultilineInput(answer, variable), err
+	case variables.Map, variables.Object, variables.ListOfObjects:
+		question.Message = inputMessage(variable, "YAML, then an empty line to finish")
+		question.Validate = func(answer any) error {
+			return inlineValidationError(parseMultilineInput(answer, variable), variable)
+		}
+
+		answer, err := prompter.Multiline(question)
+
+		return parseMultilineInput(answer, variable), err
+	case variables.String, variables.Int, variables.Float:
+		question.Message = inputMessage(variable, "")
+
+		return prompter.Input(question)
 	default:
 		if variable.Default() == nil {
-			return value, UnsupportedManualInputType{VariableName: variable.FullName(), Type: string(variable.Type())}
+			return "", UnsupportedManualInputType{VariableName: variable.FullName(), Type: string(variable.Type())}
 		}
 
-		return value, nil
+		return "", nil
 	}
+}
 
-	if err := survey.AskOne(prompt, &value); err != nil {
-		return value, err
+// Parse the lines the user entered for a list, map or object variable into its value. Lists take one item per line,
+// optionally prefixed with "- ", and maps and objects take YAML. Input that starts with "[" or "{" is returned as is,
+// so it is parsed like a value passed in via --var, and so is input that isn't valid YAML, so that it fails validation.
+func parseMultilineInput(answer any, variable variables.Variable) any {
+	text, isString := answer.(string)
+	if !isString {
+		return answer
 	}
 
-	return value, nil
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" || strings.HasPrefix(trimmed, "[") || strings.HasPrefix(trimmed, "{") {
+		return trimmed
+	}
+
+	if variable.Type().BaseType() == variables.List {
+		items := []any{}
+
+		for line := range strings.Lines(trimmed) {
+			item := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "- "))
+			if item != "" {
+				items = append(items, item)
+			}
+		}
+
+		return items
+	}
+
+	var parsed any
+	if err := yaml.Unmarshal([]byte(trimmed), &parsed); err != nil {
+		return text
+	}
+
+	return parsed
+}
+
+// Return the values that each element of the given list variable must be one of: the options of a list(enum) variable,
+// or the values of an each(oneOf(...)) validation, if it has one.
+func listElementOptions(variable variables.Variable) []string {
+	if variable.Type() == variables.ListOfEnums {
+		return variable.Options()
+	}
+
+	for _, rule := range variable.Validations() {
+		if rule.Name != "each" || rule.Element == nil || rule.Element.Name != "oneOf" {
+			continue
+		}
+
+		if options, isList := rule.Element.Args[0].([]string); isList {
+			return options
+		}
+	}
+
+	return nil
+}
+
+// Convert the given list to a []any. Returns nil if the given value is not a list.
+func toAnyList(value any) []any {
+	switch list := value.(type) {
+	case []any:
+		return list
+	case []string:
+		converted := make([]any, 0, len(list))
+		for _, item := range list {
+			converted = append(converted, item)
+		}
+
+		return converted
+	default:
+		return nil
+	}
 }
 
-func validateUserInput(value string, variable variables.Variable) (map[string]bool, bool) {
+// Return the message of the prompt for a value of the given variable, which shows its type, how to enter it, and its
+// default.
+func inputMessage(variable variables.Variable, howToEnter string) string {
+	msg := fmt.Sprintf("Enter a value [type %s]", variable.Type())
+	if howToEnter != "" {
+		msg = fmt.Sprintf("%s (%s)", msg, howToEnter)
+	}
+
+	if variable.Default() != nil {
+		msg = fmt.Sprintf("%s (default: %v)", msg, variables.RedactValue(variable, variable.Default()))
+	}
+
+	return msg
+}
+
+// Return the error to show while the user enters the given value for the given variable, if it is invalid.
+func inlineValidationError(value any, variable variables.Variable) error {
+	validationMap, hasValidationErrs := validateUserInput(value, variable)
+	if !hasValidationErrs {
+		return nil
+	}
+
+	failed := []string{}
+
+	for _, description := range slices.Sorted(maps.Keys(validationMap)) {
+		if !validationMap[description] {
+			failed = append(failed, description)
+		}
+	}
+
+	return errors.New(strings.Join(failed, "; "))
+}
+
+func validateUserInput(value any, variable variables.Variable) (map[string]bool, bool) {
 	var valueToValidate any
 	if value == "" {
 		valueToValidate = variable.Default()
@@ -99,13 +257,19 @@ func validateUserInput(value string, variable variables.Variable) (map[string]bo
 	hasValidationErrs := false
 
 	for _, customValidation := range variable.Validations() {
+		// Expression validations refer to other variables, so they are evaluated once all variables have values
+		if customValidation.IsExpression() {
+			continue
+		}
+
 		// Run the specific validation against the user-provided value and store it in the map
-		err := ozzo.Validate(valueToValidate, customValidation.Validator)
 		val := true
 
-		if err != nil {
-			hasValidationErrs = true
-			val = false
+		for _, elementToValidate := range valuesToValidate(valueToValidate, variable) {
+			if err := ozzo.Validate(elementToValidate, customValidation.Validator); err != nil {
+				hasValidationErrs = true
+				val = false
+			}
 		}
 
 		m[customValidation.DescriptionText()] = val
@@ -116,6 +280,17 @@ func validateUserInput(value string, variable variables.Variable) (map[string]bo
 		msg := fmt.Sprintf("Value must be of type %s: %s", variable.Type(), err)
 		m[msg] = false
 	}
+	// Validate the fields of object values against the validations declared on each field
+	if variable.Type().IsObject() {
+		var fieldErrs *multierror.Error
+		if errors.As(validateObjectFields(valueToValidate, variable), &fieldErrs) {
+			hasValidationErrs = true
+
+			for _, fieldErr := range fieldErrs.Errors {
+				m[fieldErr.Error()] = false
+			}
+		}
+	}
 	// Validate that the value is not empty if no default is provided
 	if value == "" && variable.Default() == nil {
 		hasValidationErrs = true
@@ -146,6 +321,10 @@ func renderVariablePrompts(variable variables.Variable, invalidEntries variables
 		fmt.Println(color.Yellow(variable.Description()))
 	}
 
+	if variable.Deprecated() != "" {
+		fmt.Println(color.Yellow("Deprecated: " + variable.Deprecated()))
+	}
+
 	if len(invalidEntries.Issues) > 0 {
 		renderValidationErrors(invalidEntries.Issues[0].Value, invalidEntries.Issues[0].ValidationMap)
 	}
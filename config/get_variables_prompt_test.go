@@ -0,0 +1,282 @@
+//go:build !(js && wasm)
+
+package config //nolint:testpackage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/gruntwork-io/boilerplate/options"
+	"github.com/gruntwork-io/boilerplate/pkg/logging"
+	"github.com/gruntwork-io/boilerplate/prompt"
+	"github.com/gruntwork-io/boilerplate/variables"
+)
+
+// orderedPrompter is a prompt.Prompter that gives the answers in the given order, recording the questions it is asked.
+// An error answer, such as prompt.ErrGoBack, is returned as the error of the prompt.
+type orderedPrompter struct {
+	answers   []any
+	questions []prompt.Question
+	groups    []string
+}
+
+func (ui *orderedPrompter) Group(name string) {
+	ui.groups = append(ui.groups, name)
+}
+
+func (ui *orderedPrompter) next(question prompt.Question) (any, error) {
+	ui.questions = append(ui.questions, question)
+
+	answer := ui.answers[0]
+	ui.answers = ui.answers[1:]
+
+	if err, isErr := answer.(error); isErr {
+		return nil, err
+	}
+
+	return answer, nil
+}
+
+func (ui *orderedPrompter) Input(question prompt.Question) (string, error) {
+	answer, err := ui.next(question)
+	if err != nil {
+		return "", err
+	}
+
+	return answer.(string), nil
+}
+
+func (ui *orderedPrompter) Multiline(question prompt.Question) (string, error) {
+	return ui.Input(question)
+}
+
+func (ui *orderedPrompter) Select(question prompt.Question) (string, error) {
+	return ui.Input(question)
+}
+
+func (ui *orderedPrompter) MultiSelect(question prompt.Question) ([]string, error) {
+	answer, err := ui.next(question)
+	if err != nil {
+		return nil, err
+	}
+
+	return answer.([]string), nil
+}
+
+func (ui *orderedPrompter) Confirm(question prompt.Question) (bool, error) {
+	answer, err := ui.next(question)
+	if err != nil {
+		return false, err
+	}
+
+	return answer.(bool), nil
+}
+
+func (ui *orderedPrompter) YesNo(question prompt.Question) (bool, error) {
+	return ui.Confirm(question)
+}
+
+func (ui *orderedPrompter) YesNoAll(question prompt.Question) (prompt.UserResponse, error) {
+	answer, err := ui.next(question)
+	if err != nil {
+		return prompt.UserResponseNo, err
+	}
+
+	return answer.(prompt.UserResponse), nil
+}
+
+func TestGetVariablesInteractive(t *testing.T) {
+	t.Parallel()
+
+	ui := &orderedPrompter{
+		answers: []any{
+			"web",
+			[]string{"api"},
+			prompt.ErrGoBack,
+			[]string{"api", "worker"},
+			"- 10.0.0.0/24\n- 10.0.1.0/24\n",
+			prompt.ErrGoBack,
+			prompt.ErrGoBack,
+			prompt.ErrGoBack,
+			"app",
+			[]string{"worker"},
+			"10.0.0.0/24\n",
+			true,
+			"prod",
+		},
+	}
+
+	config, err := ParseBoilerplateConfig([]byte(`variables:
+  - name: Name
+    order: 1
+  - name: Components
+    type: list(string)
+    validations: ["each(oneOf(api, worker))"]
+    order: 2
+  - name: Subnets
+    type: list(string)
+    group: Network
+    order: 3
+  - name: Env
+    type: enum
+    options: [dev, prod]
+    default: dev
+    confirm: true
+    order: 5
+  - name: PublicIP
+    type: bool
+    group: Network
+    order: 6
+`))
+	require.NoError(t, err)
+
+	opts := &options.BoilerplateOptions{OnMissingKey: options.ExitWithError, Prompter: ui}
+
+	actual, err := GetVariables(logging.Discard(), opts, config, &BoilerplateConfig{}, &variables.Dependency{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "app", actual["Name"])
+	assert.Equal(t, []any{"worker"}, actual["Components"])
+	assert.Equal(t, []any{"10.0.0.0/24"}, actual["Subnets"])
+	assert.Equal(t, true, actual["PublicIP"])
+	assert.Equal(t, "prod", actual["Env"])
+	assert.Empty(t, ui.answers)
+
+	// The first question can't go back, and the variables of the Network group are asked for together
+	assert.False(t, ui.questions[0].CanGoBack)
+	assert.True(t, ui.questions[1].CanGoBack)
+	assert.Equal(t, []string{"api", "worker"}, ui.questions[1].Options)
+	assert.Equal(t, []string{"", "", "Network", "", "Network", "Network", "Network", "", "", "", "Network", "Network", ""}, ui.groups)
+	assert.Equal(t, "dev", ui.questions[len(ui.questions)-1].Default)
+}
+
+func TestGetVariablesInteractiveListOfEnums(t *testing.T) {
+	t.Parallel()
+
+	ui := &orderedPrompter{answers: []any{[]string{"api", "cron"}}}
+
+	config, err := ParseBoilerplateConfig([]byte(`variables:
+  - name: Components
+    type: list(enum)
+    options: [api, worker, cron]
+    default: [api]
+    confirm: true
+    max_selections: 2
+`))
+	require.NoError(t, err)
+
+	opts := &options.BoilerplateOptions{OnMissingKey: options.ExitWithError, Prompter: ui}
+
+	actual, err := GetVariables(logging.Discard(), opts, config, &BoilerplateConfig{}, &variables.Dependency{})
+	require.NoError(t, err)
+	assert.Equal(t, []any{"api", "cron"}, actual["Components"])
+
+	require.Len(t, ui.questions, 1)
+	assert.Equal(t, []string{"api", "worker", "cron"}, ui.questions[0].Options)
+	assert.Equal(t, []string{"api"}, ui.questions[0].Defaults)
+	require.NoError(t, ui.questions[0].Validate([]string{"worker"}))
+	require.Error(t, ui.questions[0].Validate([]string{"api", "worker", "cron"}))
+}
+
+func TestGetVariablesInteractiveDynamicOptions(t *testing.T) {
+	t.Parallel()
+
+	ui := &orderedPrompter{answers: []any{"gcp", "europe-west1"}}
+
+	config, err := ParseBoilerplateConfig([]byte(`variables:
+  - name: Provider
+    type: enum
+    options: [aws, gcp]
+    order: 0
+  - name: Region
+    type: enum
+    options: '{{ if eq .Provider "aws" }}[us-east-1 eu-west-1]{{ else }}[us-central1 europe-west1]{{ end }}'
+    order: 1
+`))
+	require.NoError(t, err)
+
+	opts := &options.BoilerplateOptions{OnMissingKey: options.ExitWithError, Prompter: ui}
+
+	actual, err := GetVariables(logging.Discard(), opts, config, &BoilerplateConfig{}, &variables.Dependency{})
+	require.NoError(t, err)
+	assert.Equal(t, "europe-west1", actual["Region"])
+
+	require.Len(t, ui.questions, 2)
+	assert.Equal(t, []string{"us-central1", "europe-west1"}, ui.questions[1].Options)
+	require.Error(t, ui.questions[1].Validate("us-east-1"))
+}
+
+func TestGetVariablesInteractiveSavesAcceptedAnswers(t *testing.T) {
+	t.Parallel()
+
+	config, err := ParseBoilerplateConfig([]byte(`variables:
+  - name: UseDatabase
+    type: bool
+    order: 0
+  - name: DatabaseName
+    when: "{{ .UseDatabase }}"
+    order: 1
+  - name: MinReplicas
+    type: int
+    order: 2
+  - name: MaxReplicas
+    type: int
+    order: 3
+    validations:
+      - expr("{{ ge .MaxReplicas .MinReplicas }}")
+`))
+	require.NoError(t, err)
+
+	// The user goes back from MinReplicas to UseDatabase, so DatabaseName no longer applies, and MaxReplicas is
+	// asked for again after it fails its expression validation
+	ui := &orderedPrompter{answers: []any{true, "orders", prompt.ErrGoBack, prompt.ErrGoBack, false, "3", "2", "5"}}
+	opts := &options.BoilerplateOptions{OnMissingKey: options.ExitWithError, Prompter: ui, SaveAnswers: variables.NewAnswerRecorder()}
+
+	_, err = GetVariables(logging.Discard(), opts, config, &BoilerplateConfig{}, &variables.Dependency{})
+	require.NoError(t, err)
+	assert.Empty(t, ui.answers)
+
+	varFile, err := opts.SaveAnswers.VarFile()
+	require.NoError(t, err)
+
+	saved := map[string]any{}
+	require.NoError(t, yaml.Unmarshal(varFile, &saved))
+	assert.Equal(t, map[string]any{"UseDatabase": false, "MinReplicas": 3, "MaxReplicas": 5}, saved)
+
+	// Replaying the saved answers gives the same values
+	replayOpts := &options.BoilerplateOptions{OnMissingKey: options.ExitWithError, NonInteractive: true, Vars: saved}
+
+	replayed, err := GetVariables(logging.Discard(), replayOpts, config, &BoilerplateConfig{}, &variables.Dependency{})
+	require.NoError(t, err)
+	assert.Equal(t, 5, replayed["MaxReplicas"])
+}
+
+func TestParseMultilineInput(t *testing.T) {
+	t.Parallel()
+
+	list := variables.NewListVariable("Tags")
+	mapVariable := variables.NewMapVariable("Labels")
+
+	assert.Equal(t, []any{"web", "api"}, parseMultilineInput("web\n\n- api\n", list))
+	assert.Equal(t, `["web"]`, parseMultilineInput(` ["web"] `, list))
+	assert.Empty(t, parseMultilineInput("\n", list))
+	assert.Equal(t, map[string]any{"env": "prod", "team": "web"}, parseMultilineInput("env: prod\nteam: web\n", mapVariable))
+	assert.Equal(t, "env: [prod\n", parseMultilineInput("env: [prod\n", mapVariable))
+}
+
+func TestInlineValidationError(t *testing.T) {
+	t.Parallel()
+
+	config, err := ParseBoilerplateConfig([]byte(`variables:
+  - name: Port
+    type: int
+    validations: ["range(1, 65535)"]
+`))
+	require.NoError(t, err)
+
+	require.NoError(t, inlineValidationError("8080", config.Variables[0]))
+	assert.EqualError(t, inlineValidationError("70000", config.Variables[0]), "Must be between 1 and 65535")
+}
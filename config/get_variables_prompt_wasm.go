@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/gruntwork-io/boilerplate/pkg/logging"
+	"github.com/gruntwork-io/boilerplate/prompt"
 	"github.com/gruntwork-io/boilerplate/variables"
 )
 
@@ -14,6 +15,6 @@ import (
 // the WASM build), so they should not be hit at runtime; they exist only to
 // satisfy the linker.
 
-func getVariableFromUser(_ logging.Logger, variable variables.Variable, _ variables.InvalidEntries) (any, error) {
+func getVariableFromUser(_ logging.Logger, _ prompt.Prompter, variable variables.Variable, _ variables.InvalidEntries, _ bool) (any, error) {
 	return nil, fmt.Errorf("interactive prompts are not supported in WASM (variable %q)", variable.FullName())
 }
@@ -1,6 +1,7 @@
 package config //nolint:testpackage
 
 import (
+	"bytes"
 	"maps"
 	"reflect"
 	"slices"
@@ -101,7 +102,7 @@ func TestGetVariableNoMatchNonInteractive(t *testing.T) {
 	variable := variables.NewStringVariable("foo")
 	opts := testutil.CreateTestOptionsForShell(true, false)
 
-	_, err := getVariable(logging.Discard(), variable, opts)
+	_, err := getVariable(logging.Discard(), variable, opts, &promptState{})
 
 	require.Error(t, err)
 	assert.ErrorIs(t, err, MissingVariableWithNonInteractiveMode("foo"), "Expected a MissingVariableWithNonInteractiveMode error but got %s", reflect.TypeOf(err))
@@ -120,7 +121,7 @@ func TestGetVariableInVarsNonInteractive(t *testing.T) {
 		},
 	}
 
-	actual, err := getVariable(logging.Discard(), variable, opts)
+	actual, err := getVariable(logging.Discard(), variable, opts, &promptState{})
 	expected := "bar"
 
 	require.NoError(t, err)
@@ -140,7 +141,7 @@ func TestGetVariableDefaultNonInteractive(t *testing.T) {
 		},
 	}
 
-	actual, err := getVariable(logging.Discard(), variable, opts)
+	actual, err := getVariable(logging.Discard(), variable, opts, &promptState{})
 	expected := "bar"
 
 	require.NoError(t, err)
@@ -276,7 +277,7 @@ func TestGetVariableInteractiveWithDefaultSkipsPrompt(t *testing.T) {
 		Vars:           map[string]any{},
 	}
 
-	actual, err := getVariable(logging.Discard(), variable, opts)
+	actual, err := getVariable(logging.Discard(), variable, opts, &promptState{})
 	require.NoError(t, err)
 	assert.Equal(t, "default-val", actual)
 }
@@ -295,7 +296,7 @@ func TestGetVariableInteractiveWithDefaultAndConfirmDoesNotSkipPrompt(t *testing
 		Vars:           map[string]any{},
 	}
 
-	actual, err := getVariable(logging.Discard(), variable, opts)
+	actual, err := getVariable(logging.Discard(), variable, opts, &promptState{})
 	require.NoError(t, err)
 	assert.Equal(t, "default-val", actual)
 }
@@ -309,7 +310,7 @@ func TestGetVariableInteractiveFormulaDefaultSkipsPrompt(t *testing.T) {
 		Vars:           map[string]any{},
 	}
 
-	actual, err := getVariable(logging.Discard(), variable, opts)
+	actual, err := getVariable(logging.Discard(), variable, opts, &promptState{})
 	require.NoError(t, err)
 	assert.Equal(t, "{{ .Primary }}", actual)
 }
@@ -343,3 +344,475 @@ func TestValidateUserInput(t *testing.T) {
 	key := slices.Collect(maps.Keys(m))[0]
 	assert.Contains(t, key, "Value must be of type int")
 }
+
+func TestGetVariablesObjectFieldValidations(t *testing.T) {
+	t.Parallel()
+
+	opts := &options.BoilerplateOptions{NonInteractive: true, OnMissingKey: options.ExitWithError}
+
+	config, err := ParseBoilerplateConfig([]byte(`variables:
+  - name: Subnets
+    type: list(object)
+    fields:
+      - name: CIDR
+        validations:
+          - required
+      - name: AZ
+        default: us-east-1a
+    default:
+      - CIDR: 10.0.0.0/24
+`))
+	require.NoError(t, err)
+
+	actual, err := GetVariables(logging.Discard(), opts, config, &BoilerplateConfig{}, &variables.Dependency{})
+	require.NoError(t, err)
+	assert.Equal(t, []any{map[string]any{"CIDR": "10.0.0.0/24", "AZ": "us-east-1a"}}, actual["Subnets"])
+
+	config, err = ParseBoilerplateConfig([]byte(`variables:
+  - name: Subnets
+    type: list(object)
+    fields:
+      - name: CIDR
+        validations:
+          - required
+      - name: AZ
+        default: us-east-1a
+    default:
+      - CIDR: 10.0.0.0/24
+      - AZ: us-east-1b
+`))
+	require.NoError(t, err)
+
+	_, err = GetVariables(logging.Discard(), opts, config, &BoilerplateConfig{}, &variables.Dependency{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Subnets[1].CIDR")
+}
+
+func TestGetVariablesTypedListElementValidations(t *testing.T) {
+	t.Parallel()
+
+	opts := &options.BoilerplateOptions{NonInteractive: true, OnMissingKey: options.ExitWithError}
+
+	config, err := ParseBoilerplateConfig([]byte(`variables:
+  - name: Ports
+    type: list(int)
+    validations:
+      - required
+    default: [80, "443"]
+`))
+	require.NoError(t, err)
+
+	actual, err := GetVariables(logging.Discard(), opts, config, &BoilerplateConfig{}, &variables.Dependency{})
+	require.NoError(t, err)
+	assert.Equal(t, []any{80, 443}, actual["Ports"])
+
+	config, err = ParseBoilerplateConfig([]byte(`variables:
+  - name: Zones
+    type: map(string)
+    validations:
+      - length(2, 3)
+    default:
+      primary: us
+      secondary: europe
+`))
+	require.NoError(t, err)
+
+	_, err = GetVariables(logging.Discard(), opts, config, &BoilerplateConfig{}, &variables.Dependency{})
+	require.Error(t, err)
+}
+
+func TestValidateUserInputTypedList(t *testing.T) {
+	t.Parallel()
+
+	config, err := ParseBoilerplateConfig([]byte(`variables:
+  - name: Names
+    type: list(string)
+    validations:
+      - length(2, 5)
+`))
+	require.NoError(t, err)
+
+	_, hasValidationErrs := validateUserInput(`["ab", "cde"]`, config.Variables[0])
+	assert.False(t, hasValidationErrs)
+
+	_, hasValidationErrs = validateUserInput(`["ab", "toolong"]`, config.Variables[0])
+	assert.True(t, hasValidationErrs)
+}
+
+func TestGetVariablesWhenCondition(t *testing.T) {
+	t.Parallel()
+
+	config, err := ParseBoilerplateConfig([]byte(`variables:
+  - name: EnableDatabase
+    type: bool
+    order: 0
+  - name: DatabaseName
+    order: 1
+    when: "{{ .EnableDatabase }}"
+  - name: DatabasePort
+    type: int
+    order: 2
+    when: "{{ .EnableDatabase }}"
+    default: 5432
+`))
+	require.NoError(t, err)
+
+	opts := &options.BoilerplateOptions{
+		NonInteractive: true,
+		OnMissingKey:   options.ExitWithError,
+		Vars:           map[string]any{"EnableDatabase": "false"},
+	}
+
+	actual, err := GetVariables(logging.Discard(), opts, config, &BoilerplateConfig{}, &variables.Dependency{})
+	require.NoError(t, err)
+	assert.Equal(t, false, actual["EnableDatabase"])
+	assert.Nil(t, actual["DatabaseName"])
+	assert.Equal(t, 5432, actual["DatabasePort"])
+
+	opts.Vars = map[string]any{"EnableDatabase": true}
+
+	_, err = GetVariables(logging.Discard(), opts, config, &BoilerplateConfig{}, &variables.Dependency{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "DatabaseName")
+
+	opts.Vars = map[string]any{"EnableDatabase": true, "DatabaseName": "app"}
+
+	actual, err = GetVariables(logging.Discard(), opts, config, &BoilerplateConfig{}, &variables.Dependency{})
+	require.NoError(t, err)
+	assert.Equal(t, "app", actual["DatabaseName"])
+}
+
+func TestGetVariablesDynamicOptions(t *testing.T) {
+	t.Parallel()
+
+	config, err := ParseBoilerplateConfig([]byte(`variables:
+  - name: Provider
+    type: enum
+    options: [aws, gcp]
+    order: 0
+  - name: RegionsByProvider
+    type: map
+    default:
+      aws: "[us-east-1 eu-west-1]"
+      gcp: "[us-central1 europe-west1]"
+    order: 1
+  - name: Region
+    type: enum
+    options: "{{ index .RegionsByProvider .Provider }}"
+    order: 2
+  - name: Zones
+    type: list(enum)
+    options: |
+      {{- range $suffix := list "a" "b" "c" }}
+      - {{ $.Region }}-{{ $suffix }}
+      {{- end }}
+    min_selections: 1
+    order: 3
+`))
+	require.NoError(t, err)
+
+	opts := &options.BoilerplateOptions{
+		NonInteractive: true,
+		OnMissingKey:   options.ExitWithError,
+		Vars:           map[string]any{"Provider": "gcp", "Region": "europe-west1", "Zones": []any{"europe-west1-b"}},
+	}
+
+	actual, err := GetVariables(logging.Discard(), opts, config, &BoilerplateConfig{}, &variables.Dependency{})
+	require.NoError(t, err)
+	assert.Equal(t, "europe-west1", actual["Region"])
+	assert.Equal(t, []any{"europe-west1-b"}, actual["Zones"])
+
+	// The options of the config itself are left as they are, so they render again for the next use of the config
+	assert.Empty(t, config.GetVariablesMap()["Region"].Options())
+
+	opts.Vars = map[string]any{"Provider": "aws", "Region": "europe-west1", "Zones": []any{"europe-west1-b"}}
+
+	_, err = GetVariables(logging.Discard(), opts, config, &BoilerplateConfig{}, &variables.Dependency{})
+	require.ErrorAs(t, err, &variables.InvalidVariableValue{})
+	assert.Contains(t, err.Error(), "[us-east-1 eu-west-1]")
+}
+
+func TestGetVariablesAliasesAndDeprecation(t *testing.T) {
+	t.Parallel()
+
+	config, err := ParseBoilerplateConfig([]byte(`variables:
+  - name: ServiceName
+    aliases: [Name, AppName]
+  - name: Region
+    aliases: [AwsRegion]
+    deprecated: Regions are now set per environment
+    default: us-east-1
+`))
+	require.NoError(t, err)
+
+	var logs bytes.Buffer
+
+	opts := &options.BoilerplateOptions{
+		NonInteractive: true,
+		OnMissingKey:   options.ExitWithError,
+		Vars:           map[string]any{"AppName": "app", "AwsRegion": "eu-west-1"},
+	}
+
+	actual, err := GetVariables(logging.New(&logs, logging.LevelInfo), opts, config, &BoilerplateConfig{}, &variables.Dependency{})
+	require.NoError(t, err)
+	assert.Equal(t, "app", actual["ServiceName"])
+	assert.Equal(t, "eu-west-1", actual["Region"])
+	assert.NotContains(t, actual, "AppName")
+	assert.Contains(t, logs.String(), "Variable 'AppName' was renamed to 'ServiceName'")
+	assert.Contains(t, logs.String(), "Variable 'Region' is deprecated: Regions are now set per environment")
+
+	// A value passed in under the new name takes precedence
+	opts.Vars = map[string]any{"Name": "old", "ServiceName": "new"}
+
+	actual, err = GetVariables(logging.Discard(), opts, config, &BoilerplateConfig{}, &variables.Dependency{})
+	require.NoError(t, err)
+	assert.Equal(t, "new", actual["ServiceName"])
+
+	// Answers recorded in a manifest under the old name are used too
+	opts.Vars = map[string]any{}
+	opts.PreviousAnswers = &variables.PreviousAnswers{Variables: map[string]any{"Name": "previous"}}
+
+	actual, err = GetVariables(logging.Discard(), opts, config, &BoilerplateConfig{}, &variables.Dependency{})
+	require.NoError(t, err)
+	assert.Equal(t, "previous", actual["ServiceName"])
+}
+
+func TestGetVariablesInvalidWhenCondition(t *testing.T) {
+	t.Parallel()
+
+	config, err := ParseBoilerplateConfig([]byte(`variables:
+  - name: Name
+    when: "{{ .Missing.Field }}"
+    default: foo
+`))
+	require.NoError(t, err)
+
+	opts := &options.BoilerplateOptions{NonInteractive: true, OnMissingKey: options.ExitWithError}
+
+	_, err = GetVariables(logging.Discard(), opts, config, &BoilerplateConfig{}, &variables.Dependency{})
+
+	var conditionErr InvalidVariableCondition
+	require.ErrorAs(t, err, &conditionErr)
+	assert.Equal(t, "Name", conditionErr.VariableName)
+}
+
+func TestGetVariablesComputedValue(t *testing.T) {
+	t.Parallel()
+
+	config, err := ParseBoilerplateConfig([]byte(`variables:
+  - name: AppName
+  - name: BucketName
+    value: "{{ .AppName }}-assets"
+  - name: Replicas
+    type: int
+    value: "{{ if eq .AppName \"prod\" }}3{{ else }}1{{ end }}"
+    overridable: true
+`))
+	require.NoError(t, err)
+
+	opts := &options.BoilerplateOptions{
+		NonInteractive: true,
+		OnMissingKey:   options.ExitWithError,
+		Vars:           map[string]any{"AppName": "web", "BucketName": "ignored"},
+	}
+
+	var logs bytes.Buffer
+
+	actual, err := GetVariables(logging.New(&logs, logging.LevelWarn), opts, config, &BoilerplateConfig{}, &variables.Dependency{})
+	require.NoError(t, err)
+	assert.Equal(t, "web-assets", actual["BucketName"])
+	assert.Equal(t, 1, actual["Replicas"])
+	assert.Contains(t, logs.String(), "Ignoring the value passed in for variable 'BucketName', as it is computed")
+
+	opts.Vars = map[string]any{"AppName": "web", "Replicas": 5}
+
+	actual, err = GetVariables(logging.Discard(), opts, config, &BoilerplateConfig{}, &variables.Dependency{})
+	require.NoError(t, err)
+	assert.Equal(t, 5, actual["Replicas"])
+}
+
+func TestGetVariablesExpressionValidations(t *testing.T) {
+	t.Parallel()
+
+	config, err := ParseBoilerplateConfig([]byte(`variables:
+  - name: VpcCidr
+    validations:
+      - cidr
+  - name: SubnetCidr
+    validations:
+      - cidr
+      - expr("{{ cidrContains .VpcCidr .SubnetCidr }}")
+  - name: Port
+    type: int
+    validations:
+      - range(1, 65535)
+`))
+	require.NoError(t, err)
+
+	opts := &options.BoilerplateOptions{
+		NonInteractive: true,
+		OnMissingKey:   options.ExitWithError,
+		Vars:           map[string]any{"VpcCidr": "10.0.0.0/16", "SubnetCidr": "10.0.1.0/24", "Port": 443},
+	}
+
+	_, err = GetVariables(logging.Discard(), opts, config, &BoilerplateConfig{}, &variables.Dependency{})
+	require.NoError(t, err)
+
+	opts.Vars = map[string]any{"VpcCidr": "10.0.0.0/16", "SubnetCidr": "10.1.0.0/24", "Port": 443}
+
+	_, err = GetVariables(logging.Discard(), opts, config, &BoilerplateConfig{}, &variables.Dependency{})
+
+	var validationErr ExpressionValidationFailed
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "SubnetCidr", validationErr.VariableName)
+	assert.Equal(t, []string{"Must satisfy: {{ cidrContains .VpcCidr .SubnetCidr }}"}, validationErr.Failed)
+}
+
+func TestValidateUserInputNumericRange(t *testing.T) {
+	t.Parallel()
+
+	config, err := ParseBoilerplateConfig([]byte(`variables:
+  - name: Port
+    type: int
+    validations:
+      - range(1, 65535)
+      - expr("{{ ne .Port 22 }}")
+`))
+	require.NoError(t, err)
+
+	m, hasValidationErrs := validateUserInput("8080", config.Variables[0])
+	assert.False(t, hasValidationErrs)
+	assert.Equal(t, map[string]bool{"Must be between 1 and 65535": true}, m)
+
+	_, hasValidationErrs = validateUserInput("70000", config.Variables[0])
+	assert.True(t, hasValidationErrs)
+}
+
+func TestGetVariablesNonInteractiveCollectsAllIssues(t *testing.T) {
+	t.Parallel()
+
+	config, err := ParseBoilerplateConfig([]byte(`variables:
+  - name: Name
+    order: 0
+  - name: Region
+    order: 1
+  - name: Port
+    type: int
+    order: 2
+    validations:
+      - range(1, 65535)
+  - name: Email
+    order: 3
+    default: not-an-email
+    validations:
+      - email
+  - name: Environment
+    order: 4
+    default: dev
+`))
+	require.NoError(t, err)
+
+	opts := &options.BoilerplateOptions{
+		NonInteractive: true,
+		OnMissingKey:   options.ExitWithError,
+		Vars:           map[string]any{"Port": 70000},
+	}
+
+	vars, err := GetVariables(logging.Discard(), opts, config, &BoilerplateConfig{}, &variables.Dependency{})
+
+	var invalidVariables InvalidVariables
+	require.ErrorAs(t, err, &invalidVariables)
+	require.Len(t, invalidVariables.Issues, 4)
+
+	assert.Equal(t, "Name", invalidVariables.Issues[0].VariableName)
+	assert.True(t, invalidVariables.Issues[0].Missing)
+	assert.Equal(t, "Region", invalidVariables.Issues[1].VariableName)
+	assert.True(t, invalidVariables.Issues[1].Missing)
+	assert.Equal(t, "Port", invalidVariables.Issues[2].VariableName)
+	assert.False(t, invalidVariables.Issues[2].Missing)
+	assert.Equal(t, "Email", invalidVariables.Issues[3].VariableName)
+	assert.False(t, invalidVariables.Issues[3].Missing)
+
+	require.ErrorIs(t, err, MissingVariableWithNonInteractiveMode("Region"))
+	assert.Contains(t, err.Error(), "4 variable(s) are missing or invalid")
+
+	// The variables that do have values are still returned, so the variables of dependencies can be checked too
+	assert.Equal(t, "dev", vars["Environment"])
+
+	prefixed := invalidVariables.ForDependency("dep1")
+	assert.Equal(t, "dep1.Name", prefixed.Issues[0].VariableName)
+	assert.Equal(t, "Name", invalidVariables.Issues[0].VariableName)
+}
+
+func TestGetVariablesNonInteractiveCollectsTypeAndExpressionIssues(t *testing.T) {
+	t.Parallel()
+
+	config, err := ParseBoilerplateConfig([]byte(`variables:
+  - name: Region
+    order: 0
+  - name: Zone
+    type: enum
+    options: [a, b]
+  - name: Comps
+    type: list(enum)
+    options: [x, y]
+  - name: Replicas
+    type: int
+  - name: Name
+    order: 1
+    validations:
+      - length(1, 3)
+  - name: Env
+    validations:
+      - expr("{{ ne .Env \"prod\" }}")
+`))
+	require.NoError(t, err)
+
+	vars := map[string]any{"Zone": "c", "Comps": []any{"x", "z"}, "Replicas": "many", "Name": "toolong", "Env": "prod"}
+
+	for _, missingRegion := range []bool{true, false} {
+		opts := &options.BoilerplateOptions{
+			NonInteractive: true,
+			OnMissingKey:   options.ExitWithError,
+			Vars:           maps.Clone(vars),
+		}
+
+		expected := []string{"Name", "Comps", "Replicas", "Zone", "Env"}
+		if !missingRegion {
+			opts.Vars["Region"] = "eu-west-1"
+		} else {
+			expected = append([]string{"Region"}, expected...)
+		}
+
+		_, err = GetVariables(logging.Discard(), opts, config, &BoilerplateConfig{}, &variables.Dependency{})
+
+		var invalidVariables InvalidVariables
+		require.ErrorAs(t, err, &invalidVariables)
+
+		names := []string{}
+		for _, issue := range invalidVariables.Issues {
+			names = append(names, issue.VariableName)
+		}
+
+		// Missing and invalid values are reported in prompt order, followed by type errors and expression validation
+		// failures, each sorted by variable name
+		assert.Equal(t, expected, names)
+		assert.NotContains(t, err.Error(), "error occurred")
+	}
+}
+
+func TestValidateUserInputObject(t *testing.T) {
+	t.Parallel()
+
+	v := variables.NewObjectVariable("Subnet", []variables.Variable{
+		variables.NewStringVariable("CIDR"),
+		variables.NewIntVariable("Size").WithDefault(24),
+	})
+
+	m, hasValidationErrs := validateUserInput(`{"CIDR": "10.0.0.0/24"}`, v)
+	assert.False(t, hasValidationErrs)
+	assert.Empty(t, m)
+
+	_, hasValidationErrs = validateUserInput(`{"CIDR": "10.0.0.0/24", "Size": "large"}`, v)
+	assert.True(t, hasValidationErrs)
+}
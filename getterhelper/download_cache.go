@@ -0,0 +1,118 @@
+package getterhelper
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	getter "github.com/hashicorp/go-getter"
+
+	"github.com/gruntwork-io/boilerplate/pkg/logging"
+)
+
+// DownloadCache downloads each remote template source once per run and shares the checkout between all the templates
+// that use it, such as the iterations of a dependency with for_each, or dependencies in different subdirectories of
+// the same repo. Sources are identified by their normalized URL, including the ref, but not the subdirectory. The
+// checkouts are shared, so they must be treated as read-only. It is safe for concurrent use.
+type DownloadCache struct {
+	downloads     map[string]*cachedDownload
+	downloadCount int
+	mutex         sync.Mutex
+}
+
+type cachedDownload struct {
+	err        error
+	workingDir string
+	once       sync.Once
+}
+
+// NewDownloadCache creates an empty DownloadCache. Call Cleanup when the run is done to remove the downloads.
+func NewDownloadCache() *DownloadCache {
+	return &DownloadCache{downloads: map[string]*cachedDownload{}}
+}
+
+// Download returns the working directory and template folder of the given template URL, like
+// DownloadTemplatesToTemporaryFolder, downloading its source only if it has not been downloaded in this run before.
+// The working directory belongs to the cache, and is removed by Cleanup.
+func (cache *DownloadCache) Download(l logging.Logger, templateURL string) (string, string, error) {
+	source, subDir := getter.SourceDirSubdir(templateURL)
+	key := downloadCacheKey(source)
+
+	cache.mutex.Lock()
+
+	download, isCached := cache.downloads[key]
+	if !isCached {
+		download = &cachedDownload{}
+		cache.downloads[key] = download
+	}
+
+	cache.mutex.Unlock()
+
+	if isCached {
+		l.Debugf("Using the download of %s from earlier in this run", source)
+	}
+
+	download.once.Do(func() {
+		download.workingDir, _, download.err = DownloadTemplatesToTemporaryFolder(l, source)
+
+		cache.mutex.Lock()
+		cache.downloadCount++
+		cache.mutex.Unlock()
+	})
+
+	return download.workingDir, filepath.Clean(filepath.Join(download.workingDir, CloneSubdir, subDir)), download.err
+}
+
+// Downloads returns the number of sources the cache has downloaded, including failed downloads.
+func (cache *DownloadCache) Downloads() int {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	return cache.downloadCount
+}
+
+// Cleanup removes all the downloads of the cache.
+func (cache *DownloadCache) Cleanup(l logging.Logger) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	for key, download := range cache.downloads {
+		if download.workingDir == "" {
+			continue
+		}
+
+		l.Debugf("Cleaning up working directory %s.", download.workingDir)
+
+		if err := os.RemoveAll(download.workingDir); err != nil {
+			l.Errorf("Failed to clean up working directory %s: %v", download.workingDir, err)
+		}
+
+		delete(cache.downloads, key)
+	}
+}
+
+// Return the key to cache the download of the given source URL by: the URL as go-getter detects it, so that, e.g.,
+// github.com/acme/templates and git::https://github.com/acme/templates.git are the same source, with the host in
+// lower case and the query parameters, such as the ref, sorted.
+func downloadCacheKey(source string) string {
+	pwd, err := os.Getwd()
+	if err != nil {
+		return source
+	}
+
+	detected, err := getter.Detect(source, pwd, getter.Detectors)
+	if err != nil {
+		return source
+	}
+
+	parsed, err := urlParseGetterURL(detected)
+	if err != nil {
+		return detected
+	}
+
+	parsed.Host = strings.ToLower(parsed.Host)
+	parsed.RawQuery = parsed.Query().Encode()
+
+	return parsed.String()
+}
@@ -0,0 +1,93 @@
+package getterhelper_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/boilerplate/getterhelper"
+	"github.com/gruntwork-io/boilerplate/pkg/logging"
+)
+
+// Create a local git repository with the subdirectories app and db, and return its path.
+func createGitRepo(t *testing.T) string {
+	t.Helper()
+
+	repoDir := t.TempDir()
+
+	for _, subDir := range []string{"app", "db"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(repoDir, subDir), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(repoDir, subDir, "boilerplate.yml"), []byte("variables: []\n"), 0644))
+	}
+
+	for _, args := range [][]string{
+		{"init", "-b", "main"},
+		{"config", "user.email", "test@test.com"},
+		{"config", "user.name", "Test"},
+		{"add", "."},
+		{"commit", "-m", "initial"},
+	} {
+		cmd := exec.CommandContext(context.Background(), "git", args...)
+		cmd.Dir = repoDir
+
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, "git %v failed: %s", args, string(out))
+	}
+
+	return repoDir
+}
+
+func TestDownloadCacheSharesSources(t *testing.T) {
+	t.Parallel()
+
+	repoDir := createGitRepo(t)
+	cache := getterhelper.NewDownloadCache()
+
+	// Concurrent downloads of the same source are only done once
+	workingDirs := make([]string, 10)
+
+	var wg sync.WaitGroup
+
+	for i := range workingDirs {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			workingDir, templateFolder, err := cache.Download(logging.Discard(), "git::file://"+repoDir+"//app?ref=main")
+			assert.NoError(t, err)
+			assert.FileExists(t, filepath.Join(templateFolder, "boilerplate.yml"))
+
+			workingDirs[i] = workingDir
+		}()
+	}
+
+	wg.Wait()
+
+	for _, workingDir := range workingDirs {
+		assert.Equal(t, workingDirs[0], workingDir)
+	}
+
+	assert.Equal(t, 1, cache.Downloads())
+
+	// Other subdirectories of the same source share the download, but other refs don't
+	workingDir, templateFolder, err := cache.Download(logging.Discard(), "git::file://"+repoDir+"//db?ref=main")
+	require.NoError(t, err)
+	assert.Equal(t, workingDirs[0], workingDir)
+	assert.Equal(t, filepath.Join(workingDir, getterhelper.CloneSubdir, "db"), templateFolder)
+
+	otherRefDir, _, err := cache.Download(logging.Discard(), "git::file://"+repoDir+"//db?ref=HEAD")
+	require.NoError(t, err)
+	assert.NotEqual(t, workingDirs[0], otherRefDir)
+	assert.Equal(t, 2, cache.Downloads())
+
+	cache.Cleanup(logging.Discard())
+	assert.NoDirExists(t, workingDir)
+	assert.NoDirExists(t, otherRefDir)
+}
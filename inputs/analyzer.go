@@ -1042,6 +1042,27 @@ func collectStringRefs(v any, refs map[string]struct{}, result *Result, declared
 	}
 }
 
+// inputFields describes the declared fields of an object or list(object)
+// input, recursing into nested objects. Returns nil when there are no fields
+// so the JSON output omits the key.
+func inputFields(fields []variables.Variable) []InputField {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	out := make([]InputField, 0, len(fields))
+	for _, field := range fields {
+		out = append(out, InputField{
+			Name:        field.Name(),
+			Type:        string(field.Type()),
+			Description: field.Description(),
+			Fields:      inputFields(field.Fields()),
+		})
+	}
+
+	return out
+}
+
 // composeResult walks the templateInfo tree and emits one InputEntry per
 // (template_path, declared_input) pair into result, with transitive closure
 // applied through dependency edges and partials already expanded in fileRefs.
@@ -1069,6 +1090,11 @@ func composeResult(root *templateInfo, result *Result) {
 				Files:       sortedKeys(files),
 				Type:        string(decl.Type()),
 				Description: decl.Description(),
+				Fields:      inputFields(decl.Fields()),
+				When:        decl.When(),
+				Computed:    decl.Value() != nil,
+				Sensitive:   decl.Sensitive(),
+				Group:       decl.Group(),
 			}
 
 			for f := range files {
@@ -103,6 +103,35 @@ variables:
 	assert.Empty(t, res.Inputs[".:Unused"].Files)
 }
 
+func TestFromFS_ObjectInputFields(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"boilerplate.yml": &fstest.MapFile{Data: []byte(`
+variables:
+  - name: Subnets
+    type: list(object)
+    fields:
+      - name: CIDR
+        description: The subnet CIDR block
+      - name: AZ
+        default: us-east-1a
+`)},
+		"main.tf": &fstest.MapFile{Data: []byte(`{{ range .Subnets }}{{ .CIDR }}{{ end }}`)},
+	}
+
+	res := runFS(t, fsys, map[string]any{})
+
+	require.Contains(t, res.Inputs, ".:Subnets")
+	entry := res.Inputs[".:Subnets"]
+	assert.Equal(t, "list(object)", entry.Type)
+	assert.Equal(t, []string{"main.tf"}, entry.Files)
+	assert.Equal(t, []InputField{
+		{Name: "CIDR", Type: "string", Description: "The subnet CIDR block"},
+		{Name: "AZ", Type: "string"},
+	}, entry.Fields)
+}
+
 func TestFromFS_UndeclaredVariable(t *testing.T) {
 	t.Parallel()
 
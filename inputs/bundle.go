@@ -60,9 +60,39 @@ type ResolvedDep struct {
 	OutputFolder string `json:"outputFolder"`
 
 	// Each is the for_each iteration value the consumer must seed into the
-	// parent scope as `__each__` before evaluating dep-variable defaults.
-	// Empty for non-for_each deps.
+	// parent scope as `__each__` before evaluating dep-variable defaults,
+	// when it is a string. Empty for non-for_each deps.
 	Each string `json:"each,omitempty"`
+
+	// EachValue takes the place of Each when the iteration value is not a
+	// string: a list element such as an object, or a {key, value} object
+	// when iterating over a map.
+	EachValue any `json:"eachValue,omitempty"`
+}
+
+// eachItem returns the for_each iteration value of the dep, whichever of
+// Each and EachValue it is recorded in, or nil for non-for_each deps.
+func (dep ResolvedDep) eachItem() any {
+	if dep.EachValue != nil {
+		return dep.EachValue
+	}
+
+	if dep.Each != "" {
+		return dep.Each
+	}
+
+	return nil
+}
+
+// setEachItem records the given for_each iteration value in Each if it is
+// a string, or in EachValue otherwise.
+func (dep *ResolvedDep) setEachItem(item any) {
+	if str, isString := item.(string); isString {
+		dep.Each = str
+		return
+	}
+
+	dep.EachValue = item
 }
 
 // ErrRemoteDependencyInBundle is non-fatal at the bundle level: the bundle
@@ -305,7 +335,7 @@ func collectBundle(
 		childBundleDir := path.Join(parentBundleKey, bundleDepsDir, sanitizeDepName(dep.Name))
 
 		// A dep with no iteration produces a single ResolvedDep entry with
-		// Each=""; N iterations produce N entries sharing Name + BundlePath
+		// no Each; N iterations produce N entries sharing Name + BundlePath
 		// but each carrying its own OutputFolder + Each.
 		forEachItems, feErr := resolveForEach(ctx, loc, dep, scope)
 		if feErr != nil {
@@ -322,8 +352,8 @@ func collectBundle(
 		}
 
 		// Build the iteration list. A dep without for_each gets a
-		// single nil-Each pass so the code path below stays uniform.
-		iterations := []string{""}
+		// single nil-item pass so the code path below stays uniform.
+		iterations := []any{nil}
 		isForEach := len(forEachItems) > 0
 
 		if isForEach {
@@ -357,7 +387,7 @@ func collectBundle(
 			}
 
 			if isForEach {
-				entry.Each = item
+				entry.setEachItem(item)
 			}
 
 			bundle.Dependencies[parentBundleKey] = append(bundle.Dependencies[parentBundleKey], entry)
@@ -387,11 +417,12 @@ func collectBundle(
 // the runtime's logic in templates.processDependency. Static for_each lists
 // are returned as-is; a for_each_reference is rendered against parentVars
 // (it evaluates to a variable name), and the named variable's value is
-// expected to be a string list in parentVars.
+// expected to be a list or a map in parentVars, expanded the same way as
+// variables.ForEachItems.
 //
 // Returns (nil, nil) for a dep with no iteration — collectBundle treats
 // that as the "single un-iterated entry" case.
-func resolveForEach(ctx context.Context, loc templateLocation, dep *variables.Dependency, parentVars map[string]any) ([]string, error) {
+func resolveForEach(ctx context.Context, loc templateLocation, dep *variables.Dependency, parentVars map[string]any) ([]any, error) {
 	if len(dep.ForEachReference) > 0 {
 		renderedReference, renderErr := renderForAnalysis(ctx, loc.absDir, dep.ForEachReference, parentVars)
 		if renderErr != nil {
@@ -403,15 +434,20 @@ func resolveForEach(ctx context.Context, loc templateLocation, dep *variables.De
 			return nil, nil
 		}
 
-		value, unmarshalErr := variables.UnmarshalListOfStrings(parentVars, renderedReference)
-		if unmarshalErr != nil {
-			return nil, fmt.Errorf("looking up for_each_reference %q in vars: %w", renderedReference, unmarshalErr)
+		value, hasValue := parentVars[renderedReference]
+		if !hasValue || value == nil {
+			return nil, nil
+		}
+
+		items, itemsErr := variables.ForEachItems(value)
+		if itemsErr != nil {
+			return nil, fmt.Errorf("looking up for_each_reference %q in vars: %w", renderedReference, itemsErr)
 		}
 
-		return value, nil
+		return items, nil
 	}
 
-	return dep.ForEach, nil
+	return dep.ForEachList(), nil
 }
 
 // joinCleanBundlePath joins a parent bundle directory (already free of any
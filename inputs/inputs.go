@@ -66,6 +66,37 @@ type InputEntry struct {
 	Type        string   `json:"type"`
 	Description string   `json:"description,omitempty"`
 	Files       []string `json:"files"`
+
+	// Fields describes the declared typed fields of object and list(object)
+	// inputs. It is omitted for all other types.
+	Fields []InputField `json:"fields,omitempty"`
+
+	// When is the condition under which the input is prompted for, as
+	// declared in the variable's "when" field. It is omitted for inputs that
+	// are always prompted for.
+	When string `json:"when,omitempty"`
+
+	// Computed is true for inputs that declare a "value" and are therefore
+	// derived from other inputs rather than prompted for.
+	Computed bool `json:"computed,omitempty"`
+
+	// Sensitive is true for inputs that hold secrets, such as passwords,
+	// which consumers should mask when collecting or displaying them.
+	Sensitive bool `json:"sensitive,omitempty"`
+
+	// Group is the name of the group the input is prompted for in, as
+	// declared in the variable's "group" field. It is omitted for inputs that
+	// are not in a group.
+	Group string `json:"group,omitempty"`
+}
+
+// InputField describes a single declared field of an object or
+// list(object) input. Fields of nested objects are described recursively.
+type InputField struct {
+	Name        string       `json:"name"`
+	Type        string       `json:"type"`
+	Description string       `json:"description,omitempty"`
+	Fields      []InputField `json:"fields,omitempty"`
 }
 
 // AnalysisError is a soft error encountered during analysis. Soft errors do
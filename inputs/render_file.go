@@ -222,12 +222,12 @@ func descendIntoDep(
 	// Seed __each__ into the parent scope for this iteration so the
 	// dep's variables block — whose defaults can legitimately reference
 	// `.__each__` — has the value the runtime would have provided.
-	// Non-for_each entries have Each=="" and skip this step entirely.
+	// Non-for_each entries have no Each and skip this step entirely.
 	scopeForChild := parentScope
-	if resolved.Each != "" {
+	if each := resolved.eachItem(); each != nil {
 		scopeForChild = make(map[string]any, len(parentScope)+1)
 		maps.Copy(scopeForChild, parentScope)
-		scopeForChild[eachVarName] = resolved.Each
+		scopeForChild[eachVarName] = each
 	}
 
 	childLoc := templateLocation{fsys: parentLoc.fsys, dir: resolved.BundlePath}
@@ -112,7 +112,7 @@ func collectDepsIndex(t *testing.T, fsys fstest.MapFS, parentDir string, parentS
 
 		forEachItems, _ := resolveForEach(context.Background(), templateLocation{fsys: fsys, dir: parentDir}, dep, scope)
 
-		iterations := []string{""}
+		iterations := []any{nil}
 		isForEach := len(forEachItems) > 0
 
 		if isForEach {
@@ -139,7 +139,7 @@ func collectDepsIndex(t *testing.T, fsys fstest.MapFS, parentDir string, parentS
 			}
 
 			if isForEach {
-				entry.Each = item
+				entry.setEachItem(item)
 			}
 
 			idx[parentKey] = append(idx[parentKey], entry)
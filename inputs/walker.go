@@ -16,6 +16,7 @@ var builtinVarNames = map[string]struct{}{
 	"BoilerplateConfigVars": {},
 	"BoilerplateConfigDeps": {},
 	"This":                  {},
+	"Deps":                  {},
 	"__each__":              {},
 }
 
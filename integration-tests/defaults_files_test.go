@@ -0,0 +1,87 @@
+package integrationtests_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/boilerplate/cli"
+)
+
+func TestDefaultsFiles(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+
+	userDefaults := "vars:\n  AuthorName: Jane\n  Region: us-east-1\n  Company: Acme\n"
+	require.NoError(t, os.MkdirAll(filepath.Join(configHome, "boilerplate"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(configHome, "boilerplate", "defaults.yml"), []byte(userDefaults), 0644))
+
+	templateDir := t.TempDir()
+	templateConfig := `variables:
+  - name: AuthorName
+  - name: Region
+    default: eu-west-1
+`
+	require.NoError(t, os.WriteFile(filepath.Join(templateDir, "boilerplate.yml"), []byte(templateConfig), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(templateDir, "target.txt"), []byte("{{ .AuthorName }} {{ .Region }}"), 0644))
+
+	projectDir := t.TempDir()
+	outputDir := filepath.Join(projectDir, "modules", "service")
+
+	run := func(extraArgs ...string) string {
+		t.Helper()
+
+		args := append([]string{
+			"boilerplate",
+			"--template-url",
+			templateDir,
+			"--output-folder",
+			outputDir,
+			"--non-interactive",
+			"--strict-vars",
+		}, extraArgs...)
+
+		require.NoError(t, cli.CreateBoilerplateCli().Run(args))
+
+		content, err := os.ReadFile(filepath.Join(outputDir, "target.txt"))
+		require.NoError(t, err)
+
+		return strings.TrimSpace(string(content))
+	}
+
+	// The user defaults stand in for the default AuthorName doesn't declare, but don't override the default of Region,
+	// and --strict-vars ignores Company, which the template doesn't declare
+	assert.Equal(t, "Jane eu-west-1", run())
+
+	// The nearest project defaults file, found walking up from the output folder, overrides the user defaults
+	projectDefaults := "vars:\n  AuthorName: Platform Team\nparallelism: 2\n"
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, cli.ProjectDefaultsFileName), []byte(projectDefaults), 0644))
+	assert.Equal(t, "Platform Team eu-west-1", run())
+
+	// Variables passed in any other way override the defaults files
+	assert.Equal(t, "John us-east-1", run("--var", "AuthorName=John", "--var", "Region=us-east-1"))
+
+	// Remote templates must match the trusted templates, if there are any
+	trustedDefaults := "trusted_templates:\n  - git@github.com:acme/*\n"
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, cli.ProjectDefaultsFileName), []byte(trustedDefaults), 0644))
+
+	err := cli.CreateBoilerplateCli().Run([]string{
+		"boilerplate",
+		"--template-url",
+		"git@github.com:someone-else/templates.git//service?ref=v1.0.0",
+		"--output-folder",
+		outputDir,
+		"--non-interactive",
+	})
+	require.ErrorContains(t, err, "does not match any of the trusted templates")
+
+	// Unknown keys in a defaults file are an error
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, cli.ProjectDefaultsFileName), []byte("varz: {}\n"), 0644))
+
+	err = cli.CreateBoilerplateCli().Run([]string{"boilerplate", "--template-url", templateDir, "--output-folder", outputDir})
+	require.ErrorAs(t, err, &cli.InvalidDefaultsFile{})
+}
@@ -55,6 +55,7 @@ type ManifestDependency struct {
 	Files                []GeneratedFile      `json:"Files,omitempty" yaml:"Files,omitempty"`
 	VarFiles             []string             `json:"VarFiles,omitempty" yaml:"VarFiles,omitempty"`
 	ForEach              []string             `json:"ForEach,omitempty" yaml:"ForEach,omitempty"`
+	ForEachValues        []any                `json:"ForEachValues,omitempty" yaml:"ForEachValues,omitempty"`
 	Dependencies         []ManifestDependency `json:"Dependencies,omitempty" yaml:"Dependencies,omitempty"`
 	DontInheritVariables bool                 `json:"DontInheritVariables,omitempty" yaml:"DontInheritVariables,omitempty"`
 }
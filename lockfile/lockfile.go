@@ -0,0 +1,150 @@
+// Package lockfile provides functionality for reading and writing boilerplate.lock files, which pin the remote
+// templates used by a template and its dependencies to an immutable source.
+package lockfile
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFilename is the name of the lock file that `boilerplate lock` writes, and that later runs use if it exists in
+// the current working directory.
+const DefaultFilename = "boilerplate.lock"
+
+const lockFileHeader = "# This file is generated by `boilerplate lock`. Do not edit it by hand.\n"
+
+// LockFile records the immutable source of each remote template used by a template and its dependencies.
+type LockFile struct {
+	Sources []Source `yaml:"sources"`
+}
+
+// Source is a remote template URL, as it is rendered for a dependency, and the checksum of the source it resolved to
+// when it was locked: "git-sha1:<commit>" or "git-sha256:<commit>" for git sources, and "sha256:<hex>" over the
+// contents of the template folder for other sources.
+type Source struct {
+	TemplateURL string `yaml:"template-url"`
+	Checksum    string `yaml:"checksum"`
+}
+
+// Load parses the lock file at the given path.
+func Load(path string) (*LockFile, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	lock := &LockFile{}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(contents))
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(lock); err != nil {
+		return nil, InvalidLockFile{Err: err, Path: path}
+	}
+
+	return lock, nil
+}
+
+// Write writes the lock file to the given path, with the sources sorted by template URL so that the file only changes
+// when the sources do.
+func (lock *LockFile) Write(path string) error {
+	sorted := &LockFile{Sources: slices.Clone(lock.Sources)}
+	slices.SortFunc(sorted.Sources, func(a, b Source) int {
+		return strings.Compare(a.TemplateURL, b.TemplateURL)
+	})
+
+	contents := bytes.NewBufferString(lockFileHeader)
+
+	encoder := yaml.NewEncoder(contents)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(sorted); err != nil {
+		return err
+	}
+
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, contents.Bytes(), 0644)
+}
+
+// Lookup returns the locked source of the given template URL, if there is one.
+func (lock *LockFile) Lookup(templateURL string) (Source, bool) {
+	for _, source := range lock.Sources {
+		if source.TemplateURL == templateURL {
+			return source, true
+		}
+	}
+
+	return Source{}, false
+}
+
+// Add records the checksum of the given template URL. Adding a template URL again is a no-op, unless it resolved to a
+// different source, which is an error.
+func (lock *LockFile) Add(templateURL string, checksum string) error {
+	if source, isLocked := lock.Lookup(templateURL); isLocked {
+		if source.Checksum != checksum {
+			return ConflictingChecksums{TemplateURL: templateURL, Checksums: []string{source.Checksum, checksum}}
+		}
+
+		return nil
+	}
+
+	lock.Sources = append(lock.Sources, Source{TemplateURL: templateURL, Checksum: checksum})
+
+	return nil
+}
+
+// PinnedURL returns the URL to download the given template URL from so that it resolves to the source with the given
+// checksum: for git sources, the ref query parameter is set to the locked commit, and the depth parameter is removed,
+// as a shallow clone can't check out a commit. Other sources can't be pinned, so their URL is returned as is, and only
+// the checksum of what they download can be verified.
+func PinnedURL(templateURL string, checksum string) string {
+	format, commit, hasFormat := strings.Cut(checksum, ":")
+	if !hasFormat || !strings.HasPrefix(format, "git-") {
+		return templateURL
+	}
+
+	base, rawQuery, _ := strings.Cut(templateURL, "?")
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return templateURL
+	}
+
+	query.Set("ref", commit)
+	query.Del("depth")
+
+	return base + "?" + query.Encode()
+}
+
+// Custom error types
+
+type InvalidLockFile struct {
+	Err  error
+	Path string
+}
+
+func (err InvalidLockFile) Error() string {
+	return fmt.Sprintf("Error parsing lock file %s: %v", err.Path, err.Err)
+}
+
+func (err InvalidLockFile) Unwrap() error {
+	return err.Err
+}
+
+type ConflictingChecksums struct {
+	TemplateURL string
+	Checksums   []string
+}
+
+func (err ConflictingChecksums) Error() string {
+	return fmt.Sprintf("The template %s resolved to different sources while locking: %s. Is it being updated? Try again.", err.TemplateURL, strings.Join(err.Checksums, ", "))
+}
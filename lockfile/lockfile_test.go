@@ -0,0 +1,77 @@
+package lockfile_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/boilerplate/lockfile"
+)
+
+func TestPinnedURL(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		templateURL string
+		checksum    string
+		expected    string
+	}{
+		{"git::https://github.com/acme/templates.git//service?ref=v1.2.0", "git-sha1:abc123", "git::https://github.com/acme/templates.git//service?ref=abc123"},
+		{"git@github.com:acme/templates.git//service?depth=1&ref=main", "git-sha256:def456", "git@github.com:acme/templates.git//service?ref=def456"},
+		{"github.com/acme/templates//service", "git-sha1:abc123", "github.com/acme/templates//service?ref=abc123"},
+		{"https://example.com/templates.zip", "sha256:0123", "https://example.com/templates.zip"},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, lockfile.PinnedURL(testCase.templateURL, testCase.checksum), testCase.templateURL)
+	}
+}
+
+func TestLockFileAdd(t *testing.T) {
+	t.Parallel()
+
+	lock := &lockfile.LockFile{}
+
+	require.NoError(t, lock.Add("github.com/acme/a", "git-sha1:abc123"))
+	require.NoError(t, lock.Add("github.com/acme/a", "git-sha1:abc123"))
+	assert.Len(t, lock.Sources, 1)
+
+	err := lock.Add("github.com/acme/a", "git-sha1:def456")
+	require.ErrorAs(t, err, &lockfile.ConflictingChecksums{})
+
+	source, isLocked := lock.Lookup("github.com/acme/a")
+	assert.True(t, isLocked)
+	assert.Equal(t, "git-sha1:abc123", source.Checksum)
+
+	_, isLocked = lock.Lookup("github.com/acme/b")
+	assert.False(t, isLocked)
+}
+
+func TestLockFileWriteAndLoad(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), lockfile.DefaultFilename)
+
+	lock := &lockfile.LockFile{Sources: []lockfile.Source{
+		{TemplateURL: "github.com/acme/b", Checksum: "sha256:0123"},
+		{TemplateURL: "github.com/acme/a", Checksum: "git-sha1:abc123"},
+	}}
+	require.NoError(t, lock.Write(path))
+
+	contents, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(contents), "# This file is generated by `boilerplate lock`"))
+
+	loaded, err := lockfile.Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, []lockfile.Source{lock.Sources[1], lock.Sources[0]}, loaded.Sources, "sources are sorted by template URL")
+
+	require.NoError(t, os.WriteFile(path, []byte("sources:\n  - url: github.com/acme/a\n"), 0644))
+
+	_, err = lockfile.Load(path)
+	require.ErrorAs(t, err, &lockfile.InvalidLockFile{})
+}
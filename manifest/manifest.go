@@ -17,6 +17,7 @@ import (
 
 	v1 "github.com/gruntwork-io/boilerplate/internal/manifest/v1"
 	v2 "github.com/gruntwork-io/boilerplate/internal/manifest/v2"
+	"github.com/gruntwork-io/boilerplate/variables"
 	"github.com/gruntwork-io/boilerplate/version"
 )
 
@@ -99,6 +100,51 @@ func ParseManifestFile(path string) (*Manifest, error) {
 	return ParseManifest(data)
 }
 
+// PreviousAnswers returns the variables recorded in the given manifest, for the root template and for each of its
+// dependencies, so they can be reused as defaults when the template is run again.
+func PreviousAnswers(m *Manifest) *variables.PreviousAnswers {
+	return &variables.PreviousAnswers{
+		Variables:    m.Variables,
+		Dependencies: previousDependencyAnswers(m.Dependencies),
+	}
+}
+
+func previousDependencyAnswers(dependencies []ManifestDependency) []variables.PreviousDependencyAnswers {
+	answers := make([]variables.PreviousDependencyAnswers, 0, len(dependencies))
+
+	for _, dependency := range dependencies {
+		// Skipped dependencies were not processed, so there are no answers to reuse
+		if dependency.Variables == nil && len(dependency.Dependencies) == 0 {
+			continue
+		}
+
+		answers = append(answers, variables.PreviousDependencyAnswers{
+			Name:    dependency.Name,
+			ForEach: forEachItems(dependency),
+			Answers: &variables.PreviousAnswers{
+				Variables:    dependency.Variables,
+				Dependencies: previousDependencyAnswers(dependency.Dependencies),
+			},
+		})
+	}
+
+	return answers
+}
+
+// Return the for_each items recorded for the given dependency, whether they are strings or other values.
+func forEachItems(dependency ManifestDependency) []any {
+	if len(dependency.ForEachValues) > 0 {
+		return dependency.ForEachValues
+	}
+
+	items := make([]any, 0, len(dependency.ForEach))
+	for _, item := range dependency.ForEach {
+		items = append(items, item)
+	}
+
+	return items
+}
+
 // WriteManifest writes the manifest to the given path. The format (JSON or YAML)
 // is auto-detected from the file extension: .json produces JSON, everything else
 // produces YAML.
@@ -392,6 +392,31 @@ func TestParseManifestRoundTrip(t *testing.T) {
 	}
 }
 
+func TestParseManifestRoundTripObjectVariables(t *testing.T) {
+	t.Parallel()
+
+	subnets := []any{
+		map[string]any{"CIDR": "10.0.0.0/24", "AZ": "us-east-1a"},
+		map[string]any{"CIDR": "10.0.1.0/24", "AZ": "us-east-1b"},
+	}
+
+	original := manifest.NewManifest("tmpl", "/out", "sha256:abc", []manifest.GeneratedFile{}, map[string]any{"Subnets": subnets}, []manifest.ManifestDependency{})
+
+	for _, filename := range []string{"manifest.json", "manifest.yaml"} {
+		t.Run(filename, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), filename)
+			require.NoError(t, manifest.WriteManifest(path, original))
+			require.NoError(t, manifest.ValidateFile(path))
+
+			parsed, err := manifest.ParseManifestFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, subnets, parsed.Variables["Subnets"])
+		})
+	}
+}
+
 func TestParseManifestRoundTripNestedDependencies(t *testing.T) {
 	t.Parallel()
 
@@ -863,3 +888,37 @@ func TestNewManifestWithNestedDependencies(t *testing.T) {
 	require.NoError(t, err)
 	require.NoError(t, manifest.Validate(data))
 }
+
+func TestPreviousAnswers(t *testing.T) {
+	t.Parallel()
+
+	m := manifest.NewManifest("./template", "./output", "sha256:abc", nil, map[string]any{"Name": "web"}, []manifest.ManifestDependency{
+		{
+			Name:      "backend",
+			Variables: map[string]any{"Owner": "team-a"},
+			Dependencies: []manifest.ManifestDependency{
+				{Name: "database", ForEach: []string{"primary"}, Variables: map[string]any{"Engine": "mysql"}},
+			},
+		},
+		{Name: "docs", Skip: "true"},
+	})
+
+	answers := manifest.PreviousAnswers(m)
+
+	name, hasName := answers.Lookup("Name")
+	assert.True(t, hasName)
+	assert.Equal(t, "web", name)
+
+	backend := answers.ForDependency("backend", "")
+	require.NotNil(t, backend)
+
+	owner, hasOwner := backend.Lookup("Owner")
+	assert.True(t, hasOwner)
+	assert.Equal(t, "team-a", owner)
+
+	database := backend.ForDependency("database", "primary")
+	require.NotNil(t, database)
+	assert.Equal(t, map[string]any{"Engine": "mysql"}, database.Variables)
+
+	assert.Nil(t, answers.ForDependency("docs", ""), "skipped dependencies have no answers to reuse")
+}
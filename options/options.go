@@ -3,6 +3,11 @@ package options
 
 import (
 	"fmt"
+
+	"github.com/gruntwork-io/boilerplate/getterhelper"
+	"github.com/gruntwork-io/boilerplate/lockfile"
+	"github.com/gruntwork-io/boilerplate/prompt"
+	"github.com/gruntwork-io/boilerplate/variables"
 )
 
 const OptTemplateURL = "template-url"
@@ -10,6 +15,10 @@ const OptOutputFolder = "output-folder"
 const OptNonInteractive = "non-interactive"
 const OptVar = "var"
 const OptVarFile = "var-file"
+const OptSet = "set"
+const OptStrictVars = "strict-vars"
+const OptReuseAnswers = "reuse-answers"
+const OptSaveAnswers = "save-answers"
 const OptMissingKeyAction = "missing-key-action"
 const OptMissingConfigAction = "missing-config-action"
 const OptNoHooks = "no-hooks"
@@ -19,18 +28,54 @@ const OptManifest = "manifest"
 const OptManifestFile = "manifest-file"
 const OptParallelism = "parallelism"
 const OptIncludeBundle = "include-bundle"
+const OptLockFile = "lock-file"
 
 // BoilerplateOptions represents the command-line options for the boilerplate app
 type BoilerplateOptions struct {
-	Vars                    map[string]any
-	ShellCommandAnswers     map[string]bool
-	OnMissingConfig         MissingConfigAction
-	TemplateFolder          string
-	OutputFolder            string
-	OnMissingKey            MissingKeyAction
-	TemplateURL             string
-	ManifestFile            string
-	NonInteractive          bool
+	Vars map[string]any
+	// SensitiveVars are the names of the variables in Vars whose source marked them as sensitive, such as sensitive
+	// outputs read from `terraform output -json`. They are treated as if they were declared with sensitive: true.
+	SensitiveVars []string
+	// DefaultsFileVars are the values of variables set in defaults files. They are kept apart from Vars, as they are
+	// only fallback defaults for the variables that don't declare a default of their own: the user is still prompted
+	// for those, with the value from the defaults file offered as the default. As defaults files are shared by all
+	// templates, --strict-vars doesn't check them.
+	DefaultsFileVars map[string]any
+	// Overrides are the values passed in via --set, which override single, possibly nested, keys of variables
+	Overrides []variables.VariableOverride
+	// Prompter asks the user for the values of variables, and whether to process dependencies and execute hooks and
+	// shell commands. If it is nil, the user is asked on the terminal.
+	Prompter prompt.Prompter
+	// SaveAnswers records the answers given at interactive prompts when --save-answers is set
+	SaveAnswers *variables.AnswerRecorder
+	// HookAnswers are the recorded decisions to execute, or not, each hook, keyed the same way as ShellCommandAnswers
+	HookAnswers map[string]bool
+	// DependencyAnswers are the recorded decisions to process, or not, each dependency, keyed by the name of the
+	// dependency, namespaced with the names of the dependencies between it and this template
+	DependencyAnswers map[string]bool
+	// PreviousAnswers are the values of variables recorded in the manifest of a previous run, which take the place of
+	// the defaults when --reuse-answers is set
+	PreviousAnswers     *variables.PreviousAnswers
+	ShellCommandAnswers map[string]bool
+	OnMissingConfig     MissingConfigAction
+	TemplateFolder      string
+	OutputFolder        string
+	OnMissingKey        MissingKeyAction
+	TemplateURL         string
+	ManifestFile        string
+	NonInteractive      bool
+	// TrustedTemplates are the patterns of the remote template URLs that may be used, including by dependencies. If
+	// there are none, any template may be used.
+	TrustedTemplates []string
+	// LockFile pins the remote templates used by the template and its dependencies to the sources recorded by
+	// `boilerplate lock`. If it is set, remote templates that are not in it, or that don't match it, are an error.
+	LockFile *lockfile.LockFile
+	// DownloadCache downloads each remote template source once per run. It is created by the root template and shared
+	// with its dependencies.
+	DownloadCache *getterhelper.DownloadCache
+	// StrictVars makes it an error to pass in a variable that isn't declared in the template or its dependencies. It
+	// is only checked for the root template, so it is not passed on to dependencies.
+	StrictVars              bool
 	NoHooks                 bool
 	NoShell                 bool
 	DisableDependencyPrompt bool
@@ -39,6 +84,16 @@ type BoilerplateOptions struct {
 	Parallelism             int
 }
 
+// GetPrompter returns the Prompter to ask the user questions with: the one set in the options, or the one that asks on
+// the terminal if there is none.
+func (opts *BoilerplateOptions) GetPrompter() prompt.Prompter {
+	if opts.Prompter == nil {
+		return prompt.DefaultPrompter()
+	}
+
+	return opts.Prompter
+}
+
 // MissingKeyAction is an enum that represents what we can do when a template looks up a missing key. This typically happens
 // when there is a typo in the variable name in a template.
 type MissingKeyAction string
@@ -1,6 +1,8 @@
 // Package prompt provides interactive user prompting.
 package prompt
 
+import "errors"
+
 // UserResponse represents the user's response to a yes/no/all prompt
 type UserResponse string
 
@@ -9,3 +11,57 @@ const (
 	UserResponseNo  UserResponse = "no"
 	UserResponseAll UserResponse = "all"
 )
+
+// GoBackInput is what the user enters at a text prompt to go back to the previous question
+const GoBackInput = "<"
+
+// GoBackOption is the option added to select prompts to go back to the previous question
+const GoBackOption = "« Back"
+
+// ErrGoBack is returned by the prompts of a Prompter when the user asks to go back to the previous question
+var ErrGoBack = errors.New("go back to the previous question")
+
+// Question is a question to ask the user with a Prompter.
+type Question struct {
+	// Validate checks the answer as it is entered, so the user can correct it without leaving the prompt. It is called
+	// with a string for text prompts and a []string for MultiSelect.
+	Validate func(answer any) error
+	// Name identifies the question for prompters that answer questions without asking the user, such as the name of
+	// the variable the question asks for
+	Name string
+	// Message is the question itself
+	Message string
+	// Help is shown when the user asks for help
+	Help string
+	// Default is the answer that is selected at first in Select and Confirm. For MultiSelect, Defaults is used instead.
+	// Text prompts show the default in their message, and return an empty string when the user enters nothing.
+	Default  string
+	Defaults []string
+	// Options are the answers to choose from with Select and MultiSelect
+	Options []string
+	// Sensitive questions don't echo the answer back to the terminal
+	Sensitive bool
+	// CanGoBack offers the user to go back to the previous question, in which case the prompt returns ErrGoBack
+	CanGoBack bool
+}
+
+// Prompter asks the user questions: the values of variables, and whether to process dependencies and execute hooks
+// and shell commands. Programs that embed boilerplate can implement it to ask the questions through their own UI.
+type Prompter interface {
+	// Group shows a heading for the group of questions that the next questions belong to, if it is not already shown
+	Group(name string)
+	// Input asks for a single line of text
+	Input(question Question) (string, error)
+	// Multiline asks for several lines of text, such as one item of a list per line
+	Multiline(question Question) (string, error)
+	// Select asks to pick one of the options of the question
+	Select(question Question) (string, error)
+	// MultiSelect asks to pick any number of the options of the question
+	MultiSelect(question Question) ([]string, error)
+	// Confirm asks for the value of a bool variable
+	Confirm(question Question) (bool, error)
+	// YesNo asks whether to go ahead with something, such as processing a dependency
+	YesNo(question Question) (bool, error)
+	// YesNoAll asks whether to go ahead with something, such as executing a hook, or with it and everything like it
+	YesNoAll(question Question) (UserResponse, error)
+}
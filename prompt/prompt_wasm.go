@@ -18,3 +18,47 @@ func PromptUserForYesNo(p string) (bool, error) {
 func PromptUserForYesNoAll(p string) (UserResponse, error) {
 	return UserResponseNo, fmt.Errorf("interactive prompts are not supported in WASM")
 }
+
+// DefaultPrompter returns a StdinPrompter, whose prompts are not supported in WASM builds.
+func DefaultPrompter() Prompter {
+	return &StdinPrompter{}
+}
+
+// StdinPrompter is a stub for WASM builds where interactive prompts are not supported. Use a ScriptedPrompter or a
+// DeclinePrompter instead.
+type StdinPrompter struct{}
+
+// NewStdinPrompter is a stub for WASM builds where interactive prompts are not supported.
+func NewStdinPrompter() *StdinPrompter {
+	return &StdinPrompter{}
+}
+
+func (prompter *StdinPrompter) Group(name string) {}
+
+func (prompter *StdinPrompter) Input(question Question) (string, error) {
+	return "", fmt.Errorf("interactive prompts are not supported in WASM")
+}
+
+func (prompter *StdinPrompter) Multiline(question Question) (string, error) {
+	return "", fmt.Errorf("interactive prompts are not supported in WASM")
+}
+
+func (prompter *StdinPrompter) Select(question Question) (string, error) {
+	return "", fmt.Errorf("interactive prompts are not supported in WASM")
+}
+
+func (prompter *StdinPrompter) MultiSelect(question Question) ([]string, error) {
+	return nil, fmt.Errorf("interactive prompts are not supported in WASM")
+}
+
+func (prompter *StdinPrompter) Confirm(question Question) (bool, error) {
+	return false, fmt.Errorf("interactive prompts are not supported in WASM")
+}
+
+func (prompter *StdinPrompter) YesNo(question Question) (bool, error) {
+	return PromptUserForYesNo(question.Message)
+}
+
+func (prompter *StdinPrompter) YesNoAll(question Question) (UserResponse, error) {
+	return PromptUserForYesNoAll(question.Message)
+}
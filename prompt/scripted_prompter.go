@@ -0,0 +1,332 @@
+package prompt
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ScriptedPrompter is a Prompter that answers questions from a map instead of asking the user, e.g. to cover the
+// interactive code paths in tests. Each question is answered with the entry for its Name or, if there is none, for
+// its Message, such as "Process dependency 'backend'?". Answers are checked with the Validate function of the
+// question, and it is an error to ask a question that has no answer.
+type ScriptedPrompter struct {
+	Answers map[string]any
+}
+
+// NewScriptedPrompter creates a ScriptedPrompter that answers questions from the given map.
+func NewScriptedPrompter(answers map[string]any) *ScriptedPrompter {
+	return &ScriptedPrompter{Answers: answers}
+}
+
+// LoadScriptedPrompter creates a ScriptedPrompter that answers questions from the YAML or JSON map in the file at the
+// given path.
+func LoadScriptedPrompter(path string) (*ScriptedPrompter, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	answers := map[string]any{}
+	if err := yaml.Unmarshal(contents, &answers); err != nil {
+		return nil, InvalidScriptedAnswers{Path: path, Err: err}
+	}
+
+	return NewScriptedPrompter(answers), nil
+}
+
+// Group does nothing, as there is no one to show a heading to.
+func (prompter *ScriptedPrompter) Group(name string) {}
+
+// Input answers with the scripted answer, formatted as YAML if it is not a string.
+func (prompter *ScriptedPrompter) Input(question Question) (string, error) {
+	answer, err := prompter.answer(question)
+	if err != nil {
+		return "", err
+	}
+
+	text, err := answerText(answer)
+	if err != nil {
+		return "", InvalidScriptedAnswer{Question: questionLabel(question), Err: err}
+	}
+
+	return text, validateAnswer(question, text)
+}
+
+// Multiline answers with the scripted answer, formatted as YAML if it is not a string, so that lists are answered
+// with one item per line.
+func (prompter *ScriptedPrompter) Multiline(question Question) (string, error) {
+	return prompter.Input(question)
+}
+
+// Select answers with the scripted answer, which must be one of the options of the question.
+func (prompter *ScriptedPrompter) Select(question Question) (string, error) {
+	answer, err := prompter.Input(question)
+	if err != nil {
+		return "", err
+	}
+
+	if !slices.Contains(question.Options, answer) {
+		return "", InvalidScriptedAnswer{Question: questionLabel(question), Err: fmt.Errorf("%q is not one of %v", answer, question.Options)}
+	}
+
+	return answer, nil
+}
+
+// MultiSelect answers with the scripted answer, which must be a list of options of the question.
+func (prompter *ScriptedPrompter) MultiSelect(question Question) ([]string, error) {
+	answer, err := prompter.answer(question)
+	if err != nil {
+		return nil, err
+	}
+
+	list, isList := answer.([]any)
+	if !isList {
+		return nil, InvalidScriptedAnswer{Question: questionLabel(question), Err: fmt.Errorf("expected a list, got %v", answer)}
+	}
+
+	selected := make([]string, 0, len(list))
+
+	for _, item := range list {
+		option := fmt.Sprint(item)
+		if !slices.Contains(question.Options, option) {
+			return nil, InvalidScriptedAnswer{Question: questionLabel(question), Err: fmt.Errorf("%q is not one of %v", option, question.Options)}
+		}
+
+		selected = append(selected, option)
+	}
+
+	return selected, validateAnswer(question, selected)
+}
+
+// Confirm answers with the scripted answer, which must be a bool, or yes or no.
+func (prompter *ScriptedPrompter) Confirm(question Question) (bool, error) {
+	return prompter.YesNo(question)
+}
+
+// YesNo answers with the scripted answer, which must be a bool, or yes or no.
+func (prompter *ScriptedPrompter) YesNo(question Question) (bool, error) {
+	response, err := prompter.YesNoAll(question)
+	if err != nil {
+		return false, err
+	}
+
+	if response == UserResponseAll {
+		return false, InvalidScriptedAnswer{Question: questionLabel(question), Err: fmt.Errorf("expected yes or no, got %s", response)}
+	}
+
+	return response == UserResponseYes, nil
+}
+
+// YesNoAll answers with the scripted answer, which must be a bool, or yes, no or all.
+func (prompter *ScriptedPrompter) YesNoAll(question Question) (UserResponse, error) {
+	answer, err := prompter.answer(question)
+	if err != nil {
+		return UserResponseNo, err
+	}
+
+	response, isResponse := parseUserResponse(answer)
+	if !isResponse {
+		return UserResponseNo, InvalidScriptedAnswer{Question: questionLabel(question), Err: fmt.Errorf("expected yes, no or all, got %v", answer)}
+	}
+
+	return response, nil
+}
+
+// Return the scripted answer to the given question.
+func (prompter *ScriptedPrompter) answer(question Question) (any, error) {
+	if answer, hasAnswer := prompter.Answers[question.Name]; hasAnswer && question.Name != "" {
+		return answer, nil
+	}
+
+	if answer, hasAnswer := prompter.Answers[question.Message]; hasAnswer {
+		return answer, nil
+	}
+
+	return nil, NoScriptedAnswer{Question: questionLabel(question)}
+}
+
+// DeclinePrompter is a Prompter that declines everything, without asking the user: it doesn't process dependencies
+// or execute hooks and shell commands, and it answers the questions for variables with their defaults. It is an error
+// to ask for a variable that has no default.
+type DeclinePrompter struct{}
+
+// NewDeclinePrompter creates a new DeclinePrompter.
+func NewDeclinePrompter() *DeclinePrompter {
+	return &DeclinePrompter{}
+}
+
+// Group does nothing, as there is no one to show a heading to.
+func (prompter *DeclinePrompter) Group(name string) {}
+
+// Input answers with an empty string, which means the default, if the default is valid.
+func (prompter *DeclinePrompter) Input(question Question) (string, error) {
+	if err := validateAnswer(question, ""); err != nil {
+		return "", DeclinedQuestion{Question: questionLabel(question), Err: err}
+	}
+
+	return "", nil
+}
+
+// Multiline answers with an empty string, which means the default, if the default is valid.
+func (prompter *DeclinePrompter) Multiline(question Question) (string, error) {
+	return prompter.Input(question)
+}
+
+// Select answers with the default, if it is one of the options.
+func (prompter *DeclinePrompter) Select(question Question) (string, error) {
+	if !slices.Contains(question.Options, question.Default) {
+		return "", DeclinedQuestion{Question: questionLabel(question)}
+	}
+
+	return question.Default, nil
+}
+
+// MultiSelect answers with the defaults that are options.
+func (prompter *DeclinePrompter) MultiSelect(question Question) ([]string, error) {
+	selected := []string{}
+
+	for _, option := range question.Defaults {
+		if slices.Contains(question.Options, option) {
+			selected = append(selected, option)
+		}
+	}
+
+	if err := validateAnswer(question, selected); err != nil {
+		return nil, DeclinedQuestion{Question: questionLabel(question), Err: err}
+	}
+
+	return selected, nil
+}
+
+// Confirm answers with the default, or no if there is none.
+func (prompter *DeclinePrompter) Confirm(question Question) (bool, error) {
+	confirmed, _ := strconv.ParseBool(question.Default)
+	return confirmed, nil
+}
+
+// YesNo answers no.
+func (prompter *DeclinePrompter) YesNo(question Question) (bool, error) {
+	return false, nil
+}
+
+// YesNoAll answers no.
+func (prompter *DeclinePrompter) YesNoAll(question Question) (UserResponse, error) {
+	return UserResponseNo, nil
+}
+
+// Return the given scripted answer as text: strings as is, and other values as YAML.
+func answerText(answer any) (string, error) {
+	switch typed := answer.(type) {
+	case string:
+		return typed, nil
+	case nil:
+		return "", nil
+	case []any, map[string]any:
+		asYAML, err := yaml.Marshal(typed)
+		return string(asYAML), err
+	default:
+		return fmt.Sprint(typed), nil
+	}
+}
+
+// Parse the given scripted answer as a bool, or as yes, no or all.
+func parseUserResponse(answer any) (UserResponse, bool) {
+	if asBool, isBool := answer.(bool); isBool {
+		if asBool {
+			return UserResponseYes, true
+		}
+
+		return UserResponseNo, true
+	}
+
+	switch strings.ToLower(fmt.Sprint(answer)) {
+	case "y", "yes", "true":
+		return UserResponseYes, true
+	case "n", "no", "false":
+		return UserResponseNo, true
+	case "a", "all":
+		return UserResponseAll, true
+	default:
+		return UserResponseNo, false
+	}
+}
+
+// Check the given answer with the Validate function of the given question, if it has one.
+func validateAnswer(question Question, answer any) error {
+	if question.Validate == nil {
+		return nil
+	}
+
+	if err := question.Validate(answer); err != nil {
+		return InvalidScriptedAnswer{Question: questionLabel(question), Err: err}
+	}
+
+	return nil
+}
+
+// Return the name of the given question to use in errors.
+func questionLabel(question Question) string {
+	if question.Name != "" {
+		return question.Name
+	}
+
+	return question.Message
+}
+
+// Custom error types
+
+type NoScriptedAnswer struct {
+	Question string
+}
+
+func (err NoScriptedAnswer) Error() string {
+	return fmt.Sprintf("There is no scripted answer for the question '%s'", err.Question)
+}
+
+type InvalidScriptedAnswer struct {
+	Err      error
+	Question string
+}
+
+func (err InvalidScriptedAnswer) Error() string {
+	return fmt.Sprintf("Invalid answer for the question '%s': %v", err.Question, err.Err)
+}
+
+func (err InvalidScriptedAnswer) Unwrap() error {
+	return err.Err
+}
+
+type InvalidScriptedAnswers struct {
+	Err  error
+	Path string
+}
+
+func (err InvalidScriptedAnswers) Error() string {
+	return fmt.Sprintf("Could not parse the scripted answers in %s: %v", err.Path, err.Err)
+}
+
+func (err InvalidScriptedAnswers) Unwrap() error {
+	return err.Err
+}
+
+type DeclinedQuestion struct {
+	Err      error
+	Question string
+}
+
+func (err DeclinedQuestion) Error() string {
+	if err.Err == nil {
+		return fmt.Sprintf("Declined to answer the question '%s', which has no default", err.Question)
+	}
+
+	return fmt.Sprintf("Declined to answer the question '%s', and its default is not valid: %v", err.Question, err.Err)
+}
+
+func (err DeclinedQuestion) Unwrap() error {
+	return err.Err
+}
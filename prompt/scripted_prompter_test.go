@@ -0,0 +1,122 @@
+package prompt //nolint:testpackage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScriptedPrompter(t *testing.T) {
+	t.Parallel()
+
+	prompter := NewScriptedPrompter(map[string]any{
+		"Name":            "web",
+		"Tags":            []any{"a", "b"},
+		"Env":             "prod",
+		"Components":      []any{"api"},
+		"Enabled":         true,
+		"Execute hook?":   "all",
+		"backend":         "n",
+		"Port":            "70000",
+		"Execute script?": "maybe",
+	})
+
+	name, err := prompter.Input(Question{Name: "Name", Message: "Name"})
+	require.NoError(t, err)
+	assert.Equal(t, "web", name)
+
+	tags, err := prompter.Multiline(Question{Name: "Tags"})
+	require.NoError(t, err)
+	assert.Equal(t, "- a\n- b\n", tags)
+
+	env, err := prompter.Select(Question{Name: "Env", Options: []string{"dev", "prod"}})
+	require.NoError(t, err)
+	assert.Equal(t, "prod", env)
+
+	components, err := prompter.MultiSelect(Question{Name: "Components", Options: []string{"api", "worker"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api"}, components)
+
+	enabled, err := prompter.Confirm(Question{Name: "Enabled"})
+	require.NoError(t, err)
+	assert.True(t, enabled)
+
+	// Questions without an answer for their name are answered by their message
+	response, err := prompter.YesNoAll(Question{Name: "abc123", Message: "Execute hook?"})
+	require.NoError(t, err)
+	assert.Equal(t, UserResponseAll, response)
+
+	process, err := prompter.YesNo(Question{Name: "backend", Message: "Process dependency 'backend'?"})
+	require.NoError(t, err)
+	assert.False(t, process)
+
+	_, err = prompter.Input(Question{Name: "Port", Validate: func(answer any) error { return errors.New("too big") }})
+	require.ErrorAs(t, err, &InvalidScriptedAnswer{})
+
+	_, err = prompter.Select(Question{Name: "Env", Options: []string{"dev"}})
+	require.ErrorAs(t, err, &InvalidScriptedAnswer{})
+
+	_, err = prompter.YesNoAll(Question{Message: "Execute script?"})
+	require.ErrorAs(t, err, &InvalidScriptedAnswer{})
+
+	_, err = prompter.Input(Question{Name: "Missing"})
+	require.ErrorAs(t, err, &NoScriptedAnswer{})
+}
+
+func TestLoadScriptedPrompter(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "answers.yml")
+	require.NoError(t, os.WriteFile(path, []byte("Name: web\nExecute shell command?: yes\n"), 0644))
+
+	prompter, err := LoadScriptedPrompter(path)
+	require.NoError(t, err)
+
+	name, err := prompter.Input(Question{Name: "Name"})
+	require.NoError(t, err)
+	assert.Equal(t, "web", name)
+
+	response, err := prompter.YesNoAll(Question{Message: "Execute shell command?"})
+	require.NoError(t, err)
+	assert.Equal(t, UserResponseYes, response)
+}
+
+func TestDeclinePrompter(t *testing.T) {
+	t.Parallel()
+
+	prompter := NewDeclinePrompter()
+
+	process, err := prompter.YesNo(Question{Message: "Process dependency 'backend'?"})
+	require.NoError(t, err)
+	assert.False(t, process)
+
+	response, err := prompter.YesNoAll(Question{Message: "Execute hook?"})
+	require.NoError(t, err)
+	assert.Equal(t, UserResponseNo, response)
+
+	name, err := prompter.Input(Question{Name: "Name", Default: "web"})
+	require.NoError(t, err)
+	assert.Empty(t, name)
+
+	env, err := prompter.Select(Question{Name: "Env", Default: "dev", Options: []string{"dev", "prod"}})
+	require.NoError(t, err)
+	assert.Equal(t, "dev", env)
+
+	components, err := prompter.MultiSelect(Question{Name: "Components", Defaults: []string{"api"}, Options: []string{"api", "worker"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api"}, components)
+
+	enabled, err := prompter.Confirm(Question{Name: "Enabled", Default: "true"})
+	require.NoError(t, err)
+	assert.True(t, enabled)
+
+	_, err = prompter.Input(Question{Name: "Port", Validate: func(answer any) error { return errors.New("required") }})
+	require.ErrorAs(t, err, &DeclinedQuestion{})
+
+	_, err = prompter.Select(Question{Name: "Env", Options: []string{"dev", "prod"}})
+	require.ErrorAs(t, err, &DeclinedQuestion{})
+}
@@ -0,0 +1,241 @@
+//go:build !(js && wasm)
+
+package prompt
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/AlecAivazis/survey/v2/core"
+
+	"github.com/gruntwork-io/boilerplate/internal/color"
+)
+
+// The options of the select prompt that Confirm shows
+const (
+	yesOption = "Yes"
+	noOption  = "No"
+)
+
+// defaultPrompter is the Prompter used when none is set in the options
+var defaultPrompter = NewStdinPrompter()
+
+// DefaultPrompter returns the Prompter that asks questions in the terminal, which is used when no other Prompter is
+// set.
+func DefaultPrompter() Prompter {
+	return defaultPrompter
+}
+
+// StdinPrompter is a Prompter that asks questions in the terminal and reads the answers from stdin, with arrow-key
+// navigation for the prompts with options and validation messages shown as the answer is entered.
+type StdinPrompter struct {
+	// group is the name of the group whose heading was shown last
+	group string
+}
+
+// NewStdinPrompter creates a new StdinPrompter.
+func NewStdinPrompter() *StdinPrompter {
+	return &StdinPrompter{}
+}
+
+// Group shows a heading with the given name, unless it is the name of the group whose heading was shown last.
+func (prompter *StdinPrompter) Group(name string) {
+	if name == prompter.group {
+		return
+	}
+
+	prompter.group = name
+	if name == "" {
+		return
+	}
+
+	fmt.Println()
+	fmt.Println(color.BoldGreen("== " + name + " =="))
+}
+
+// Input asks for a single line of text. Entering nothing returns an empty string.
+func (prompter *StdinPrompter) Input(question Question) (string, error) {
+	var prompt survey.Prompt = &survey.Input{Message: question.Message, Help: helpText(question, "Enter "+GoBackInput)}
+	if question.Sensitive {
+		// Don't echo the value of sensitive variables, such as passwords, back to the terminal
+		prompt = &survey.Password{Message: question.Message, Help: helpText(question, "Enter "+GoBackInput)}
+	}
+
+	answer := ""
+	if err := survey.AskOne(prompt, &answer, validator(question)); err != nil {
+		return "", err
+	}
+
+	if question.CanGoBack && strings.TrimSpace(answer) == GoBackInput {
+		return "", ErrGoBack
+	}
+
+	return answer, nil
+}
+
+// Multiline asks for several lines of text, which ends with an empty line. Entering nothing returns an empty string.
+func (prompter *StdinPrompter) Multiline(question Question) (string, error) {
+	prompt := &survey.Multiline{Message: question.Message, Help: helpText(question, "Enter "+GoBackInput+" on the first line")}
+
+	answer := ""
+	if err := survey.AskOne(prompt, &answer, validator(question)); err != nil {
+		return "", err
+	}
+
+	if question.CanGoBack && strings.TrimSpace(answer) == GoBackInput {
+		return "", ErrGoBack
+	}
+
+	return answer, nil
+}
+
+// Select asks to pick one of the options of the question. The default of the question is selected at first.
+func (prompter *StdinPrompter) Select(question Question) (string, error) {
+	prompt := &survey.Select{
+		Message: question.Message,
+		Help:    helpText(question, "Select "+GoBackOption),
+		Options: withGoBackOption(question.Options, question.CanGoBack),
+	}
+
+	if slices.Contains(question.Options, question.Default) {
+		prompt.Default = question.Default
+	}
+
+	answer := ""
+	if err := survey.AskOne(prompt, &answer, validator(question)); err != nil {
+		return "", err
+	}
+
+	if answer == GoBackOption {
+		return "", ErrGoBack
+	}
+
+	return answer, nil
+}
+
+// MultiSelect asks to pick any number of the options of the question. The defaults of the question are picked at first.
+func (prompter *StdinPrompter) MultiSelect(question Question) ([]string, error) {
+	prompt := &survey.MultiSelect{
+		Message: question.Message,
+		Help:    helpText(question, "Pick "+GoBackOption),
+		Options: withGoBackOption(question.Options, question.CanGoBack),
+	}
+
+	defaults := []string{}
+
+	for _, option := range question.Defaults {
+		if slices.Contains(question.Options, option) {
+			defaults = append(defaults, option)
+		}
+	}
+
+	prompt.Default = defaults
+
+	answer := []string{}
+	if err := survey.AskOne(prompt, &answer, validator(question)); err != nil {
+		return nil, err
+	}
+
+	if slices.Contains(answer, GoBackOption) {
+		return nil, ErrGoBack
+	}
+
+	return answer, nil
+}
+
+// Confirm asks a yes or no question, which is toggled with the arrow keys. The default of the question is parsed as a
+// bool and selected at first.
+func (prompter *StdinPrompter) Confirm(question Question) (bool, error) {
+	selectQuestion := question
+	selectQuestion.Options = []string{yesOption, noOption}
+	selectQuestion.Default = ""
+
+	if question.Validate != nil {
+		selectQuestion.Validate = func(answer any) error {
+			return question.Validate(strconv.FormatBool(answer == yesOption))
+		}
+	}
+
+	if defaultValue, err := strconv.ParseBool(question.Default); err == nil {
+		selectQuestion.Default = noOption
+		if defaultValue {
+			selectQuestion.Default = yesOption
+		}
+	}
+
+	answer, err := prompter.Select(selectQuestion)
+	if err != nil {
+		return false, err
+	}
+
+	return answer == yesOption, nil
+}
+
+// YesNo asks the question and reads a y or n answer.
+func (prompter *StdinPrompter) YesNo(question Question) (bool, error) {
+	return PromptUserForYesNo(question.Message)
+}
+
+// YesNoAll asks the question and reads a y, a or n answer.
+func (prompter *StdinPrompter) YesNoAll(question Question) (UserResponse, error) {
+	return PromptUserForYesNoAll(question.Message)
+}
+
+// Return the help text for the given question, including how to go back to the previous question, if the user can.
+func helpText(question Question, goBack string) string {
+	if !question.CanGoBack {
+		return question.Help
+	}
+
+	return strings.TrimSpace(question.Help + "\n" + goBack + " to go back to the previous question.")
+}
+
+// Return the given options, followed by GoBackOption if the user can go back to the previous question.
+func withGoBackOption(options []string, canGoBack bool) []string {
+	if !canGoBack {
+		return options
+	}
+
+	return append(slices.Clone(options), GoBackOption)
+}
+
+// Return the option to validate answers with the Validate function of the given question, as they are entered.
+// Answers that go back to the previous question are not validated.
+func validator(question Question) survey.AskOpt {
+	return survey.WithValidator(func(answer any) error {
+		if question.Validate == nil {
+			return nil
+		}
+
+		switch typed := answer.(type) {
+		case string:
+			if question.CanGoBack && strings.TrimSpace(typed) == GoBackInput {
+				return nil
+			}
+
+			return question.Validate(typed)
+		case core.OptionAnswer:
+			if typed.Value == GoBackOption {
+				return nil
+			}
+
+			return question.Validate(typed.Value)
+		case []core.OptionAnswer:
+			selected := make([]string, 0, len(typed))
+			for _, option := range typed {
+				if option.Value == GoBackOption {
+					return nil
+				}
+
+				selected = append(selected, option.Value)
+			}
+
+			return question.Validate(selected)
+		default:
+			return nil
+		}
+	})
+}
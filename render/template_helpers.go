@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"maps"
 	"math"
+	"net"
 	"os"
 	"path"
 	"path/filepath"
@@ -94,6 +95,8 @@ func CreateTemplateHelpers(ctx context.Context, l logging.Logger, templatePath s
 		"numRange":   slice,
 		"keysSorted": keys,
 
+		"cidrContains": cidrContains,
+
 		"snippet":    wrapWithTemplatePath(ctx, l, templatePath, opts, snippet),
 		"include":    wrapIncludeWithTemplatePath(ctx, l, templatePath, opts),
 		"shell":      wrapWithTemplatePath(ctx, l, templatePath, opts, shell),
@@ -683,7 +686,12 @@ func shell(ctx context.Context, l logging.Logger, templatePath string, opts *opt
 	workingDir := filepath.Dir(templatePath)
 	shellKey := generateShellCommandKey(args, envVars, workingDir)
 
-	// Auto-confirm all if non-interactive
+	// Auto-confirm all if non-interactive, except for shell commands declined in a var file written by --save-answers
+	if confirmed, seen := opts.ShellCommandAnswers[shellKey]; opts.NonInteractive && seen && !confirmed {
+		l.Warnf("Skipping shell command (previously declined)")
+		return shellDisabledPlaceholder, nil
+	}
+
 	if opts.NonInteractive {
 		opts.ShellCommandAnswers[shellKey] = true
 
@@ -707,11 +715,13 @@ func shell(ctx context.Context, l logging.Logger, templatePath string, opts *opt
 	// Handle user confirmation
 	printShellCommandDetails(l, args, envVars, workingDir)
 
-	resp, err := prompt.PromptUserForYesNoAll("Execute shell command?")
+	resp, err := opts.GetPrompter().YesNoAll(prompt.Question{Name: shellKey, Message: "Execute shell command?"})
 	if err != nil {
 		return "", err
 	}
 
+	opts.SaveAnswers.RecordShellCommand(shellKey, resp != prompt.UserResponseNo)
+
 	switch resp {
 	case prompt.UserResponseYes:
 		opts.ShellCommandAnswers[shellKey] = true
@@ -868,6 +878,34 @@ func boilerplateConfigVars(opts *options.BoilerplateOptions) func(string, string
 	}
 }
 
+// cidrContains returns true if the given CIDR block contains the given IP address or CIDR block. This is useful in
+// expr() validations, such as checking that a subnet is inside the CIDR block of its VPC.
+func cidrContains(cidr string, ipOrCIDR string) (bool, error) {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return false, err
+	}
+
+	if !strings.Contains(ipOrCIDR, "/") {
+		ip := net.ParseIP(ipOrCIDR)
+		if ip == nil {
+			return false, InvalidIPAddress(ipOrCIDR)
+		}
+
+		return network.Contains(ip), nil
+	}
+
+	_, subnet, err := net.ParseCIDR(ipOrCIDR)
+	if err != nil {
+		return false, err
+	}
+
+	networkPrefix, networkBits := network.Mask.Size()
+	subnetPrefix, subnetBits := subnet.Mask.Size()
+
+	return networkBits == subnetBits && subnetPrefix >= networkPrefix && network.Contains(subnet.IP), nil
+}
+
 // Custom errors
 
 type SnippetNotFound string
@@ -896,6 +934,12 @@ func (NoArgsPassedToShellHelper) Error() string {
 
 var ErrNoArgsPassedToShellHelper = NoArgsPassedToShellHelper{}
 
+type InvalidIPAddress string
+
+func (ip InvalidIPAddress) Error() string {
+	return fmt.Sprintf("'%s' is not a valid IP address or CIDR block", string(ip))
+}
+
 type InvalidTypeForMethodArgument struct {
 	MethodName   string
 	ExpectedType string
@@ -454,6 +454,36 @@ func TestLowerFirst(t *testing.T) {
 	}
 }
 
+func TestCidrContains(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		cidr     string
+		ipOrCIDR string
+		expected bool
+	}{
+		{"10.0.0.0/16", "10.0.1.0/24", true},
+		{"10.0.0.0/16", "10.0.0.0/16", true},
+		{"10.0.0.0/16", "10.1.0.0/24", false},
+		{"10.0.0.0/16", "10.0.0.0/8", false},
+		{"10.0.0.0/16", "10.0.200.7", true},
+		{"10.0.0.0/16", "192.168.0.1", false},
+		{"10.0.0.0/16", "2001:db8::/64", false},
+	}
+
+	for _, testCase := range testCases {
+		actual, err := cidrContains(testCase.cidr, testCase.ipOrCIDR)
+		require.NoError(t, err)
+		assert.Equal(t, testCase.expected, actual, "When calling cidrContains on '%s' and '%s'", testCase.cidr, testCase.ipOrCIDR)
+	}
+
+	_, err := cidrContains("10.0.0.0/16", "not-an-ip")
+	require.ErrorAs(t, err, new(InvalidIPAddress))
+
+	_, err = cidrContains("10.0.0.0", "10.0.0.1")
+	require.Error(t, err)
+}
+
 func TestShellSuccess(t *testing.T) {
 	t.Parallel()
 
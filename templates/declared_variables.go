@@ -0,0 +1,201 @@
+package templates
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/gruntwork-io/boilerplate/config"
+	"github.com/gruntwork-io/boilerplate/getterhelper"
+	"github.com/gruntwork-io/boilerplate/options"
+	"github.com/gruntwork-io/boilerplate/pkg/logging"
+	"github.com/gruntwork-io/boilerplate/render"
+	"github.com/gruntwork-io/boilerplate/variables"
+)
+
+// maxDeclaredVariablesDepth limits how deep we follow dependencies when loading declared variables, in case of cycles
+const maxDeclaredVariablesDepth = 20
+
+// DeclaredVariables contains the variables declared in a template and, recursively, in its dependencies. It is used to
+// document the variables a template accepts, e.g. in `boilerplate vars example` and `boilerplate vars schema`.
+type DeclaredVariables struct {
+	TemplateURL  string
+	Variables    []variables.Variable
+	Dependencies []DeclaredDependency
+}
+
+// DeclaredDependency contains the variables declared in the template of a dependency.
+type DeclaredDependency struct {
+	Dependency *variables.Dependency
+	// Variables is nil if the template of the dependency could not be loaded, e.g. because its template-url depends
+	// on the value of a variable that was not passed in. Err explains why.
+	Variables *DeclaredVariables
+	Err       error
+}
+
+// LoadDeclaredVariables loads the variables declared in the template at opts.TemplateURL and in all its dependencies,
+// downloading remote templates as necessary. The template-url of each dependency is rendered with opts.Vars and the
+// defaults of the declared variables, so dependencies whose template-url depends on variables without a default can be
+// loaded by passing those variables in.
+func LoadDeclaredVariables(ctx context.Context, l logging.Logger, opts *options.BoilerplateOptions) (*DeclaredVariables, error) {
+	return loadDeclaredVariables(ctx, l, opts, nil, 0)
+}
+
+func loadDeclaredVariables(
+	ctx context.Context,
+	l logging.Logger,
+	opts *options.BoilerplateOptions,
+	dependency *variables.Dependency,
+	depth int,
+) (*DeclaredVariables, error) {
+	cleanup, _, err := resolveTemplate(l, opts)
+	if cleanup != nil {
+		defer cleanup()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	boilerplateConfig, err := config.LoadBoilerplateConfig(l, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	declared := &DeclaredVariables{
+		TemplateURL: opts.TemplateURL,
+		Variables:   declaredVariablesForTemplate(boilerplateConfig, dependency),
+	}
+
+	vars := resolveVariablesForDependencies(ctx, l, opts, boilerplateConfig, dependency)
+
+	for i := range boilerplateConfig.Dependencies {
+		childDependency := &boilerplateConfig.Dependencies[i]
+		declaredDependency := DeclaredDependency{Dependency: childDependency}
+
+		childOpts, optsErr := optionsForDeclaredDependency(ctx, l, opts, childDependency, vars)
+
+		switch {
+		case optsErr != nil:
+			declaredDependency.Err = optsErr
+		case depth >= maxDeclaredVariablesDepth:
+			declaredDependency.Err = DependencyTooDeep(childDependency.Name)
+		default:
+			declaredDependency.Variables, declaredDependency.Err = loadDeclaredVariables(ctx, l, childOpts, childDependency, depth+1)
+		}
+
+		if declaredDependency.Err != nil {
+			l.Debugf("Could not load the variables of dependency '%s': %v", childDependency.Name, declaredDependency.Err)
+		}
+
+		declared.Dependencies = append(declared.Dependencies, declaredDependency)
+	}
+
+	return declared, nil
+}
+
+// Return the variables declared in the given config, sorted by their order. If the config is for a dependency, the
+// variables set in the dependency block of the parent config take the place of the ones declared in the config, as
+// those are the values the dependency actually gets.
+func declaredVariablesForTemplate(boilerplateConfig *config.BoilerplateConfig, dependency *variables.Dependency) []variables.Variable {
+	declared := slices.Clone(boilerplateConfig.Variables)
+
+	if dependency != nil {
+		for _, dependencyVariable := range dependency.Variables {
+			index := slices.IndexFunc(declared, func(variable variables.Variable) bool {
+				return variable.Name() == dependencyVariable.Name()
+			})
+
+			if index >= 0 {
+				declared[index] = dependencyVariable
+			} else {
+				declared = append(declared, dependencyVariable)
+			}
+		}
+	}
+
+	slices.SortStableFunc(declared, func(a, b variables.Variable) int {
+		return a.Order() - b.Order()
+	})
+
+	return declared
+}
+
+// Return the variables to render the template-url and for_each_reference of the dependencies of the given config with:
+// the values passed in via opts and the defaults of the variables declared in the config, as processDependency does.
+// Nobody is prompted, so variables that have no value are left out, and only the dependencies that use them fail to
+// render.
+func resolveVariablesForDependencies(
+	ctx context.Context,
+	l logging.Logger,
+	opts *options.BoilerplateOptions,
+	boilerplateConfig *config.BoilerplateConfig,
+	dependency *variables.Dependency,
+) map[string]any {
+	variableOpts := *opts
+	variableOpts.NonInteractive = true
+	variableOpts.NoHooks = true
+	variableOpts.NoShell = true
+	variableOpts.OnMissingKey = options.ExitWithError
+
+	vars, err := config.GetVariablesWithContext(ctx, l, &variableOpts, boilerplateConfig, boilerplateConfig, dependency)
+	if err != nil {
+		l.Debugf("Not all the variables of template %s have a value: %v", opts.TemplateURL, err)
+	}
+
+	if vars == nil {
+		return opts.Vars
+	}
+
+	return vars
+}
+
+// Return the options to use to load the template of the given dependency, rendering its template-url with the given
+// variables, which are resolved with resolveVariablesForDependencies.
+func optionsForDeclaredDependency(
+	ctx context.Context,
+	l logging.Logger,
+	opts *options.BoilerplateOptions,
+	dependency *variables.Dependency,
+	vars map[string]any,
+) (*options.BoilerplateOptions, error) {
+	renderOpts := &options.BoilerplateOptions{
+		TemplateFolder: opts.TemplateFolder,
+		OnMissingKey:   options.ExitWithError,
+		NoShell:        true,
+	}
+
+	renderedTemplateURL, err := render.RenderTemplateFromStringWithContext(ctx, l, opts.TemplateFolder, dependency.TemplateURL, vars, renderOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	templateURL, templateFolder, err := getterhelper.DetermineTemplateConfig(renderedTemplateURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if templateFolder != "" {
+		templateFolder = render.PathRelativeToTemplate(opts.TemplateFolder, renderedTemplateURL)
+	}
+
+	return &options.BoilerplateOptions{
+		Vars:             opts.Vars,
+		DefaultsFileVars: opts.DefaultsFileVars,
+		TemplateURL:      templateURL,
+		TemplateFolder:   templateFolder,
+		OnMissingConfig:  opts.OnMissingConfig,
+		OnMissingKey:     options.ExitWithError,
+		NonInteractive:   true,
+		NoHooks:          true,
+		NoShell:          true,
+	}, nil
+}
+
+// Custom error types
+
+type DependencyTooDeep string
+
+func (name DependencyTooDeep) Error() string {
+	return fmt.Sprintf("Not loading the variables of dependency '%s', as dependencies are nested more than %d levels deep. Do your dependencies form a cycle?", string(name), maxDeclaredVariablesDepth)
+}
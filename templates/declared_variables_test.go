@@ -0,0 +1,220 @@
+package templates //nolint:testpackage
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/gruntwork-io/boilerplate/options"
+	"github.com/gruntwork-io/boilerplate/pkg/logging"
+	"github.com/gruntwork-io/boilerplate/testutil"
+)
+
+// Create a root template with a local dependency, whose template-url depends on the BackendTemplate variable, and a
+// dependency that does not inherit variables, side by side in a temporary folder. Returns the path to the root template.
+func createDeclaredVariablesTemplates(t *testing.T) string {
+	t.Helper()
+
+	templatesDir := t.TempDir()
+
+	files := map[string]string{
+		"root/boilerplate.yml": `variables:
+  - name: Name
+    description: The name of the app
+    validations: [required, dns_label]
+  - name: Port
+    type: int
+    default: 8080
+    validations: ["range(1, 65535)"]
+  - name: Env
+    type: enum
+    options: [dev, prod]
+    default: dev
+  - name: Token
+    sensitive: true
+    default: hunter2
+  - name: Slug
+    value: "{{ .Name }}-{{ .Env }}"
+    overridable: true
+  - name: Region
+    value: us-east-1
+  - name: Subnets
+    type: list(string)
+    default: []
+    validations: [cidr, each(required)]
+dependencies:
+  - name: backend
+    template-url: "../{{ .BackendTemplate }}"
+    output-folder: backend
+    variables:
+      - name: Replicas
+        type: int
+        default: 1
+  - name: database
+    template-url: ../database
+    output-folder: database
+    dont-inherit-variables: true
+`,
+		"go-service/boilerplate.yml": `variables:
+  - name: Name
+  - name: Replicas
+    type: int
+    default: 2
+  - name: Owner
+    description: Team that owns the service
+`,
+		"database/boilerplate.yml": `variables:
+  - name: Name
+    default: db
+  - name: Engine
+    type: enum
+    options: [postgres, mysql]
+    default: postgres
+  - name: Extensions
+    type: list(enum)
+    options: [pgcrypto, postgis, citext]
+    min_selections: 1
+`,
+	}
+
+	testutil.WriteFiles(t, templatesDir, files)
+
+	return filepath.Join(templatesDir, "root")
+}
+
+func loadTestDeclaredVariables(t *testing.T, vars map[string]any) *DeclaredVariables {
+	t.Helper()
+
+	opts := &options.BoilerplateOptions{
+		TemplateURL:     "root",
+		TemplateFolder:  createDeclaredVariablesTemplates(t),
+		Vars:            vars,
+		NonInteractive:  true,
+		OnMissingKey:    options.ExitWithError,
+		OnMissingConfig: options.Exit,
+	}
+
+	declared, err := LoadDeclaredVariables(context.Background(), logging.Discard(), opts)
+	require.NoError(t, err)
+
+	return declared
+}
+
+func TestLoadDeclaredVariablesRendersDependencyTemplateURLs(t *testing.T) {
+	t.Parallel()
+
+	declared := loadTestDeclaredVariables(t, map[string]any{"BackendTemplate": "go-service"})
+	require.Len(t, declared.Dependencies, 2)
+
+	backend := declared.Dependencies[0]
+	require.NoError(t, backend.Err)
+	require.NotNil(t, backend.Variables)
+
+	names := []string{}
+	for _, variable := range backend.Variables.Variables {
+		names = append(names, variable.Name())
+	}
+
+	assert.Equal(t, []string{"Name", "Replicas", "Owner"}, names)
+	// The variables set in the dependency block take the place of the ones declared in the dependency's template
+	assert.Equal(t, 1, backend.Variables.Variables[1].Default())
+}
+
+func TestLoadDeclaredVariablesReportsUnresolvableDependencies(t *testing.T) {
+	t.Parallel()
+
+	declared := loadTestDeclaredVariables(t, nil)
+	require.Len(t, declared.Dependencies, 2)
+
+	assert.Error(t, declared.Dependencies[0].Err)
+	assert.Nil(t, declared.Dependencies[0].Variables)
+	assert.NoError(t, declared.Dependencies[1].Err)
+}
+
+func TestExampleVarFile(t *testing.T) {
+	t.Parallel()
+
+	declared := loadTestDeclaredVariables(t, map[string]any{"BackendTemplate": "go-service"})
+
+	example, err := declared.ExampleVarFile()
+	require.NoError(t, err)
+
+	assert.Contains(t, example, "# The name of the app\n#\n# Type: string\n# Validations: Must not be empty; Must be a valid DNS label")
+	assert.Contains(t, example, "# Required: this variable has no default value.\nName: \"\"\n")
+	assert.Contains(t, example, "# Type: enum (one of: dev, prod)\nEnv: dev\n")
+	assert.Contains(t, example, "# Type: list(enum) (any of: pgcrypto, postgis, citext)\n")
+	assert.Contains(t, example, "# Computed: uncomment this to override the computed value.\n# Slug:")
+	assert.Contains(t, example, "# Dependency: backend (template-url: ../{{ .BackendTemplate }})")
+	assert.NotContains(t, example, "hunter2")
+
+	parsed := map[string]any{}
+	require.NoError(t, yaml.Unmarshal([]byte(example), &parsed))
+
+	assert.Equal(t, map[string]any{
+		"Name":                "",
+		"Port":                8080,
+		"Env":                 "dev",
+		"Token":               "",
+		"Subnets":             []any{},
+		"backend.Replicas":    1,
+		"backend.Owner":       "",
+		"database.Name":       "db",
+		"database.Engine":     "postgres",
+		"database.Extensions": []any{"pgcrypto"},
+	}, parsed)
+}
+
+func TestVarFileSchema(t *testing.T) {
+	t.Parallel()
+
+	declared := loadTestDeclaredVariables(t, map[string]any{"BackendTemplate": "go-service"})
+
+	schema := declared.VarFileSchema()
+	assert.Equal(t, false, schema["additionalProperties"])
+
+	properties, isMap := schema["properties"].(map[string]any)
+	require.True(t, isMap)
+
+	assert.Equal(t, map[string]any{
+		"type":        "string",
+		"description": "The name of the app",
+		"minLength":   1,
+		"pattern":     `^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`,
+	}, properties["Name"])
+	assert.Equal(t, map[string]any{"type": "integer", "default": 8080, "minimum": float64(1), "maximum": float64(65535)}, properties["Port"])
+	assert.Equal(t, map[string]any{"enum": []string{"dev", "prod"}, "default": "dev"}, properties["Env"])
+	assert.Equal(t, map[string]any{"type": "string"}, properties["Token"])
+	assert.Equal(t, map[string]any{
+		"type":        "array",
+		"items":       map[string]any{"type": "string", "minLength": 1},
+		"default":     []any{},
+		"description": "Validations: Must be a valid CIDR block (e.g., 10.0.0.0/16)",
+	}, properties["Subnets"])
+
+	// Variables of dependencies can be set with their namespaced names, and, if the dependency inherits variables,
+	// with just their names
+	assert.Contains(t, properties, "backend.Owner")
+	assert.Contains(t, properties, "Owner")
+	assert.Contains(t, properties, "database.Engine")
+	assert.NotContains(t, properties, "Engine")
+	assert.Equal(t, map[string]any{
+		"type":        "array",
+		"items":       map[string]any{"enum": []string{"pgcrypto", "postgis", "citext"}},
+		"uniqueItems": true,
+		"minItems":    1,
+	}, properties["database.Extensions"])
+	assert.Contains(t, properties, "Slug")
+	assert.NotContains(t, properties, "Region")
+}
+
+func TestVarFileSchemaAllowsUnknownKeysWhenIncomplete(t *testing.T) {
+	t.Parallel()
+
+	declared := loadTestDeclaredVariables(t, nil)
+
+	assert.Equal(t, true, declared.VarFileSchema()["additionalProperties"])
+}
@@ -0,0 +1,218 @@
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gruntwork-io/boilerplate/variables"
+)
+
+// exampleVarFileBanner separates the variables of each dependency in an example var file
+var exampleVarFileBanner = "# " + strings.Repeat("-", 117)
+
+// ExampleVarFile returns a commented YAML var file that sets every variable of the template and its dependencies, in
+// the order boilerplate asks for them. Each variable is documented with its description, type, options, validations,
+// and whether it is required. Variables are set to their default value, or to an empty value of the right type if
+// they don't have one.
+func (declared *DeclaredVariables) ExampleVarFile() (string, error) {
+	var out strings.Builder
+
+	fmt.Fprintf(&out, "# Example var file for the template at %s, generated by `boilerplate vars example`.\n", declared.TemplateURL)
+	out.WriteString("# Fill in the required variables, then pass this file to boilerplate with --var-file.\n")
+
+	for _, section := range declared.varFileSections() {
+		if section.Dependency != nil {
+			writeExampleDependencyHeader(&out, section)
+		}
+
+		for _, entry := range section.Entries {
+			out.WriteString("\n")
+
+			if err := writeExampleVarFileEntry(&out, entry); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	return out.String(), nil
+}
+
+func writeExampleDependencyHeader(out *strings.Builder, section varFileSection) {
+	dependency := section.Dependency.Dependency
+
+	out.WriteString("\n" + exampleVarFileBanner + "\n")
+	fmt.Fprintf(out, "# Dependency: %s (template-url: %s)\n", section.Path, dependency.TemplateURL)
+
+	if dependency.DontInheritVariables {
+		out.WriteString("# This dependency does not inherit variables, so they can only be set with their namespaced names.\n")
+	}
+
+	if section.Dependency.Err != nil {
+		writeExampleComment(out, fmt.Sprintf("The variables of this dependency could not be determined: %v", section.Dependency.Err))
+		out.WriteString("# If its template-url uses variables, pass them in with --var to include its variables here.\n")
+	} else if len(section.Entries) == 0 {
+		out.WriteString("# This dependency has no variables of its own, as it only uses variables that it inherits.\n")
+	}
+
+	out.WriteString(exampleVarFileBanner + "\n")
+}
+
+func writeExampleVarFileEntry(out *strings.Builder, entry varFileEntry) error {
+	variable := entry.Variable
+
+	if variable.Description() != "" {
+		writeExampleComment(out, variable.Description())
+		out.WriteString("#\n")
+	}
+
+	writeExampleComment(out, "Type: "+exampleTypeDescription(variable))
+
+	if rules := variable.Validations(); len(rules) > 0 {
+		messages := make([]string, 0, len(rules))
+		for _, rule := range rules {
+			messages = append(messages, rule.DescriptionText())
+		}
+
+		writeExampleComment(out, "Validations: "+strings.Join(messages, "; "))
+	}
+
+	if variable.When() != "" {
+		writeExampleComment(out, "Only used when: "+variable.When())
+	}
+
+	if variable.Deprecated() != "" {
+		writeExampleComment(out, "Deprecated: "+variable.Deprecated())
+	}
+
+	computed := variable.Value() != nil
+
+	switch {
+	case computed:
+		writeExampleComment(out, "Computed: uncomment this to override the computed value.")
+	case variable.Sensitive() && variable.Default() != nil:
+		writeExampleComment(out, "Sensitive: this variable has a default value, which is not shown here.")
+	case variable.Default() == nil:
+		writeExampleComment(out, "Required: this variable has no default value.")
+	}
+
+	valueYAML, err := exampleVarFileValueYAML(entry.Key, exampleVarFileValue(variable))
+	if err != nil {
+		return err
+	}
+
+	if computed {
+		writeExampleComment(out, strings.TrimSuffix(valueYAML, "\n"))
+	} else {
+		out.WriteString(valueYAML)
+	}
+
+	return nil
+}
+
+// Write the given text as YAML comment lines.
+func writeExampleComment(out *strings.Builder, text string) {
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		out.WriteString(strings.TrimRight("# "+line, " ") + "\n")
+	}
+}
+
+// Return a short description of the type of the given variable, including the options of enums and the fields of
+// objects.
+func exampleTypeDescription(variable variables.Variable) string {
+	description := variable.Type().String()
+
+	switch {
+	case variable.OptionsExpression() != "":
+		description += " (options: " + variable.OptionsExpression() + ")"
+	case variable.Type() == variables.Enum:
+		description += " (one of: " + strings.Join(variable.Options(), ", ") + ")"
+	case variable.Type() == variables.ListOfEnums:
+		description += " (any of: " + strings.Join(variable.Options(), ", ") + ")"
+	case variable.Type().IsObject():
+		fields := make([]string, 0, len(variable.Fields()))
+		for _, field := range variable.Fields() {
+			fields = append(fields, fmt.Sprintf("%s (%s)", field.Name(), exampleTypeDescription(field)))
+		}
+
+		description += " with fields: " + strings.Join(fields, ", ")
+	}
+
+	return description
+}
+
+// Return the value to set the given variable to in the example var file: its default, its computed value, or an empty
+// value of the right type. The defaults of sensitive variables are never included.
+func exampleVarFileValue(variable variables.Variable) any {
+	switch {
+	case variable.Sensitive():
+		return emptyValueForVariable(variable)
+	case variable.Value() != nil:
+		return variable.Value()
+	case variable.Default() != nil:
+		return variable.Default()
+	default:
+		return emptyValueForVariable(variable)
+	}
+}
+
+// Return an empty value of the type of the given variable. Enums get their first option, lists of enums get as many of
+// their first options as must be selected, and objects get each of their fields set to its default or an empty value.
+func emptyValueForVariable(variable variables.Variable) any {
+	if variable.Type() == variables.ListOfEnums {
+		selections := []any{}
+		for _, option := range variable.Options()[:min(variable.MinSelections(), len(variable.Options()))] {
+			selections = append(selections, option)
+		}
+
+		return selections
+	}
+
+	switch variable.Type().BaseType() {
+	case variables.Int:
+		return 0
+	case variables.Float:
+		return 0.0
+	case variables.Bool:
+		return false
+	case variables.List:
+		return []any{}
+	case variables.Map:
+		return map[string]any{}
+	case variables.Enum:
+		if len(variable.Options()) > 0 {
+			return variable.Options()[0]
+		}
+
+		return ""
+	case variables.Object:
+		object := map[string]any{}
+		for _, field := range variable.Fields() {
+			object[field.Name()] = exampleVarFileValue(field)
+		}
+
+		return object
+	default:
+		return ""
+	}
+}
+
+// Marshal a single key and value into YAML.
+func exampleVarFileValueYAML(key string, value any) (string, error) {
+	var buf bytes.Buffer
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(map[string]any{key: value}); err != nil {
+		return "", err
+	}
+
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
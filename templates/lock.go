@@ -0,0 +1,215 @@
+package templates
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gruntwork-io/boilerplate/config"
+	"github.com/gruntwork-io/boilerplate/getterhelper"
+	"github.com/gruntwork-io/boilerplate/lockfile"
+	"github.com/gruntwork-io/boilerplate/manifest"
+	"github.com/gruntwork-io/boilerplate/options"
+	"github.com/gruntwork-io/boilerplate/pkg/logging"
+	"github.com/gruntwork-io/boilerplate/render"
+	"github.com/gruntwork-io/boilerplate/util"
+	"github.com/gruntwork-io/boilerplate/variables"
+)
+
+// LockTemplates resolves the remote template at opts.TemplateURL, and the remote templates of all its dependencies, to
+// an immutable source: the commit for git sources, or a checksum of the contents for other sources. The template-url
+// of each dependency is rendered with opts.Vars and the defaults of the declared variables, once per item for
+// dependencies with for_each, so dependencies whose template-url depends on variables without a default are locked by
+// passing those variables in. Local templates are not locked.
+func LockTemplates(ctx context.Context, l logging.Logger, opts *options.BoilerplateOptions) (*lockfile.LockFile, error) {
+	lock := &lockfile.LockFile{}
+
+	// Sources used by several templates are only downloaded once
+	if opts.DownloadCache == nil {
+		opts.DownloadCache = getterhelper.NewDownloadCache()
+
+		defer func() {
+			opts.DownloadCache.Cleanup(l)
+			opts.DownloadCache = nil
+		}()
+	}
+
+	if err := lockTemplate(ctx, l, opts, nil, lock, 0); err != nil {
+		return nil, err
+	}
+
+	return lock, nil
+}
+
+func lockTemplate(
+	ctx context.Context,
+	l logging.Logger,
+	opts *options.BoilerplateOptions,
+	dependency *variables.Dependency,
+	lock *lockfile.LockFile,
+	depth int,
+) error {
+	isRemote := opts.TemplateFolder == ""
+
+	cleanup, cloneDir, err := resolveTemplate(l, opts)
+	if cleanup != nil {
+		defer cleanup()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if isRemote {
+		checksum, checksumErr := manifest.ComputeSourceChecksum(l, opts.TemplateFolder, cloneDir)
+		if checksumErr != nil {
+			return checksumErr
+		}
+
+		l.Infof("Locked %s to %s", opts.TemplateURL, checksum)
+
+		if err := lock.Add(opts.TemplateURL, checksum); err != nil {
+			return err
+		}
+	}
+
+	boilerplateConfig, err := config.LoadBoilerplateConfig(l, opts)
+	if err != nil {
+		return err
+	}
+
+	vars := resolveVariablesForDependencies(ctx, l, opts, boilerplateConfig, dependency)
+
+	for i := range boilerplateConfig.Dependencies {
+		childDependency := &boilerplateConfig.Dependencies[i]
+
+		if depth >= maxDeclaredVariablesDepth {
+			return LockDependencyTooDeep(childDependency.Name)
+		}
+
+		childOpts, err := optionsForLockedDependency(ctx, l, opts, childDependency, vars)
+		if err != nil {
+			return LockDependencyError{Err: err, DependencyName: childDependency.Name}
+		}
+
+		for _, childOpt := range childOpts {
+			childOpt.TrustedTemplates = opts.TrustedTemplates
+			childOpt.DownloadCache = opts.DownloadCache
+
+			if err := lockTemplate(ctx, l, childOpt, childDependency, lock, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Return the options to use to lock the template of the given dependency: one for each distinct template-url it
+// renders to with the given variables, as a dependency with for_each may use a different template for each item.
+func optionsForLockedDependency(
+	ctx context.Context,
+	l logging.Logger,
+	opts *options.BoilerplateOptions,
+	dependency *variables.Dependency,
+	vars map[string]any,
+) ([]*options.BoilerplateOptions, error) {
+	forEach := dependency.ForEachList()
+
+	if dependency.ForEachReference != "" {
+		renderOpts := &options.BoilerplateOptions{TemplateFolder: opts.TemplateFolder, OnMissingKey: options.ExitWithError, NoShell: true}
+
+		renderedReference, err := render.RenderTemplateFromStringWithContext(ctx, l, opts.TemplateFolder, dependency.ForEachReference, vars, renderOpts)
+		if err != nil {
+			return nil, err
+		}
+
+		forEach = nil
+
+		if value, hasValue := vars[renderedReference]; hasValue && value != nil {
+			forEach, err = variables.ForEachItems(value)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(forEach) == 0 {
+		childOpts, err := optionsForDeclaredDependency(ctx, l, opts, dependency, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		return []*options.BoilerplateOptions{childOpts}, nil
+	}
+
+	allChildOpts := []*options.BoilerplateOptions{}
+	seen := map[string]bool{}
+
+	for _, item := range forEach {
+		childOpts, err := optionsForDeclaredDependency(ctx, l, opts, dependency, util.MergeMaps(vars, map[string]any{eachVarName: item}))
+		if err != nil {
+			return nil, err
+		}
+
+		key := childOpts.TemplateURL + "\x00" + childOpts.TemplateFolder
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		allChildOpts = append(allChildOpts, childOpts)
+	}
+
+	return allChildOpts, nil
+}
+
+// Check that the checksum of the template downloaded for a locked template matches the checksum in the lock file.
+func verifyLockedSource(l logging.Logger, lockedSource lockfile.Source, templateFolder string, cloneDir string) error {
+	checksum, err := manifest.ComputeSourceChecksum(l, templateFolder, cloneDir)
+	if err != nil {
+		return err
+	}
+
+	if checksum != lockedSource.Checksum {
+		return LockedTemplateMismatch{TemplateURL: lockedSource.TemplateURL, LockedChecksum: lockedSource.Checksum, Checksum: checksum}
+	}
+
+	return nil
+}
+
+// Custom error types
+
+type TemplateNotLocked string
+
+func (templateURL TemplateNotLocked) Error() string {
+	return fmt.Sprintf("The template %s is not in the lock file. Run boilerplate lock to add it.", string(templateURL))
+}
+
+type LockedTemplateMismatch struct {
+	TemplateURL    string
+	LockedChecksum string
+	Checksum       string
+}
+
+func (err LockedTemplateMismatch) Error() string {
+	return fmt.Sprintf("The template %s does not match the lock file: it was locked to %s, but resolved to %s. Run boilerplate lock to update the lock file.", err.TemplateURL, err.LockedChecksum, err.Checksum)
+}
+
+type LockDependencyError struct {
+	Err            error
+	DependencyName string
+}
+
+func (err LockDependencyError) Error() string {
+	return fmt.Sprintf("Could not render the template-url of dependency '%s' to lock it. Pass in the variables it uses with --var or --var-file: %v", err.DependencyName, err.Err)
+}
+
+func (err LockDependencyError) Unwrap() error {
+	return err.Err
+}
+
+type LockDependencyTooDeep string
+
+func (name LockDependencyTooDeep) Error() string {
+	return fmt.Sprintf("Not locking the template of dependency '%s', as dependencies are nested more than %d levels deep. Do your dependencies form a cycle?", string(name), maxDeclaredVariablesDepth)
+}
@@ -0,0 +1,155 @@
+package templates //nolint:testpackage
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/boilerplate/lockfile"
+	"github.com/gruntwork-io/boilerplate/pkg/logging"
+	"github.com/gruntwork-io/boilerplate/testutil"
+	"github.com/gruntwork-io/boilerplate/variables"
+)
+
+// Create a git repository with a template whose service.txt contains the given contents, and a local root template
+// that uses it as a dependency, with a template-url that depends on the RepoPath variable. Returns the path to the
+// root template and a function that commits new contents to the repository.
+func createLockTemplates(t *testing.T, contents string) (string, string, func(string) string) {
+	t.Helper()
+
+	repoDir := t.TempDir()
+
+	gitCmd := func(args ...string) string {
+		t.Helper()
+
+		cmd := exec.CommandContext(context.Background(), "git", args...)
+		cmd.Dir = repoDir
+
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, "git %v failed: %s", args, string(out))
+
+		return strings.TrimSpace(string(out))
+	}
+
+	commit := func(contents string) string {
+		t.Helper()
+
+		require.NoError(t, os.WriteFile(filepath.Join(repoDir, "service.txt"), []byte(contents), 0644))
+		gitCmd("add", ".")
+		gitCmd("commit", "-m", contents)
+
+		return gitCmd("rev-parse", "HEAD")
+	}
+
+	gitCmd("init", "-b", "main")
+	gitCmd("config", "user.email", "test@test.com")
+	gitCmd("config", "user.name", "Test")
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "boilerplate.yml"), []byte("variables: []\n"), 0644))
+	commit(contents)
+
+	rootDir := t.TempDir()
+	rootConfig := `variables:
+  - name: RepoPath
+dependencies:
+  - name: service
+    template-url: "git::file://{{ .RepoPath }}?ref=main"
+    output-folder: service
+`
+	require.NoError(t, os.WriteFile(filepath.Join(rootDir, "boilerplate.yml"), []byte(rootConfig), 0644))
+
+	return rootDir, repoDir, commit
+}
+
+func TestLockTemplates(t *testing.T) {
+	t.Parallel()
+
+	rootDir, repoDir, commit := createLockTemplates(t, "v1")
+
+	opts := testutil.CreateTestOptionsWithOutput(rootDir, t.TempDir())
+	opts.Vars = map[string]any{"RepoPath": repoDir}
+
+	lock, err := LockTemplates(t.Context(), logging.Discard(), opts)
+	require.NoError(t, err)
+	require.Len(t, lock.Sources, 1)
+
+	locked := lock.Sources[0]
+	assert.Equal(t, "git::file://"+repoDir+"?ref=main", locked.TemplateURL)
+	assert.True(t, strings.HasPrefix(locked.Checksum, "git-sha"), "unexpected checksum %s", locked.Checksum)
+
+	// Later commits to the branch are not used, as the dependency is pinned to the locked commit
+	commit("v2")
+
+	outputDir := t.TempDir()
+	runOpts := testutil.CreateTestOptionsWithOutput(rootDir, outputDir)
+	runOpts.Vars = map[string]any{"RepoPath": repoDir}
+	runOpts.LockFile = lock
+
+	_, err = ProcessTemplateWithContext(t.Context(), logging.Discard(), runOpts, runOpts, &variables.Dependency{})
+	require.NoError(t, err)
+
+	service, err := os.ReadFile(filepath.Join(outputDir, "service", "service.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "v1", string(service))
+}
+
+func TestLockTemplatesUsesVariableDefaults(t *testing.T) {
+	t.Parallel()
+
+	_, repoDir, _ := createLockTemplates(t, "v1")
+
+	rootDir := t.TempDir()
+	rootConfig := `variables:
+  - name: RepoPath
+  - name: Ref
+    default: main
+  - name: Refs
+    type: list
+    default: [main]
+dependencies:
+  - name: service
+    template-url: "git::file://{{ .RepoPath }}?ref={{ .Ref }}"
+    output-folder: service
+  - name: services
+    template-url: "git::file://{{ .RepoPath }}?ref={{ .__each__ }}"
+    output-folder: "services/{{ .__each__ }}"
+    for_each_reference: Refs
+`
+	require.NoError(t, os.WriteFile(filepath.Join(rootDir, "boilerplate.yml"), []byte(rootConfig), 0644))
+
+	opts := testutil.CreateTestOptionsWithOutput(rootDir, t.TempDir())
+	opts.Vars = map[string]any{"RepoPath": repoDir}
+
+	lock, err := LockTemplates(t.Context(), logging.Discard(), opts)
+	require.NoError(t, err)
+	require.Len(t, lock.Sources, 1)
+	assert.Equal(t, "git::file://"+repoDir+"?ref=main", lock.Sources[0].TemplateURL)
+}
+
+func TestProcessTemplateWithLockFileErrors(t *testing.T) {
+	t.Parallel()
+
+	rootDir, repoDir, _ := createLockTemplates(t, "v1")
+	templateURL := "git::file://" + repoDir + "?ref=main"
+
+	process := func(lock *lockfile.LockFile) error {
+		opts := testutil.CreateTestOptionsWithOutput(rootDir, t.TempDir())
+		opts.Vars = map[string]any{"RepoPath": repoDir}
+		opts.LockFile = lock
+
+		_, err := ProcessTemplateWithContext(t.Context(), logging.Discard(), opts, opts, &variables.Dependency{})
+
+		return err
+	}
+
+	err := process(&lockfile.LockFile{})
+	require.ErrorAs(t, err, new(TemplateNotLocked))
+
+	err = process(&lockfile.LockFile{Sources: []lockfile.Source{{TemplateURL: templateURL, Checksum: "sha256:0123"}}})
+	require.ErrorAs(t, err, &LockedTemplateMismatch{})
+}
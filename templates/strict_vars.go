@@ -0,0 +1,288 @@
+package templates
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/agext/levenshtein"
+
+	"github.com/gruntwork-io/boilerplate/config"
+	"github.com/gruntwork-io/boilerplate/getterhelper"
+	"github.com/gruntwork-io/boilerplate/options"
+	"github.com/gruntwork-io/boilerplate/pkg/logging"
+	"github.com/gruntwork-io/boilerplate/render"
+	"github.com/gruntwork-io/boilerplate/variables"
+)
+
+// maxStrictVarsDepth limits how deep we follow dependencies when looking for declared variables, in case of cycles
+const maxStrictVarsDepth = 20
+
+// maxSuggestions is the maximum number of similar variable names we suggest for each unknown variable
+const maxSuggestions = 3
+
+// variableScope contains the names of the variables declared in a template and, recursively, in its dependencies.
+type variableScope struct {
+	names        map[string]bool
+	dependencies map[string]*variableScope
+	// aliases are the old names of renamed variables, which are still accepted but never suggested
+	aliases map[string]bool
+	// inherits is false for dependencies that set dont_inherit_variables, which only get namespaced variables
+	inherits bool
+	// incomplete is true if the variables of this template could not be determined without rendering it, e.g. because
+	// its template-url is remote or contains Go template syntax. Only names namespaced under it are then accepted.
+	incomplete bool
+}
+
+// checkForUnknownVariables returns an error if any of the variables passed in via --var, --var-file, --set, or
+// BOILERPLATE_ environment variables does not match a variable declared in the given config or any of its
+// dependencies. Variables set in defaults files are not checked. This is used to implement --strict-vars.
+func checkForUnknownVariables(l logging.Logger, opts *options.BoilerplateOptions, boilerplateConfig *config.BoilerplateConfig) error {
+	scope := newVariableScope(l, opts, boilerplateConfig, true, 0)
+
+	unknown := UnknownVariables{}
+
+	for _, name := range slices.Sorted(maps.Keys(opts.Vars)) {
+		if !scope.knows(name) {
+			unknown = append(unknown, UnknownVariable{Name: name, Suggestions: scope.suggest(name)})
+		}
+	}
+
+	for _, override := range opts.Overrides {
+		if !scope.knowsOverride(override.Path) {
+			unknown = append(unknown, UnknownVariable{Name: override.Path, Suggestions: scope.suggestOverride(override.Path)})
+		}
+	}
+
+	if len(unknown) > 0 {
+		return unknown
+	}
+
+	return nil
+}
+
+// Build the scope of variables declared in the given config, following local dependencies.
+func newVariableScope(l logging.Logger, opts *options.BoilerplateOptions, boilerplateConfig *config.BoilerplateConfig, inherits bool, depth int) *variableScope {
+	scope := &variableScope{
+		names:        map[string]bool{},
+		aliases:      map[string]bool{},
+		dependencies: map[string]*variableScope{},
+		inherits:     inherits,
+	}
+
+	for _, variable := range boilerplateConfig.Variables {
+		scope.add(variable)
+	}
+
+	for i := range boilerplateConfig.Dependencies {
+		dependency := &boilerplateConfig.Dependencies[i]
+
+		dependencyScope := newDependencyScope(l, opts, dependency, depth)
+		for _, variable := range dependency.Variables {
+			dependencyScope.add(variable)
+		}
+
+		scope.dependencies[dependency.Name] = dependencyScope
+	}
+
+	return scope
+}
+
+// Build the scope of variables declared in the template of the given dependency. The template is only inspected if it
+// is local and its path does not need to be rendered; otherwise the scope is marked as incomplete.
+func newDependencyScope(l logging.Logger, opts *options.BoilerplateOptions, dependency *variables.Dependency, depth int) *variableScope {
+	incompleteScope := &variableScope{
+		names:        map[string]bool{},
+		aliases:      map[string]bool{},
+		dependencies: map[string]*variableScope{},
+		inherits:     !dependency.DontInheritVariables,
+		incomplete:   true,
+	}
+
+	if depth >= maxStrictVarsDepth {
+		warnIncompleteScope(l, dependency, fmt.Sprintf("dependencies are nested more than %d levels deep", maxStrictVarsDepth))
+		return incompleteScope
+	}
+
+	if strings.Contains(dependency.TemplateURL, "{{") {
+		warnIncompleteScope(l, dependency, "its template-url contains Go template syntax")
+		return incompleteScope
+	}
+
+	_, templateFolder, err := getterhelper.DetermineTemplateConfig(dependency.TemplateURL)
+	if err != nil || templateFolder == "" {
+		warnIncompleteScope(l, dependency, "its template is remote")
+		return incompleteScope
+	}
+
+	dependencyOpts := &options.BoilerplateOptions{
+		TemplateFolder:  render.PathRelativeToTemplate(opts.TemplateFolder, dependency.TemplateURL),
+		OnMissingConfig: opts.OnMissingConfig,
+	}
+
+	dependencyConfig, err := config.LoadBoilerplateConfig(l, dependencyOpts)
+	if err != nil {
+		warnIncompleteScope(l, dependency, err.Error())
+		return incompleteScope
+	}
+
+	return newVariableScope(l, dependencyOpts, dependencyConfig, !dependency.DontInheritVariables, depth+1)
+}
+
+// Warn that --strict-vars can only partially check the variables of the given dependency.
+func warnIncompleteScope(l logging.Logger, dependency *variables.Dependency, reason string) {
+	l.Warnf(
+		"Could not determine the variables of dependency '%s' for --%s, as %s. Variables namespaced as '%s.<name>' are not checked, and variables without a namespace are only accepted if another template declares them.",
+		dependency.Name, options.OptStrictVars, reason, dependency.Name,
+	)
+}
+
+// Add the name and aliases of the given variable to this scope.
+func (scope *variableScope) add(variable variables.Variable) {
+	scope.names[variable.Name()] = true
+
+	for _, alias := range variable.Aliases() {
+		scope.aliases[alias] = true
+	}
+}
+
+// Return true if the given variable name, which may be namespaced with the names of dependencies, is declared in this
+// scope. Names namespaced under a dependency whose scope is incomplete can't be ruled out, so they are accepted.
+func (scope *variableScope) knows(name string) bool {
+	if scope.names[name] || scope.aliases[name] {
+		return true
+	}
+
+	// Variables that are not namespaced are passed down to every dependency that inherits variables. An incomplete
+	// scope can't tell us whether it declares them, so it doesn't vouch for them.
+	for _, dependencyScope := range scope.dependencies {
+		if dependencyScope.inherits && !dependencyScope.incomplete && dependencyScope.knows(name) {
+			return true
+		}
+	}
+
+	dependencyName, variableName := variables.SplitIntoDependencyNameAndVariableName(name)
+	if dependencyScope, hasDependency := scope.dependencies[dependencyName]; hasDependency {
+		return dependencyScope.incomplete || dependencyScope.knows(variableName)
+	}
+
+	return false
+}
+
+// Return true if the given --set path targets a variable in this scope. The path may continue past the name of the
+// variable into the keys of its value.
+func (scope *variableScope) knowsOverride(path string) bool {
+	parts := strings.Split(path, ".")
+
+	for i := range parts {
+		if scope.knows(strings.Join(parts[:i+1], ".")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Return the names of the variables in this scope that are most similar to the given name, most similar first.
+func (scope *variableScope) suggest(name string) []string {
+	maxDistance := max(2, len(name)/3)
+
+	type candidate struct {
+		name     string
+		distance int
+	}
+
+	candidates := []candidate{}
+
+	for _, candidateName := range scope.allNames("") {
+		if distance := levenshtein.Distance(strings.ToLower(name), strings.ToLower(candidateName), nil); distance <= maxDistance {
+			candidates = append(candidates, candidate{name: candidateName, distance: distance})
+		}
+	}
+
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		if a.distance != b.distance {
+			return a.distance - b.distance
+		}
+
+		return strings.Compare(a.name, b.name)
+	})
+
+	suggestions := []string{}
+
+	for _, candidate := range candidates {
+		if !slices.Contains(suggestions, candidate.name) {
+			suggestions = append(suggestions, candidate.name)
+		}
+
+		if len(suggestions) == maxSuggestions {
+			break
+		}
+	}
+
+	return suggestions
+}
+
+// Return the --set paths that are most similar to the given one. The path may continue past the name of the variable
+// into the keys of its value, so we look for variables similar to each prefix of the path, longest first.
+func (scope *variableScope) suggestOverride(path string) []string {
+	parts := strings.Split(path, ".")
+
+	for i := len(parts); i > 0; i-- {
+		suggestions := scope.suggest(strings.Join(parts[:i], "."))
+		if len(suggestions) == 0 {
+			continue
+		}
+
+		if rest := parts[i:]; len(rest) > 0 {
+			for j := range suggestions {
+				suggestions[j] += "." + strings.Join(rest, ".")
+			}
+		}
+
+		return suggestions
+	}
+
+	return []string{}
+}
+
+// Return the names of all the variables in this scope, with the variables of dependencies namespaced with the given
+// prefix and the names of the dependencies.
+func (scope *variableScope) allNames(prefix string) []string {
+	names := []string{}
+
+	for name := range scope.names {
+		names = append(names, prefix+name)
+	}
+
+	for dependencyName, dependencyScope := range scope.dependencies {
+		names = append(names, dependencyScope.allNames(prefix+dependencyName+".")...)
+	}
+
+	return names
+}
+
+// Custom error types
+
+type UnknownVariable struct {
+	Name        string
+	Suggestions []string
+}
+
+type UnknownVariables []UnknownVariable
+
+func (err UnknownVariables) Error() string {
+	lines := []string{fmt.Sprintf("%d unknown variable(s) were passed in, and --%s is set:", len(err), options.OptStrictVars)}
+
+	for _, unknown := range err {
+		line := "  - " + unknown.Name
+		if len(unknown.Suggestions) > 0 {
+			line += fmt.Sprintf(" (did you mean %s?)", strings.Join(unknown.Suggestions, ", "))
+		}
+
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,235 @@
+package templates //nolint:testpackage
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/boilerplate/options"
+	"github.com/gruntwork-io/boilerplate/pkg/logging"
+	"github.com/gruntwork-io/boilerplate/testutil"
+	"github.com/gruntwork-io/boilerplate/variables"
+)
+
+// Create a root template with a local dependency, which has a nested dependency of its own, side by side in a
+// temporary folder, and return the path to the root template.
+func createStrictVarsTemplates(t *testing.T, rootDependencies string) string {
+	t.Helper()
+
+	templatesDir := t.TempDir()
+
+	files := map[string]string{
+		"root/boilerplate.yml": `variables:
+  - name: Name
+  - name: Tags
+    type: map
+    default: {}
+dependencies:
+  - name: backend
+    template-url: ../backend
+    output-folder: backend
+    variables:
+      - name: Replicas
+        type: int
+        default: 1
+` + rootDependencies,
+		"backend/boilerplate.yml": `variables:
+  - name: Port
+    type: int
+    default: 8080
+dependencies:
+  - name: database
+    template-url: ../database
+    output-folder: database
+`,
+		"database/boilerplate.yml": `variables:
+  - name: Engine
+    default: postgres
+`,
+	}
+
+	testutil.WriteFiles(t, templatesDir, files)
+
+	return filepath.Join(templatesDir, "root")
+}
+
+func TestStrictVarsAcceptsDeclaredVariables(t *testing.T) {
+	t.Parallel()
+
+	overrides, err := variables.ParseVariableOverrides([]string{"Tags.env=prod", "backend.database.Engine=mysql"})
+	require.NoError(t, err)
+
+	opts := &options.BoilerplateOptions{
+		TemplateFolder:  createStrictVarsTemplates(t, ""),
+		OutputFolder:    t.TempDir(),
+		NonInteractive:  true,
+		StrictVars:      true,
+		OnMissingKey:    options.ExitWithError,
+		OnMissingConfig: options.Exit,
+		Vars: map[string]any{
+			"Name":                    "app",
+			"Port":                    9090,
+			"backend.Replicas":        3,
+			"backend.Port":            9091,
+			"backend.database.Engine": "mysql",
+		},
+		Overrides: overrides,
+	}
+
+	_, err = ProcessTemplateWithContext(t.Context(), logging.Discard(), opts, opts, &variables.Dependency{})
+	require.NoError(t, err)
+}
+
+func TestStrictVarsRejectsUnknownVariables(t *testing.T) {
+	t.Parallel()
+
+	overrides, err := variables.ParseVariableOverrides([]string{"Tgas.env=prod"})
+	require.NoError(t, err)
+
+	opts := &options.BoilerplateOptions{
+		TemplateFolder:  createStrictVarsTemplates(t, ""),
+		OutputFolder:    t.TempDir(),
+		NonInteractive:  true,
+		StrictVars:      true,
+		OnMissingKey:    options.ExitWithError,
+		OnMissingConfig: options.Exit,
+		Vars: map[string]any{
+			"Name":                    "app",
+			"Nmae":                    "typo",
+			"backend.Prot":            9091,
+			"backend.database.Engin":  "mysql",
+			"frontend.Port":           80,
+			"CompletelyDifferentName": true,
+		},
+		Overrides: overrides,
+	}
+
+	_, err = ProcessTemplateWithContext(t.Context(), logging.Discard(), opts, opts, &variables.Dependency{})
+	require.Error(t, err)
+
+	var unknown UnknownVariables
+	require.ErrorAs(t, err, &unknown)
+	assert.Equal(t, UnknownVariables{
+		{Name: "CompletelyDifferentName", Suggestions: []string{}},
+		{Name: "Nmae", Suggestions: []string{"Name"}},
+		{Name: "backend.Prot", Suggestions: []string{"backend.Port"}},
+		{Name: "backend.database.Engin", Suggestions: []string{"backend.database.Engine"}},
+		{Name: "frontend.Port", Suggestions: []string{}},
+		{Name: "Tgas.env", Suggestions: []string{"Tags.env"}},
+	}, unknown)
+	assert.Contains(t, err.Error(), "Nmae (did you mean Name?)")
+}
+
+func TestStrictVarsIsLenientForRemoteDependencies(t *testing.T) {
+	t.Parallel()
+
+	opts := &options.BoilerplateOptions{
+		TemplateFolder: createStrictVarsTemplates(t, `  - name: remote
+    template-url: git::https://example.com/templates.git//remote?ref=v1.0.0
+    output-folder: remote
+    skip: "true"
+`),
+		OutputFolder:    t.TempDir(),
+		NonInteractive:  true,
+		StrictVars:      true,
+		OnMissingKey:    options.ExitWithError,
+		OnMissingConfig: options.Exit,
+		Vars:            map[string]any{"Name": "app", "remote.Anything": "goes"},
+	}
+
+	_, err := ProcessTemplateWithContext(t.Context(), logging.Discard(), opts, opts, &variables.Dependency{})
+	require.NoError(t, err)
+}
+
+func TestStrictVarsChecksVariablesWithoutNamespaceDespiteRemoteDependencies(t *testing.T) {
+	t.Parallel()
+
+	var logs bytes.Buffer
+
+	opts := &options.BoilerplateOptions{
+		TemplateFolder: createStrictVarsTemplates(t, `  - name: remote
+    template-url: git::https://example.com/templates.git//remote?ref=v1.0.0
+    output-folder: remote
+    skip: "true"
+  - name: templated
+    template-url: "../{{ .Name }}"
+    output-folder: templated
+    skip: "true"
+`),
+		OutputFolder:    t.TempDir(),
+		NonInteractive:  true,
+		StrictVars:      true,
+		OnMissingKey:    options.ExitWithError,
+		OnMissingConfig: options.Exit,
+		Vars: map[string]any{
+			"Name":               "app",
+			"Port":               9090,
+			"remote.Anything":    "goes",
+			"templated.Anything": "goes",
+			"Anything":           "unknown",
+		},
+	}
+
+	_, err := ProcessTemplateWithContext(t.Context(), logging.New(&logs, logging.LevelWarn), opts, opts, &variables.Dependency{})
+	require.Error(t, err)
+
+	var unknown UnknownVariables
+	require.ErrorAs(t, err, &unknown)
+	assert.Equal(t, UnknownVariables{{Name: "Anything", Suggestions: []string{}}}, unknown)
+	assert.Contains(t, logs.String(), "Could not determine the variables of dependency 'remote'")
+	assert.Contains(t, logs.String(), "Could not determine the variables of dependency 'templated'")
+}
+
+func TestStrictVarsAcceptsAliases(t *testing.T) {
+	t.Parallel()
+
+	templatesDir := t.TempDir()
+
+	files := map[string]string{
+		"root/boilerplate.yml": `variables:
+  - name: ServiceName
+    aliases: [Name]
+dependencies:
+  - name: backend
+    template-url: ../backend
+    output-folder: backend
+`,
+		"backend/boilerplate.yml": `variables:
+  - name: ListenPort
+    type: int
+    aliases: [Port]
+    default: 8080
+`,
+	}
+
+	testutil.WriteFiles(t, templatesDir, files)
+
+	require.NoError(t, os.WriteFile(filepath.Join(templatesDir, "root", "name.txt"), []byte("{{ .ServiceName }}"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(templatesDir, "backend", "port.txt"), []byte("{{ .ListenPort }}"), 0644))
+
+	outputDir := t.TempDir()
+	opts := &options.BoilerplateOptions{
+		TemplateFolder:  filepath.Join(templatesDir, "root"),
+		OutputFolder:    outputDir,
+		NonInteractive:  true,
+		StrictVars:      true,
+		OnMissingKey:    options.ExitWithError,
+		OnMissingConfig: options.Exit,
+		Vars:            map[string]any{"Name": "app", "backend.Port": 9090},
+	}
+
+	_, err := ProcessTemplateWithContext(t.Context(), logging.Discard(), opts, opts, &variables.Dependency{})
+	require.NoError(t, err)
+
+	name, err := os.ReadFile(filepath.Join(outputDir, "name.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "app", string(name))
+
+	port, err := os.ReadFile(filepath.Join(outputDir, "backend", "port.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "9090", string(port))
+}
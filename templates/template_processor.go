@@ -3,6 +3,7 @@ package templates
 import (
 	"context"
 	"crypto/sha256"
+	"errors"
 	"fmt"
 	"io/fs"
 	"maps"
@@ -10,6 +11,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"golang.org/x/sync/errgroup"
@@ -18,6 +20,7 @@ import (
 	"github.com/gruntwork-io/boilerplate/getterhelper"
 	"github.com/gruntwork-io/boilerplate/internal/fileutil"
 	"github.com/gruntwork-io/boilerplate/internal/shell"
+	"github.com/gruntwork-io/boilerplate/lockfile"
 	"github.com/gruntwork-io/boilerplate/manifest"
 	"github.com/gruntwork-io/boilerplate/options"
 	"github.com/gruntwork-io/boilerplate/pkg/logging"
@@ -30,6 +33,7 @@ import (
 // ProcessResult holds the outputs of a template processing run.
 type ProcessResult struct {
 	Variables      map[string]any
+	Outputs        map[string]string
 	Dependencies   []manifest.ManifestDependency
 	SourceChecksum string
 	GeneratedFiles []string
@@ -38,6 +42,9 @@ type ProcessResult struct {
 // The name of the variable that contains the current value of the loop in each iteration of for_each
 const eachVarName = "__each__"
 
+// The name of the variable that contains the outputs of the dependencies that have been processed, by dependency name
+const depsVarName = "Deps"
+
 const defaultDirPerm = 0o777
 
 // ProcessTemplate processes the boilerplate template specified in the given options and use the existing variables. This function will
@@ -53,6 +60,16 @@ func ProcessTemplate(l logging.Logger, options, rootOpts *options.BoilerplateOpt
 // ProcessTemplateWithContext is like ProcessTemplate but accepts a context for cancellation and timeouts.
 // Returns a ProcessResult containing the list of generated file paths and source checksum.
 func ProcessTemplateWithContext(ctx context.Context, l logging.Logger, options, rootOpts *options.BoilerplateOptions, thisDep *variables.Dependency) (*ProcessResult, error) {
+	// Each remote template source is downloaded once per run, and shared by all the templates that use it
+	if options.DownloadCache == nil {
+		options.DownloadCache = getterhelper.NewDownloadCache()
+
+		defer func() {
+			options.DownloadCache.Cleanup(l)
+			options.DownloadCache = nil
+		}()
+	}
+
 	cleanup, cloneDir, err := resolveTemplate(l, options)
 	if cleanup != nil {
 		defer cleanup()
@@ -87,26 +104,46 @@ func ProcessTemplateWithContext(ctx context.Context, l logging.Logger, options,
 		return nil, err
 	}
 
+	if options.StrictVars {
+		if err := checkForUnknownVariables(l, options, boilerplateConfig); err != nil {
+			return nil, err
+		}
+	}
+
 	vars, err := config.GetVariablesWithContext(ctx, l, options, boilerplateConfig, rootBoilerplateConfig, thisDep)
 	if err != nil {
+		var invalidVariables config.InvalidVariables
+		if options.NonInteractive && vars != nil && errors.As(err, &invalidVariables) {
+			// Check the variables of the dependencies too, so that all the missing and invalid variables are reported
+			// at once
+			invalidVariables.Issues = append(invalidVariables.Issues, collectDependencyVariableIssues(ctx, l, boilerplateConfig.Dependencies, options, rootBoilerplateConfig, boilerplateConfig.GetVariablesMap(), vars)...)
+
+			return nil, invalidVariables
+		}
+
 		return nil, err
 	}
 
+	// Hooks, shell helpers and dependencies log commands rendered from the variables, so keep the secrets out of them
+	l = variables.RedactingLogger(l, variables.SensitiveValues(vars, boilerplateConfig.Variables, options.SensitiveVars))
+
 	err = os.MkdirAll(options.OutputFolder, defaultDirPerm)
 	if err != nil {
 		return nil, err
 	}
 
-	err = processHooks(ctx, l, boilerplateConfig.Hooks.BeforeHooks, options, vars)
+	err = processHooks(ctx, l, boilerplateConfig.Hooks.BeforeHooks, options, boilerplateConfig.Variables, vars)
 	if err != nil {
 		return nil, err
 	}
 
-	deps, err := processDependencies(ctx, l, boilerplateConfig.Dependencies, options, boilerplateConfig.GetVariablesMap(), vars)
+	deps, depsOutputs, err := processDependencies(ctx, l, boilerplateConfig.Dependencies, options, boilerplateConfig.GetVariablesMap(), vars)
 	if err != nil {
 		return nil, err
 	}
 
+	vars[depsVarName] = depsOutputs
+
 	partials, err := processPartials(ctx, l, boilerplateConfig.Partials, options, vars)
 	if err != nil {
 		return nil, err
@@ -117,16 +154,22 @@ func ProcessTemplateWithContext(ctx context.Context, l logging.Logger, options,
 		return nil, err
 	}
 
-	err = processHooks(ctx, l, boilerplateConfig.Hooks.AfterHooks, options, vars)
+	err = processHooks(ctx, l, boilerplateConfig.Hooks.AfterHooks, options, boilerplateConfig.Variables, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	outputs, err := renderOutputs(ctx, l, boilerplateConfig.Outputs, options, vars)
 	if err != nil {
 		return nil, err
 	}
 
-	// Filter out builtin variables so the manifest only records user-defined ones.
+	// Filter out builtin variables so the manifest only records user-defined ones, and redact sensitive values so
+	// secrets never end up in the manifest.
 	userVars := make(map[string]any, len(vars))
 	for k, v := range vars {
 		switch k {
-		case "BoilerplateConfigVars", "BoilerplateConfigDeps", "This":
+		case "BoilerplateConfigVars", "BoilerplateConfigDeps", "This", depsVarName:
 			continue
 		default:
 			userVars[k] = v
@@ -134,29 +177,56 @@ func ProcessTemplateWithContext(ctx context.Context, l logging.Logger, options,
 	}
 
 	return &ProcessResult{
+		Outputs:        outputs,
 		GeneratedFiles: generatedFilePaths,
 		SourceChecksum: sourceChecksum,
-		Variables:      userVars,
+		Variables:      variables.RedactNamedVariables(variables.RedactSensitiveVariables(userVars, boilerplateConfig.Variables), options.SensitiveVars),
 		Dependencies:   deps,
 	}, nil
 }
 
 // resolveTemplate ensures opts.TemplateFolder is set, downloading remote
-// templates if necessary. It returns a cleanup function (nil for local templates)
-// and the clone directory (empty for local templates). The cleanup function must
-// be deferred by the caller before checking the error.
+// templates if necessary. It returns a cleanup function (nil for local templates
+// and for templates downloaded through opts.DownloadCache, which owns them) and
+// the clone directory (empty for local templates). The cleanup function must be
+// deferred by the caller before checking the error.
 func resolveTemplate(l logging.Logger, opts *options.BoilerplateOptions) (cleanup func(), cloneDir string, err error) {
 	if opts.TemplateFolder != "" {
 		return nil, "", nil
 	}
 
-	workingDir, templateFolder, downloadErr := getterhelper.DownloadTemplatesToTemporaryFolder(l, opts.TemplateURL)
+	if !isTrustedTemplate(opts.TemplateURL, opts.TrustedTemplates) {
+		return nil, "", UntrustedTemplate{TemplateURL: opts.TemplateURL, TrustedTemplates: opts.TrustedTemplates}
+	}
+
+	downloadURL := opts.TemplateURL
+
+	lockedSource, isLocked := lockfile.Source{}, false
+	if opts.LockFile != nil {
+		lockedSource, isLocked = opts.LockFile.Lookup(opts.TemplateURL)
+		if !isLocked {
+			return nil, "", TemplateNotLocked(opts.TemplateURL)
+		}
+
+		downloadURL = lockfile.PinnedURL(opts.TemplateURL, lockedSource.Checksum)
+	}
+
+	var (
+		workingDir, templateFolder string
+		downloadErr                error
+	)
 
-	cleanup = func() {
-		l.Debugf("Cleaning up working directory.")
+	if opts.DownloadCache != nil {
+		workingDir, templateFolder, downloadErr = opts.DownloadCache.Download(l, downloadURL)
+	} else {
+		workingDir, templateFolder, downloadErr = getterhelper.DownloadTemplatesToTemporaryFolder(l, downloadURL)
 
-		if rmErr := os.RemoveAll(workingDir); rmErr != nil {
-			l.Errorf("Failed to clean up working directory %s: %v", workingDir, rmErr)
+		cleanup = func() {
+			l.Debugf("Cleaning up working directory.")
+
+			if rmErr := os.RemoveAll(workingDir); rmErr != nil {
+				l.Errorf("Failed to clean up working directory %s: %v", workingDir, rmErr)
+			}
 		}
 	}
 
@@ -164,9 +234,17 @@ func resolveTemplate(l logging.Logger, opts *options.BoilerplateOptions) (cleanu
 		return cleanup, "", downloadErr
 	}
 
+	cloneDir = filepath.Join(workingDir, getterhelper.CloneSubdir)
+
+	if isLocked {
+		if err := verifyLockedSource(l, lockedSource, templateFolder, cloneDir); err != nil {
+			return cleanup, "", err
+		}
+	}
+
 	opts.TemplateFolder = templateFolder
 
-	return cleanup, filepath.Join(workingDir, getterhelper.CloneSubdir), nil
+	return cleanup, cloneDir, nil
 }
 
 // computeSourceChecksum computes the source checksum, logging a warning on
@@ -197,8 +275,9 @@ func processPartials(ctx context.Context, l logging.Logger, partials []string, o
 	return renderedPartials, nil
 }
 
-// processHooks processes the given list of hooks, which are scripts that should be executed at the command-line
-func processHooks(ctx context.Context, l logging.Logger, hooks []variables.Hook, opts *options.BoilerplateOptions, vars map[string]any) error {
+// processHooks processes the given list of hooks, which are scripts that should be executed at the command-line. The
+// values of the sensitive variables in variablesInConfig are redacted from the hook details shown to the user.
+func processHooks(ctx context.Context, l logging.Logger, hooks []variables.Hook, opts *options.BoilerplateOptions, variablesInConfig []variables.Variable, vars map[string]any) error {
 	if len(hooks) == 0 || opts.NoHooks {
 		if opts.NoHooks {
 			l.Debugf("Hooks are disabled, skipping %d hook(s)", len(hooks))
@@ -208,7 +287,9 @@ func processHooks(ctx context.Context, l logging.Logger, hooks []variables.Hook,
 	}
 
 	executeAll := opts.NonInteractive // Auto-confirm all if non-interactive
+	// Start from the answers recorded in a var file written by --save-answers, if any
 	hookAnswers := make(map[string]bool)
+	maps.Copy(hookAnswers, opts.HookAnswers)
 
 	for i := range hooks {
 		hook := &hooks[i]
@@ -241,11 +322,15 @@ func processHooks(ctx context.Context, l logging.Logger, hooks []variables.Hook,
 
 		// Handle user confirmation if needed (skip if non-interactive)
 		if !executeAll && !hookAnswers[hookKey] && !opts.NonInteractive {
-			shouldExecute, shouldSetExecuteAll, err := handleHookUserConfirmation(l, hookDetails, hookKey, hookAnswers)
+			redactedDetails := variables.RedactSensitiveText(hookDetails, vars, variablesInConfig)
+
+			shouldExecute, shouldSetExecuteAll, err := handleHookUserConfirmation(l, opts.GetPrompter(), redactedDetails, hookKey, hookAnswers)
 			if err != nil {
 				return err
 			}
 
+			opts.SaveAnswers.RecordHook(hookKey, shouldExecute)
+
 			if !shouldExecute {
 				continue
 			}
@@ -346,10 +431,10 @@ func handlePreviousHookConfirmation(l logging.Logger, hookKey string, hookAnswer
 }
 
 // handleHookUserConfirmation prompts the user for confirmation and handles the response
-func handleHookUserConfirmation(l logging.Logger, hookDetails string, hookKey string, hookAnswers map[string]bool) (bool, bool, error) {
+func handleHookUserConfirmation(l logging.Logger, prompter prompt.Prompter, hookDetails string, hookKey string, hookAnswers map[string]bool) (bool, bool, error) {
 	printHookDetails(l, hookDetails)
 
-	resp, err := prompt.PromptUserForYesNoAll("Execute hook?")
+	resp, err := prompter.YesNoAll(prompt.Question{Name: hookKey, Message: "Execute hook?"})
 	if err != nil {
 		return false, false, err
 	}
@@ -465,31 +550,253 @@ func shouldSkipHook(ctx context.Context, l logging.Logger, hook *variables.Hook,
 	return rendered == "true", nil
 }
 
-// processDependencies executes the boilerplate templates in the given list of dependencies
+// processDependencies executes the boilerplate templates in the given list of dependencies, and returns their manifest
+// entries in the order the dependencies are declared, along with their outputs by dependency name. Each dependency is
+// processed after the dependencies in its depends_on, and can read their outputs. In non-interactive mode, dependencies
+// that don't depend on each other are processed concurrently, up to opts.Parallelism at a time, and if a dependency
+// fails, the other dependencies are still processed, except for those that depend on it, and the errors of all
+// dependencies are returned together. In interactive mode, the dependencies are processed one at a time, stopping at
+// the first one that fails, so the user isn't prompted for the others.
 func processDependencies(
 	ctx context.Context,
 	l logging.Logger,
 	dependencies []variables.Dependency,
 	opts *options.BoilerplateOptions,
 	variablesInConfig map[string]variables.Variable,
-	variables map[string]any,
-) ([]manifest.ManifestDependency, error) {
+	vars map[string]any,
+) ([]manifest.ManifestDependency, map[string]any, error) {
+	order, err := variables.DependencyOrder(dependencies)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	indices := map[string]int{}
+	for i, dependency := range dependencies {
+		indices[dependency.Name] = i
+	}
+
+	results := make([][]manifest.ManifestDependency, len(dependencies))
+	outputs := make([]map[string]any, len(dependencies))
+	errs := make([]error, len(dependencies))
+
+	done := make([]chan struct{}, len(dependencies))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+
+	// Prompts can't be answered for several dependencies at once, so dependencies are only processed concurrently in
+	// non-interactive mode
+	parallelism := 1
+	if opts.NonInteractive {
+		parallelism = opts.Parallelism
+	}
+
+	var group errgroup.Group
+	if parallelism > 0 {
+		group.SetLimit(parallelism)
+	}
+
+	// Dependencies are started in order, so the dependencies they wait for have always been started before them
+	for _, i := range order {
+		dependency := &dependencies[i]
+
+		process := func() error {
+			defer close(done[i])
+
+			for _, dependsOn := range dependency.DependsOn {
+				<-done[indices[dependsOn]]
+
+				if errs[indices[dependsOn]] != nil {
+					errs[i] = DependencyNotProcessed{DependencyName: dependency.Name, FailedDependencyName: dependsOn}
+					return nil
+				}
+			}
+
+			dependencyOpts := opts
+			if parallelism != 1 {
+				// Rendering the settings of a dependency may record answers for shell commands, so give each
+				// dependency its own copy of them
+				optsCopy := *opts
+				optsCopy.ShellCommandAnswers = maps.Clone(opts.ShellCommandAnswers)
+				dependencyOpts = &optsCopy
+			}
+
+			// Let the dependency read the outputs of the dependencies it depends on, directly or not, which are all done
+			depsOutputs := map[string]any{}
+			collectDependsOnOutputs(dependencies, indices, i, outputs, depsOutputs)
+
+			for _, sibling := range dependencies {
+				if _, isCollected := depsOutputs[sibling.Name]; !isCollected && sibling.Name != dependency.Name {
+					depsOutputs[sibling.Name] = unlistedDependency{dependencyName: sibling.Name, readerName: dependency.Name}
+				}
+			}
+
+			dependencyVars := util.MergeMaps(vars, map[string]any{depsVarName: depsOutputs})
+
+			results[i], outputs[i], errs[i] = processDependency(ctx, l, dependency, dependencyOpts, variablesInConfig, dependencyVars)
+
+			return nil
+		}
+
+		if opts.NonInteractive {
+			group.Go(process)
+			continue
+		}
+
+		_ = process()
+
+		if errs[i] != nil {
+			break
+		}
+	}
+
+	_ = group.Wait()
+
 	var allDeps []manifest.ManifestDependency
 
+	allOutputs := map[string]any{}
+
+	// In non-interactive mode, collect the missing and invalid variables of all dependencies, so they can all be
+	// reported at once
+	invalidVariables := config.InvalidVariables{}
+	dependencyErrors := DependencyErrors{}
+	onlyInvalidVariables := true
+
 	for i := range dependencies {
-		deps, err := processDependency(ctx, l, &dependencies[i], opts, variablesInConfig, variables)
+		if errs[i] == nil {
+			allDeps = append(allDeps, results[i]...)
+			allOutputs[dependencies[i].Name] = outputs[i]
+
+			continue
+		}
+
+		dependencyErrors = append(dependencyErrors, DependencyError{DependencyName: dependencies[i].Name, Err: errs[i]})
+
+		var depInvalidVariables config.InvalidVariables
+
+		switch {
+		case opts.NonInteractive && errors.As(errs[i], &depInvalidVariables):
+			invalidVariables.Issues = append(invalidVariables.Issues, depInvalidVariables.ForDependency(dependencies[i].Name).Issues...)
+		case errors.As(errs[i], &DependencyNotProcessed{}):
+		default:
+			onlyInvalidVariables = false
+		}
+	}
+
+	if len(dependencyErrors) == 0 {
+		return allDeps, allOutputs, nil
+	}
+
+	if onlyInvalidVariables && len(invalidVariables.Issues) > 0 {
+		return nil, nil, invalidVariables
+	}
+
+	return nil, nil, dependencyErrors
+}
+
+// collectDependsOnOutputs adds the outputs of the dependencies that the dependency at the given index depends on,
+// directly or not, to the given map.
+func collectDependsOnOutputs(dependencies []variables.Dependency, indices map[string]int, i int, outputs []map[string]any, collected map[string]any) {
+	for _, dependsOn := range dependencies[i].DependsOn {
+		if _, isCollected := collected[dependsOn]; isCollected {
+			continue
+		}
+
+		collected[dependsOn] = outputs[indices[dependsOn]]
+		collectDependsOnOutputs(dependencies, indices, indices[dependsOn], outputs, collected)
+	}
+}
+
+// unlistedDependency takes the place of a sibling dependency in the .Deps of a dependency that doesn't depend on it,
+// directly or not. The sibling may not have been processed yet, so reading its outputs is an error, rather than
+// silently rendering nothing.
+type unlistedDependency struct {
+	dependencyName string
+	readerName     string
+}
+
+func (dep unlistedDependency) Outputs() (any, error) {
+	return nil, DependencyNotInDependsOn{DependencyName: dep.readerName, ReadDependencyName: dep.dependencyName}
+}
+
+func (dep unlistedDependency) Skipped() (bool, error) {
+	return false, DependencyNotInDependsOn{DependencyName: dep.readerName, ReadDependencyName: dep.dependencyName}
+}
+
+// renderOutputs renders the given output expressions of a template with its variables.
+func renderOutputs(ctx context.Context, l logging.Logger, outputs map[string]string, opts *options.BoilerplateOptions, vars map[string]any) (map[string]string, error) {
+	rendered := make(map[string]string, len(outputs))
+
+	for name, expression := range outputs {
+		value, err := render.RenderTemplateFromStringWithContext(ctx, l, opts.TemplateFolder, expression, vars, opts)
 		if err != nil {
-			return nil, err
+			return nil, InvalidOutput{Name: name, Err: err}
+		}
+
+		rendered[name] = value
+	}
+
+	return rendered, nil
+}
+
+// collectDependencyVariableIssues returns the missing and invalid variables of the given dependencies and their
+// nested dependencies, without generating any files. This is used in non-interactive mode when the variables of the
+// parent template are already missing or invalid, so the dependencies can't be processed, but their variables can
+// still be reported along with those of the parent. This is best-effort: dependencies that are skipped, that use
+// remote templates, or whose options can't be rendered with the variables that do have values are not checked.
+func collectDependencyVariableIssues(
+	ctx context.Context,
+	l logging.Logger,
+	dependencies []variables.Dependency,
+	opts *options.BoilerplateOptions,
+	rootBoilerplateConfig *config.BoilerplateConfig,
+	variablesInConfig map[string]variables.Variable,
+	vars map[string]any,
+) []config.VariableIssue {
+	var issues []config.VariableIssue
+
+	for i := range dependencies {
+		dependency := &dependencies[i]
+
+		if skip, err := shouldSkipDependency(ctx, l, dependency, opts, vars); err != nil || skip {
+			continue
+		}
+
+		dependencyOptions, err := cloneOptionsForDependency(ctx, l, dependency, opts, variablesInConfig, vars)
+		if err != nil || dependencyOptions.TemplateFolder == "" {
+			continue
+		}
+
+		dependencyConfig, err := config.LoadBoilerplateConfig(l, dependencyOptions)
+		if err != nil {
+			continue
+		}
+
+		dependencyVars, err := config.GetVariablesWithContext(ctx, l, dependencyOptions, dependencyConfig, rootBoilerplateConfig, dependency)
+
+		var invalidVariables config.InvalidVariables
+		if errors.As(err, &invalidVariables) {
+			issues = append(issues, invalidVariables.ForDependency(dependency.Name).Issues...)
+		}
+
+		if dependencyVars == nil {
+			continue
 		}
 
-		allDeps = append(allDeps, deps...)
+		nested := config.InvalidVariables{
+			Issues: collectDependencyVariableIssues(ctx, l, dependencyConfig.Dependencies, dependencyOptions, rootBoilerplateConfig, dependencyConfig.GetVariablesMap(), dependencyVars),
+		}
+		issues = append(issues, nested.ForDependency(dependency.Name).Issues...)
 	}
 
-	return allDeps, nil
+	return issues
 }
 
 // processDependency processes a single dependency and returns manifest entries for it.
 // A single dependency with for_each can produce multiple entries.
+// Also returns what the templates that can see the dependency read with .Deps.<name>: its Outputs, which are a list
+// with the outputs of each iteration for a dependency with for_each, and whether it was Skipped, in which case it has
+// no outputs.
 func processDependency(
 	ctx context.Context,
 	l logging.Logger,
@@ -497,10 +804,10 @@ func processDependency(
 	opts *options.BoilerplateOptions,
 	variablesInConfig map[string]variables.Variable,
 	originalVars map[string]any,
-) ([]manifest.ManifestDependency, error) {
+) ([]manifest.ManifestDependency, map[string]any, error) {
 	shouldProcess, err := shouldProcessDependency(ctx, l, dependency, opts, originalVars)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if !shouldProcess {
@@ -518,26 +825,30 @@ func processDependency(
 			OutputFolder:         dependency.OutputFolder,
 			Skip:                 renderedSkip,
 			ForEach:              dependency.ForEach,
+			ForEachValues:        dependency.ForEachValues,
 			ForEachReference:     dependency.ForEachReference,
 			DontInheritVariables: dependency.DontInheritVariables,
-		}}, nil
+		}}, map[string]any{"Outputs": map[string]string{}, "Skipped": true}, nil
 	}
 
-	doProcess := func(ctx context.Context, updatedVars map[string]any, forEach []string) (manifest.ManifestDependency, error) {
+	doProcess := func(ctx context.Context, updatedVars map[string]any, forEachItems []any) (manifest.ManifestDependency, map[string]string, error) {
 		dependencyOptions, cloneErr := cloneOptionsForDependency(ctx, l, dependency, opts, variablesInConfig, updatedVars)
 		if cloneErr != nil {
-			return manifest.ManifestDependency{}, cloneErr
+			return manifest.ManifestDependency{}, nil, cloneErr
 		}
 
 		l.Debugf("Processing dependency %s, with template folder %s and output folder %s", dependency.Name, dependencyOptions.TemplateFolder, dependencyOptions.OutputFolder)
 
 		depResult, processErr := ProcessTemplateWithContext(ctx, l, dependencyOptions, opts, dependency)
 		if processErr != nil {
-			return manifest.ManifestDependency{}, processErr
+			return manifest.ManifestDependency{}, nil, processErr
 		}
 
-		// Use the dependency's result variables, which already have builtins filtered out.
-		resolvedVars := maps.Clone(depResult.Variables)
+		// Use the dependency's result variables, which already have builtins filtered out and the variables that are
+		// sensitive in the dependency redacted. Variables that are sensitive in this template or on the dependency
+		// itself may be inherited by the dependency without being declared there, so redact those too.
+		resolvedVars := variables.RedactSensitiveVariables(depResult.Variables, slices.Concat(slices.Collect(maps.Values(variablesInConfig)), dependency.Variables))
+		resolvedVars = variables.RedactNamedVariables(resolvedVars, dependencyOptions.SensitiveVars)
 
 		// Compute checksums for files generated by this dependency.
 		var depFiles []manifest.GeneratedFile
@@ -548,7 +859,7 @@ func processDependency(
 
 				checksum, csErr := manifest.SHA256File(absPath)
 				if csErr != nil {
-					return manifest.ManifestDependency{}, csErr
+					return manifest.ManifestDependency{}, nil, csErr
 				}
 
 				depFiles = append(depFiles, manifest.GeneratedFile{
@@ -558,6 +869,8 @@ func processDependency(
 			}
 		}
 
+		forEach, forEachValues := variables.SplitForEachItems(forEachItems)
+
 		return manifest.ManifestDependency{
 			Name:                 dependency.Name,
 			TemplateURL:          dependencyOptions.TemplateURL,
@@ -565,34 +878,40 @@ func processDependency(
 			SourceChecksum:       depResult.SourceChecksum,
 			Skip:                 dependency.Skip,
 			ForEach:              forEach,
+			ForEachValues:        forEachValues,
 			ForEachReference:     dependency.ForEachReference,
 			VarFiles:             dependency.VarFiles,
 			Variables:            resolvedVars,
 			Files:                depFiles,
 			DontInheritVariables: dependency.DontInheritVariables,
 			Dependencies:         depResult.Dependencies,
-		}, nil
+		}, depResult.Outputs, nil
 	}
 
-	forEach := dependency.ForEach
+	forEach := dependency.ForEachList()
 
 	if len(dependency.ForEachReference) > 0 {
 		renderedReference, renderErr := render.RenderTemplateFromStringWithContext(ctx, l, opts.TemplateFolder, dependency.ForEachReference, originalVars, opts)
 		if renderErr != nil {
-			return nil, renderErr
+			return nil, nil, renderErr
 		}
 
-		value, unmarshalErr := variables.UnmarshalListOfStrings(originalVars, renderedReference)
-		if unmarshalErr != nil {
-			return nil, unmarshalErr
-		}
+		forEach = nil
+
+		if value, hasValue := originalVars[renderedReference]; hasValue && value != nil {
+			items, itemsErr := variables.ForEachItems(value)
+			if itemsErr != nil {
+				return nil, nil, itemsErr
+			}
 
-		forEach = value
+			forEach = items
+		}
 	}
 
 	if len(forEach) > 0 {
 		// Pre-allocate results to preserve input ordering regardless of goroutine scheduling.
 		allDeps := make([]manifest.ManifestDependency, len(forEach))
+		allOutputs := make([]map[string]string, len(forEach))
 
 		g, ctx := errgroup.WithContext(ctx)
 		if opts.Parallelism > 0 {
@@ -603,30 +922,31 @@ func processDependency(
 			g.Go(func() error {
 				updatedVars := util.MergeMaps(originalVars, map[string]any{eachVarName: item})
 
-				dep, processErr := doProcess(ctx, updatedVars, []string{item})
+				dep, outputs, processErr := doProcess(ctx, updatedVars, []any{item})
 				if processErr != nil {
 					return processErr
 				}
 
 				allDeps[i] = dep
+				allOutputs[i] = outputs
 
 				return nil
 			})
 		}
 
 		if err := g.Wait(); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
-		return allDeps, nil
+		return allDeps, map[string]any{"Outputs": allOutputs, "Skipped": false}, nil
 	}
 
-	dep, processErr := doProcess(ctx, originalVars, nil)
+	dep, outputs, processErr := doProcess(ctx, originalVars, nil)
 	if processErr != nil {
-		return nil, processErr
+		return nil, nil, processErr
 	}
 
-	return []manifest.ManifestDependency{dep}, nil
+	return []manifest.ManifestDependency{dep}, map[string]any{"Outputs": outputs, "Skipped": false}, nil
 }
 
 // Clone the given options for use when rendering the given dependency. The dependency will get the same options as
@@ -672,24 +992,42 @@ func cloneOptionsForDependency(
 		renderedVarFiles = append(renderedVarFiles, renderedVarFilePath)
 	}
 
-	vars, err := cloneVariablesForDependency(ctx, l, originalOpts, dependency, variablesInConfig, variables, renderedVarFiles)
+	vars, varFileSensitiveVars, err := cloneVariablesForDependency(ctx, l, originalOpts, dependency, variablesInConfig, variables, renderedVarFiles)
 	if err != nil {
 		return nil, err
 	}
 
+	// Match the answers of a dependency with for_each to the item they were recorded for
+	var forEachItem any
+	if len(dependency.ForEachList()) > 0 || dependency.ForEachReference != "" {
+		forEachItem = variables[eachVarName]
+	}
+
 	return &options.BoilerplateOptions{
 		Vars:                    vars,
+		SensitiveVars:           sensitiveVarsForDependency(dependency, originalOpts.SensitiveVars, varFileSensitiveVars),
+		DefaultsFileVars:        defaultsFileVarsForDependency(dependency, originalOpts.DefaultsFileVars),
+		Overrides:               dependency.InheritedOverrides(originalOpts.Overrides),
+		PreviousAnswers:         originalOpts.PreviousAnswers.ForDependency(dependency.Name, forEachItem),
+		SaveAnswers:             originalOpts.SaveAnswers.ForDependency(dependency.Name),
+		Prompter:                originalOpts.Prompter,
+		ShellCommandAnswers:     maps.Clone(originalOpts.ShellCommandAnswers),
+		HookAnswers:             maps.Clone(originalOpts.HookAnswers),
+		DependencyAnswers:       dependencyAnswersForDependency(dependency.Name, originalOpts.DependencyAnswers),
 		TemplateURL:             templateURL,
 		TemplateFolder:          templateFolder,
 		OutputFolder:            outputFolder,
 		OnMissingKey:            originalOpts.OnMissingKey,
 		OnMissingConfig:         originalOpts.OnMissingConfig,
 		NonInteractive:          originalOpts.NonInteractive,
+		TrustedTemplates:        originalOpts.TrustedTemplates,
 		NoHooks:                 originalOpts.NoHooks,
 		NoShell:                 originalOpts.NoShell,
 		DisableDependencyPrompt: originalOpts.DisableDependencyPrompt,
 		Manifest:                originalOpts.Manifest,
 		ManifestFile:            originalOpts.ManifestFile,
+		LockFile:                originalOpts.LockFile,
+		DownloadCache:           originalOpts.DownloadCache,
 		Parallelism:             originalOpts.Parallelism,
 	}, nil
 }
@@ -702,6 +1040,8 @@ func cloneOptionsForDependency(
 //     DontInheritVariables is set.
 //   - Variables defined from VarFiles set on the dependency.
 //   - Variables defaults set on the dependency.
+//
+// Also returns the names of the variables that the dependency's var files marked as sensitive.
 func cloneVariablesForDependency(
 	ctx context.Context,
 	l logging.Logger,
@@ -710,7 +1050,7 @@ func cloneVariablesForDependency(
 	variablesInConfig map[string]variables.Variable,
 	originalVariables map[string]any,
 	renderedVarFiles []string,
-) (map[string]any, error) {
+) (map[string]any, []string, error) {
 	// Clone the opts so that we attempt to get the value for the variable, and we can error on any variable that is set
 	// on a dependency and the value can't be computed.
 	dependencyOpts := &options.BoilerplateOptions{
@@ -737,18 +1077,19 @@ func cloneVariablesForDependency(
 	// handled later.
 	newVariables := map[string]any{}
 
+	// The outputs of other dependencies are not passed down, as the dependency gets the outputs of its own dependencies.
 	if !dependency.DontInheritVariables {
 		for key, value := range originalVariables {
 			dependencyName, _ := variables.SplitIntoDependencyNameAndVariableName(key)
-			if dependencyName == "" {
+			if dependencyName == "" && key != depsVarName {
 				newVariables[key] = value
 			}
 		}
 	}
 
-	varFileVars, err := variables.ParseVars(nil, renderedVarFiles)
+	varFileVars, varFileSensitiveVars, err := variables.ParseVarsWithSensitive(nil, renderedVarFiles)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	currentVariables := util.MergeMaps(originalVariables, varFileVars)
@@ -762,13 +1103,13 @@ func cloneVariablesForDependency(
 			0,
 		)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		// If the value is a string, render it
 		if strValue, ok := varValue.(string); ok {
 			renderedValue, err := render.RenderTemplateFromStringWithContext(ctx, l, opts.TemplateFolder, strValue, currentVariables, opts)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 
 			varValue = renderedValue
@@ -784,7 +1125,7 @@ func cloneVariablesForDependency(
 	newVariables = util.MergeMaps(newVariables, varFileVars)
 
 	if dependency.DontInheritVariables {
-		return newVariables, nil
+		return newVariables, varFileSensitiveVars, nil
 	}
 
 	// Now handle the CLI passed variables. Note that we handle dependency namespaced values separately, as they have
@@ -806,10 +1147,81 @@ func cloneVariablesForDependency(
 		}
 	}
 
-	return newVariables, nil
+	return newVariables, varFileSensitiveVars, nil
 }
 
-// Prompt the user to verify if the given dependency should be executed and return true if they confirm. If
+// Return the values from defaults files that apply to the given dependency, using the same DEPENDENCY.VARNAME
+// namespacing as cloneVariablesForDependency does for the variables passed in. They are only used as fallback defaults,
+// so they never override the variables set in the dependency block.
+func defaultsFileVarsForDependency(dependency *variables.Dependency, defaultsFileVars map[string]any) map[string]any {
+	if dependency.DontInheritVariables || len(defaultsFileVars) == 0 {
+		return nil
+	}
+
+	dependencyDefaults := map[string]any{}
+
+	for key, value := range defaultsFileVars {
+		if dependencyName, _ := variables.SplitIntoDependencyNameAndVariableName(key); dependencyName != dependency.Name {
+			dependencyDefaults[key] = value
+		}
+	}
+
+	// Namespaced values take precedence over those that are not
+	for key, value := range defaultsFileVars {
+		if dependencyName, originalName := variables.SplitIntoDependencyNameAndVariableName(key); dependencyName == dependency.Name {
+			dependencyDefaults[originalName] = value
+		}
+	}
+
+	return dependencyDefaults
+}
+
+// Return the recorded decisions for the dependencies of the given dependency, which are namespaced with its name,
+// using the same DEPENDENCY.NAME namespacing as variables.
+func dependencyAnswersForDependency(dependencyName string, dependencyAnswers map[string]bool) map[string]bool {
+	var answers map[string]bool
+
+	for name, process := range dependencyAnswers {
+		if nestedName, isNested := strings.CutPrefix(name, dependencyName+"."); isNested {
+			if answers == nil {
+				answers = map[string]bool{}
+			}
+
+			answers[nestedName] = process
+		}
+	}
+
+	return answers
+}
+
+// Return the names of the variables that are sensitive for the given dependency: those marked as sensitive by the
+// dependency's own var files, plus those passed to the parent template that the dependency inherits, using the same
+// DEPENDENCY.VARNAME namespacing as cloneVariablesForDependency.
+func sensitiveVarsForDependency(dependency *variables.Dependency, originalSensitiveVars []string, varFileSensitiveVars []string) []string {
+	sensitiveVars := slices.Clone(varFileSensitiveVars)
+
+	if !dependency.DontInheritVariables {
+		for _, name := range originalSensitiveVars {
+			dependencyName, originalName := variables.SplitIntoDependencyNameAndVariableName(name)
+
+			switch dependencyName {
+			case "":
+				sensitiveVars = append(sensitiveVars, name)
+			case dependency.Name:
+				sensitiveVars = append(sensitiveVars, originalName)
+			}
+		}
+	}
+
+	if len(sensitiveVars) == 0 {
+		return nil
+	}
+
+	return sensitiveVars
+}
+
+// Prompt the user to verify if the given dependency should be executed and return true if they confirm. If the answer
+// was recorded in a var file written by --save-answers, that answer is used instead. Otherwise, if
 // options.NonInteractive or options.DisableDependencyPrompt are set to true, this function always returns true.
 func shouldProcessDependency(
 	ctx context.Context,
@@ -827,11 +1239,26 @@ func shouldProcessDependency(
 		return false, nil
 	}
 
+	if process, isRecorded := opts.DependencyAnswers[dependency.Name]; isRecorded {
+		l.Debugf("Using the recorded answer for whether to process dependency %s: %t", dependency.Name, process)
+		return process, nil
+	}
+
 	if opts.NonInteractive || opts.DisableDependencyPrompt {
 		return true, nil
 	}
 
-	return prompt.PromptUserForYesNo(fmt.Sprintf("Process dependency '%s'?", dependency.Name))
+	process, err := opts.GetPrompter().YesNo(prompt.Question{
+		Name:    dependency.Name,
+		Message: fmt.Sprintf("Process dependency '%s'?", dependency.Name),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	opts.SaveAnswers.RecordDependency(dependency.Name, process)
+
+	return process, nil
 }
 
 // Return true if the skip parameter of the given dependency evaluates to a "true" value
@@ -1114,3 +1541,76 @@ func pathInAnySkipNotPath(canonicalPath string, skipFileList []ProcessedSkipFile
 
 	return false
 }
+
+// Custom error types
+
+type DependencyError struct {
+	Err            error
+	DependencyName string
+}
+
+func (err DependencyError) Error() string {
+	return fmt.Sprintf("Dependency '%s' failed: %v", err.DependencyName, err.Err)
+}
+
+func (err DependencyError) Unwrap() error {
+	return err.Err
+}
+
+// DependencyErrors are the errors of all the dependencies of a template that failed.
+type DependencyErrors []DependencyError
+
+func (errs DependencyErrors) Error() string {
+	if len(errs) == 1 {
+		return errs[0].Error()
+	}
+
+	lines := make([]string, 0, len(errs)+1)
+	lines = append(lines, fmt.Sprintf("%d dependencies failed:", len(errs)))
+
+	for _, err := range errs {
+		lines = append(lines, fmt.Sprintf("  - %s: %v", err.DependencyName, err.Err))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (errs DependencyErrors) Unwrap() []error {
+	unwrapped := make([]error, 0, len(errs))
+	for _, err := range errs {
+		unwrapped = append(unwrapped, err)
+	}
+
+	return unwrapped
+}
+
+type DependencyNotProcessed struct {
+	DependencyName       string
+	FailedDependencyName string
+}
+
+func (err DependencyNotProcessed) Error() string {
+	return fmt.Sprintf("Not processed, as it depends on '%s', which failed", err.FailedDependencyName)
+}
+
+type DependencyNotInDependsOn struct {
+	DependencyName     string
+	ReadDependencyName string
+}
+
+func (err DependencyNotInDependsOn) Error() string {
+	return fmt.Sprintf("Dependency '%s' reads .Deps.%s, but '%s' is not in its depends_on, so it may not have been processed yet. Add it to depends_on.", err.DependencyName, err.ReadDependencyName, err.ReadDependencyName)
+}
+
+type InvalidOutput struct {
+	Err  error
+	Name string
+}
+
+func (err InvalidOutput) Error() string {
+	return fmt.Sprintf("Could not render the output '%s': %v", err.Name, err.Err)
+}
+
+func (err InvalidOutput) Unwrap() error {
+	return err.Err
+}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/gruntwork-io/boilerplate/options"
 	"github.com/gruntwork-io/boilerplate/pkg/logging"
+	"github.com/gruntwork-io/boilerplate/prompt"
 	"github.com/gruntwork-io/boilerplate/templates"
 	"github.com/gruntwork-io/boilerplate/variables"
 )
@@ -501,3 +502,140 @@ func readOutputFile(t *testing.T, opts *options.BoilerplateOptions, item string)
 
 	return string(content)
 }
+
+// createDependsOnFixture sets up a parent template with the given dependencies, which can use a "slow" child template
+// that sleeps in an after hook, a "fast" child template, and a "broken" child template that fails to render. Each
+// child writes the name of its output folder to output.txt. Returns the configured options pointing at the parent.
+func createDependsOnFixture(t *testing.T, dependencies string) *options.BoilerplateOptions {
+	t.Helper()
+
+	tempDir := t.TempDir()
+
+	children := map[string]string{
+		"slow":   "variables: []\nhooks:\n  after:\n    - command: sleep\n      args:\n        - \"0.3\"\n",
+		"fast":   "variables: []\n",
+		"broken": "variables: []\n",
+	}
+
+	for name, childConfig := range children {
+		childDir := filepath.Join(tempDir, name)
+		require.NoError(t, os.MkdirAll(childDir, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(childDir, "boilerplate.yml"), []byte(childConfig), 0o644))
+
+		content := "{{ outputFolder | base }}"
+		if name == "broken" {
+			content = "{{ .ThisVarDoesNotExist }}"
+		}
+
+		require.NoError(t, os.WriteFile(filepath.Join(childDir, "output.txt"), []byte(content), 0o644))
+	}
+
+	parentDir := filepath.Join(tempDir, "parent")
+	require.NoError(t, os.MkdirAll(parentDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(parentDir, "boilerplate.yml"), []byte("variables: []\ndependencies:\n"+dependencies), 0o644))
+
+	return &options.BoilerplateOptions{
+		ShellCommandAnswers:     make(map[string]bool),
+		TemplateURL:             parentDir,
+		TemplateFolder:          parentDir,
+		OutputFolder:            filepath.Join(tempDir, "output"),
+		OnMissingKey:            options.ExitWithError,
+		OnMissingConfig:         options.Exit,
+		NonInteractive:          true,
+		DisableDependencyPrompt: true,
+		Parallelism:             4,
+	}
+}
+
+func TestDependsOn_DependentsWaitAndOthersRunConcurrently(t *testing.T) {
+	t.Parallel()
+
+	opts := createDependsOnFixture(t, `  - name: network
+    template-url: ../slow
+    output-folder: network
+  - name: database
+    template-url: ../fast
+    output-folder: database
+    depends_on: [network]
+  - name: cache
+    template-url: ../fast
+    output-folder: cache
+`)
+
+	result, err := templates.ProcessTemplateWithContext(t.Context(), logging.Discard(), opts, opts, &variables.Dependency{})
+	require.NoError(t, err)
+
+	modTimes := map[string]time.Time{}
+
+	for _, name := range []string{"network", "database", "cache"} {
+		info, err := os.Stat(filepath.Join(opts.OutputFolder, name, "output.txt"))
+		require.NoError(t, err)
+
+		modTimes[name] = info.ModTime()
+	}
+
+	// database waits for the after hook of network, while cache doesn't
+	assert.GreaterOrEqual(t, modTimes["database"].Sub(modTimes["network"]), 250*time.Millisecond)
+	assert.Less(t, modTimes["cache"].Sub(modTimes["network"]), 250*time.Millisecond)
+
+	// The manifest entries are still in declaration order
+	names := []string{}
+	for _, dependency := range result.Dependencies {
+		names = append(names, dependency.Name)
+	}
+
+	assert.Equal(t, []string{"network", "database", "cache"}, names)
+}
+
+func TestDependsOn_ErrorsCollectedPerDependency(t *testing.T) {
+	t.Parallel()
+
+	opts := createDependsOnFixture(t, `  - name: network
+    template-url: ../broken
+    output-folder: network
+  - name: database
+    template-url: ../fast
+    output-folder: database
+    depends_on: [network]
+  - name: cache
+    template-url: ../fast
+    output-folder: cache
+`)
+
+	_, err := templates.ProcessTemplateWithContext(t.Context(), logging.Discard(), opts, opts, &variables.Dependency{})
+
+	var dependencyErrors templates.DependencyErrors
+	require.ErrorAs(t, err, &dependencyErrors)
+	require.Len(t, dependencyErrors, 2)
+	assert.Equal(t, "network", dependencyErrors[0].DependencyName)
+	assert.Equal(t, "database", dependencyErrors[1].DependencyName)
+	require.ErrorAs(t, dependencyErrors[1], &templates.DependencyNotProcessed{})
+
+	// The dependency that doesn't depend on the broken one is still processed
+	assert.Equal(t, "cache", readOutputFile(t, opts, "cache"))
+	assert.NoFileExists(t, filepath.Join(opts.OutputFolder, "database", "output.txt"))
+}
+
+func TestDependsOn_InteractiveStopsAtFirstError(t *testing.T) {
+	t.Parallel()
+
+	opts := createDependsOnFixture(t, `  - name: network
+    template-url: ../broken
+    output-folder: network
+  - name: cache
+    template-url: ../fast
+    output-folder: cache
+`)
+	opts.NonInteractive = false
+	opts.DisableDependencyPrompt = false
+	// There is no answer for whether to process cache, so prompting for it would fail too
+	opts.Prompter = prompt.NewScriptedPrompter(map[string]any{"network": true})
+
+	_, err := templates.ProcessTemplateWithContext(t.Context(), logging.Discard(), opts, opts, &variables.Dependency{})
+
+	var dependencyErrors templates.DependencyErrors
+	require.ErrorAs(t, err, &dependencyErrors)
+	require.Len(t, dependencyErrors, 1)
+	assert.Equal(t, "network", dependencyErrors[0].DependencyName)
+	assert.NoFileExists(t, filepath.Join(opts.OutputFolder, "cache", "output.txt"))
+}
@@ -1,12 +1,17 @@
 package templates //nolint:testpackage
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/gruntwork-io/boilerplate/config"
+	"github.com/gruntwork-io/boilerplate/getterhelper"
+	"github.com/gruntwork-io/boilerplate/manifest"
 	"github.com/gruntwork-io/boilerplate/options"
 	"github.com/gruntwork-io/boilerplate/pkg/logging"
+	"github.com/gruntwork-io/boilerplate/prompt"
 	"github.com/gruntwork-io/boilerplate/testutil"
 	"github.com/gruntwork-io/boilerplate/variables"
 	"github.com/stretchr/testify/assert"
@@ -215,7 +220,7 @@ func TestCloneVariablesForDependency(t *testing.T) {
 
 			opts := testutil.CreateTestOptionsWithOutput("/template/path/", "/output/path/")
 			opts.Vars = tt.optsVars
-			actualVariables, err := cloneVariablesForDependency(t.Context(), logging.Discard(), opts, tt.dependency, nil, tt.variables, nil)
+			actualVariables, _, err := cloneVariablesForDependency(t.Context(), logging.Discard(), opts, tt.dependency, nil, tt.variables, nil)
 			require.NoError(t, err)
 			assert.Equal(t, tt.expectedVariables, actualVariables, "Dependency: %s", tt.dependency)
 		})
@@ -318,7 +323,7 @@ func TestForEachReferenceRendersAsTemplate(t *testing.T) {
 
 	opts := testutil.CreateTestOptionsWithOutput(templateFolder, tempDir)
 
-	_, err = processDependency(t.Context(), logging.Discard(), dependency, opts, nil, vars)
+	_, _, err = processDependency(t.Context(), logging.Discard(), dependency, opts, nil, vars)
 	require.NoError(t, err)
 
 	// Should create directories "a" and "b" from template1 list
@@ -327,3 +332,765 @@ func TestForEachReferenceRendersAsTemplate(t *testing.T) {
 		require.NoError(t, err)
 	}
 }
+
+func TestProcessTemplateRedactsSensitiveVariables(t *testing.T) {
+	t.Parallel()
+
+	templateDir := t.TempDir()
+	outputDir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(templateDir, "boilerplate.yml"), []byte(`variables:
+  - name: Name
+  - name: DBPassword
+    sensitive: true
+dependencies:
+  - name: child
+    template-url: ./child
+    output-folder: child
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(templateDir, "password.txt"), []byte("{{ .DBPassword }}"), 0644))
+
+	childDir := filepath.Join(templateDir, "child")
+	require.NoError(t, os.MkdirAll(childDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(childDir, "boilerplate.yml"), []byte(`variables:
+  - name: DBPassword
+`), 0644))
+
+	opts := &options.BoilerplateOptions{
+		TemplateFolder:  templateDir,
+		OutputFolder:    outputDir,
+		NonInteractive:  true,
+		OnMissingKey:    options.ExitWithError,
+		OnMissingConfig: options.Exit,
+		Vars:            map[string]any{"Name": "app", "DBPassword": "hunter2"},
+	}
+
+	result, err := ProcessTemplateWithContext(t.Context(), logging.Discard(), opts, opts, &variables.Dependency{})
+	require.NoError(t, err)
+
+	// The generated files still get the real value
+	content, err := os.ReadFile(filepath.Join(outputDir, "password.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", string(content))
+
+	assert.Equal(t, "app", result.Variables["Name"])
+	assert.Equal(t, variables.SensitiveValuePlaceholder, result.Variables["DBPassword"])
+	require.Len(t, result.Dependencies, 1)
+	assert.Equal(t, variables.SensitiveValuePlaceholder, result.Dependencies[0].Variables["DBPassword"])
+}
+
+func TestProcessTemplateNonInteractiveReportsDependencyVariables(t *testing.T) {
+	t.Parallel()
+
+	writeTemplate := func(t *testing.T, dir string, config string) {
+		t.Helper()
+
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "boilerplate.yml"), []byte(config), 0644))
+	}
+
+	templateDir := t.TempDir()
+	writeTemplate(t, templateDir, `variables:
+  - name: Name
+dependencies:
+  - name: api
+    template-url: ./api
+    output-folder: api
+  - name: web
+    template-url: ./web
+    output-folder: web
+`)
+	writeTemplate(t, filepath.Join(templateDir, "api"), `variables:
+  - name: Port
+    type: int
+dependencies:
+  - name: db
+    template-url: ../db
+    output-folder: db
+`)
+	writeTemplate(t, filepath.Join(templateDir, "web"), `variables:
+  - name: Domain
+    validations:
+      - hostname
+`)
+	writeTemplate(t, filepath.Join(templateDir, "db"), `variables:
+  - name: Engine
+`)
+
+	testCases := []struct {
+		vars     map[string]any
+		name     string
+		expected []string
+	}{
+		{
+			name:     "missing-in-parent-and-dependencies",
+			vars:     map[string]any{"Domain": "not a domain"},
+			expected: []string{"Name", "api.Port", "api.db.Engine", "web.Domain"},
+		},
+		{
+			name:     "missing-in-dependencies-only",
+			vars:     map[string]any{"Name": "app", "Engine": "postgres"},
+			expected: []string{"api.Port", "web.Domain"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := &options.BoilerplateOptions{
+				TemplateFolder:  templateDir,
+				OutputFolder:    t.TempDir(),
+				NonInteractive:  true,
+				OnMissingKey:    options.ExitWithError,
+				OnMissingConfig: options.Exit,
+				Vars:            testCase.vars,
+			}
+
+			_, err := ProcessTemplateWithContext(t.Context(), logging.Discard(), opts, opts, &variables.Dependency{})
+
+			var invalidVariables config.InvalidVariables
+			require.ErrorAs(t, err, &invalidVariables)
+
+			actual := make([]string, 0, len(invalidVariables.Issues))
+			for _, issue := range invalidVariables.Issues {
+				actual = append(actual, issue.VariableName)
+			}
+
+			assert.Equal(t, testCase.expected, actual)
+		})
+	}
+}
+
+func TestProcessTemplateRedactsSensitiveVariablesInCommandLogs(t *testing.T) {
+	t.Parallel()
+
+	templateDir := t.TempDir()
+	outputDir := t.TempDir()
+
+	testutil.WriteFiles(t, templateDir, map[string]string{
+		"boilerplate.yml": `variables:
+  - name: Token
+    sensitive: true
+hooks:
+  after:
+    - command: echo
+      args: ["--token", "{{ .Token }}"]
+`,
+		"token.txt": `{{ shell "echo" "-n" .Token }}`,
+	})
+
+	opts := testutil.CreateTestOptionsWithOutput(templateDir, outputDir)
+	opts.NoHooks = false
+	opts.Vars = map[string]any{"Token": "hunter2"}
+
+	var logs bytes.Buffer
+
+	_, err := ProcessTemplateWithContext(t.Context(), logging.New(&logs, logging.LevelDebug), opts, opts, &variables.Dependency{})
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "token.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", string(content))
+
+	assert.Contains(t, logs.String(), "Running command: echo --token "+variables.SensitiveValuePlaceholder)
+	assert.NotContains(t, logs.String(), "hunter2")
+}
+
+func TestProcessTemplateRedactsSensitiveTerraformOutputs(t *testing.T) {
+	t.Parallel()
+
+	templateDir := t.TempDir()
+	outputDir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(templateDir, "boilerplate.yml"), []byte(`variables:
+  - name: net_vpc_id
+  - name: net_db_password
+dependencies:
+  - name: child
+    template-url: ./child
+    output-folder: child
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(templateDir, "vpc.txt"), []byte("{{ .net_vpc_id }}"), 0644))
+
+	childDir := filepath.Join(templateDir, "child")
+	require.NoError(t, os.MkdirAll(childDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(childDir, "boilerplate.yml"), []byte(`variables:
+  - name: net_db_password
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(childDir, "password.txt"), []byte("{{ .net_db_password }}"), 0644))
+
+	outputsFile := filepath.Join(t.TempDir(), "outputs.json")
+	require.NoError(t, os.WriteFile(outputsFile, []byte(`{
+  "vpc_id": {"sensitive": false, "type": "string", "value": "vpc-123"},
+  "db_password": {"sensitive": true, "type": "string", "value": "hunter2"}
+}`), 0644))
+
+	vars, sensitiveVars, err := variables.ParseVarsWithSensitive(nil, []string{"tfoutput:net_=" + outputsFile})
+	require.NoError(t, err)
+
+	opts := &options.BoilerplateOptions{
+		TemplateFolder:  templateDir,
+		OutputFolder:    outputDir,
+		NonInteractive:  true,
+		OnMissingKey:    options.ExitWithError,
+		OnMissingConfig: options.Exit,
+		Vars:            vars,
+		SensitiveVars:   sensitiveVars,
+	}
+
+	result, err := ProcessTemplateWithContext(t.Context(), logging.Discard(), opts, opts, &variables.Dependency{})
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "child", "password.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", string(content))
+
+	assert.Equal(t, "vpc-123", result.Variables["net_vpc_id"])
+	assert.Equal(t, variables.SensitiveValuePlaceholder, result.Variables["net_db_password"])
+	require.Len(t, result.Dependencies, 1)
+	assert.Equal(t, variables.SensitiveValuePlaceholder, result.Dependencies[0].Variables["net_db_password"])
+}
+
+func TestProcessTemplateAppliesNestedOverrides(t *testing.T) {
+	t.Parallel()
+
+	// Keep the templates side by side, so the dependencies are not also processed as part of their parent's files
+	templatesDir := t.TempDir()
+	templateDir := filepath.Join(templatesDir, "root")
+	outputDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(templateDir, 0755))
+
+	require.NoError(t, os.WriteFile(filepath.Join(templateDir, "boilerplate.yml"), []byte(`variables:
+  - name: Tags
+    type: map
+    default:
+      env: dev
+      team: core
+dependencies:
+  - name: dep1
+    template-url: ../dep1
+    output-folder: dep1
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(templateDir, "tags.txt"), []byte("{{ .Tags.env }}-{{ .Tags.team }}"), 0644))
+
+	dep1Dir := filepath.Join(templatesDir, "dep1")
+	require.NoError(t, os.MkdirAll(dep1Dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dep1Dir, "boilerplate.yml"), []byte(`dependencies:
+  - name: dep2
+    template-url: ../dep2
+    output-folder: dep2
+`), 0644))
+
+	dep2Dir := filepath.Join(templatesDir, "dep2")
+	require.NoError(t, os.MkdirAll(dep2Dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dep2Dir, "boilerplate.yml"), []byte(`variables:
+  - name: Config
+    type: object
+    fields:
+      - name: host
+        type: string
+      - name: port
+        type: int
+    default:
+      host: localhost
+      port: 80
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dep2Dir, "config.txt"), []byte("{{ .Config.host }}:{{ .Config.port }}"), 0644))
+
+	overrides, err := variables.ParseVariableOverrides([]string{"Tags.env=prod", "dep1.dep2.Config.port=8080"})
+	require.NoError(t, err)
+
+	opts := &options.BoilerplateOptions{
+		TemplateFolder:  templateDir,
+		OutputFolder:    outputDir,
+		NonInteractive:  true,
+		OnMissingKey:    options.ExitWithError,
+		OnMissingConfig: options.Exit,
+		Vars:            map[string]any{},
+		Overrides:       overrides,
+	}
+
+	_, err = ProcessTemplateWithContext(t.Context(), logging.Discard(), opts, opts, &variables.Dependency{})
+	require.NoError(t, err)
+
+	tags, err := os.ReadFile(filepath.Join(outputDir, "tags.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "prod-core", string(tags))
+
+	config, err := os.ReadFile(filepath.Join(outputDir, "dep1", "dep2", "config.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "localhost:8080", string(config))
+}
+
+func TestProcessTemplateReusesPreviousAnswers(t *testing.T) {
+	t.Parallel()
+
+	// Keep the templates side by side, so the dependency is not also processed as part of its parent's files
+	templatesDir := t.TempDir()
+	templateDir := filepath.Join(templatesDir, "root")
+	require.NoError(t, os.MkdirAll(templateDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(templateDir, "boilerplate.yml"), []byte(`variables:
+  - name: Name
+  - name: Port
+    type: int
+    default: 8080
+  - name: Token
+    sensitive: true
+    default: default-token
+dependencies:
+  - name: backend
+    template-url: ../backend
+    output-folder: backend
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(templateDir, "app.txt"), []byte("{{ .Name }}:{{ .Port }}:{{ .Token }}"), 0644))
+
+	backendDir := filepath.Join(templatesDir, "backend")
+	require.NoError(t, os.MkdirAll(backendDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(backendDir, "boilerplate.yml"), []byte(`variables:
+  - name: Owner
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(backendDir, "owner.txt"), []byte("{{ .Name }}:{{ .Owner }}"), 0644))
+
+	firstOutputDir := t.TempDir()
+	firstOpts := &options.BoilerplateOptions{
+		TemplateFolder:  templateDir,
+		OutputFolder:    firstOutputDir,
+		NonInteractive:  true,
+		OnMissingKey:    options.ExitWithError,
+		OnMissingConfig: options.Exit,
+		Vars:            map[string]any{"Name": "web", "Port": 9090, "Token": "secret", "backend.Owner": "team-a"},
+	}
+
+	result, err := ProcessTemplateWithContext(t.Context(), logging.Discard(), firstOpts, firstOpts, &variables.Dependency{})
+	require.NoError(t, err)
+
+	previousManifest := manifest.NewManifest(templateDir, firstOutputDir, result.SourceChecksum, nil, result.Variables, result.Dependencies)
+
+	// Run again without any vars: the answers of the first run take the place of the defaults, except for the
+	// sensitive ones, which are redacted in the manifest
+	secondOutputDir := t.TempDir()
+	secondOpts := &options.BoilerplateOptions{
+		TemplateFolder:  templateDir,
+		OutputFolder:    secondOutputDir,
+		NonInteractive:  true,
+		OnMissingKey:    options.ExitWithError,
+		OnMissingConfig: options.Exit,
+		Vars:            map[string]any{"Port": 7070},
+		PreviousAnswers: manifest.PreviousAnswers(previousManifest),
+	}
+
+	_, err = ProcessTemplateWithContext(t.Context(), logging.Discard(), secondOpts, secondOpts, &variables.Dependency{})
+	require.NoError(t, err)
+
+	app, err := os.ReadFile(filepath.Join(secondOutputDir, "app.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "web:7070:default-token", string(app))
+
+	owner, err := os.ReadFile(filepath.Join(secondOutputDir, "backend", "owner.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "web:team-a", string(owner))
+}
+
+func TestProcessTemplateReplaysRecordedDecisions(t *testing.T) {
+	t.Parallel()
+
+	templatesDir := t.TempDir()
+
+	files := map[string]string{
+		"root/boilerplate.yml": `dependencies:
+  - name: backend
+    template-url: ../backend
+    output-folder: backend
+  - name: frontend
+    template-url: ../frontend
+    output-folder: frontend
+`,
+		"backend/boilerplate.yml": `dependencies:
+  - name: database
+    template-url: ../database
+    output-folder: database
+`,
+		"frontend/boilerplate.yml": "",
+		"database/boilerplate.yml": "",
+		// The shell helper checks that dependencies get answers for shell commands they can add to
+		"backend/backend.txt":   `{{ shell "echo" "-n" "backend" }}`,
+		"frontend/frontend.txt": "frontend",
+		"database/database.txt": "database",
+	}
+
+	testutil.WriteFiles(t, templatesDir, files)
+
+	outputDir := t.TempDir()
+	opts := &options.BoilerplateOptions{
+		TemplateFolder:      filepath.Join(templatesDir, "root"),
+		OutputFolder:        outputDir,
+		NonInteractive:      true,
+		OnMissingKey:        options.ExitWithError,
+		OnMissingConfig:     options.Exit,
+		ShellCommandAnswers: make(map[string]bool),
+		DependencyAnswers:   map[string]bool{"frontend": false, "backend.database": false},
+	}
+
+	_, err := ProcessTemplateWithContext(t.Context(), logging.Discard(), opts, opts, &variables.Dependency{})
+	require.NoError(t, err)
+
+	backend, err := os.ReadFile(filepath.Join(outputDir, "backend", "backend.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "backend", string(backend))
+
+	assert.NoFileExists(t, filepath.Join(outputDir, "frontend", "frontend.txt"))
+	assert.NoFileExists(t, filepath.Join(outputDir, "backend", "database", "database.txt"))
+}
+
+func TestProcessTemplateWithScriptedPrompter(t *testing.T) {
+	t.Parallel()
+
+	templatesDir := t.TempDir()
+
+	files := map[string]string{
+		"root/boilerplate.yml": `variables:
+  - name: Greeting
+dependencies:
+  - name: backend
+    template-url: ../backend
+    output-folder: backend
+  - name: frontend
+    template-url: ../frontend
+    output-folder: frontend
+`,
+		"backend/boilerplate.yml":  "",
+		"frontend/boilerplate.yml": "",
+		"root/root.txt":            `{{ .Greeting }} {{ shell "echo" "-n" "world" }}`,
+		"backend/backend.txt":      "backend",
+		"frontend/frontend.txt":    "frontend",
+	}
+
+	testutil.WriteFiles(t, templatesDir, files)
+
+	outputDir := t.TempDir()
+	opts := &options.BoilerplateOptions{
+		TemplateFolder:      filepath.Join(templatesDir, "root"),
+		OutputFolder:        outputDir,
+		OnMissingKey:        options.ExitWithError,
+		OnMissingConfig:     options.Exit,
+		ShellCommandAnswers: make(map[string]bool),
+		Prompter: prompt.NewScriptedPrompter(map[string]any{
+			"Greeting":               "hello",
+			"backend":                true,
+			"frontend":               "no",
+			"Execute shell command?": "yes",
+		}),
+	}
+
+	_, err := ProcessTemplateWithContext(t.Context(), logging.Discard(), opts, opts, &variables.Dependency{})
+	require.NoError(t, err)
+
+	root, err := os.ReadFile(filepath.Join(outputDir, "root.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", string(root))
+
+	assert.FileExists(t, filepath.Join(outputDir, "backend", "backend.txt"))
+	assert.NoFileExists(t, filepath.Join(outputDir, "frontend", "frontend.txt"))
+}
+
+func TestProcessTemplateWithDefaultsFileVars(t *testing.T) {
+	t.Parallel()
+
+	templatesDir := t.TempDir()
+
+	files := map[string]string{
+		"root/boilerplate.yml": `variables:
+  - name: Owner
+dependencies:
+  - name: service
+    template-url: ../service
+    output-folder: service
+    variables:
+      - name: Region
+        default: eu-west-1
+`,
+		"service/boilerplate.yml": `variables:
+  - name: Owner
+  - name: Region
+  - name: Team
+`,
+		"service/service.txt": "{{ .Owner }} {{ .Region }} {{ .Team }}",
+	}
+
+	testutil.WriteFiles(t, templatesDir, files)
+
+	outputDir := t.TempDir()
+	opts := &options.BoilerplateOptions{
+		TemplateFolder:   filepath.Join(templatesDir, "root"),
+		OutputFolder:     outputDir,
+		OnMissingKey:     options.ExitWithError,
+		OnMissingConfig:  options.Exit,
+		DefaultsFileVars: map[string]any{"Owner": "platform", "Region": "us-east-1", "service.Team": "sre"},
+		// An empty answer takes the value from the defaults file, which is offered as the default, but the user is
+		// still prompted, so they can answer differently
+		Prompter: prompt.NewScriptedPrompter(map[string]any{
+			"Owner":   "",
+			"service": true,
+			"Team":    "devs",
+		}),
+	}
+
+	_, err := ProcessTemplateWithContext(t.Context(), logging.Discard(), opts, opts, &variables.Dependency{})
+	require.NoError(t, err)
+
+	// The default set in the dependency block takes precedence over the defaults file
+	service, err := os.ReadFile(filepath.Join(outputDir, "service", "service.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "platform eu-west-1 devs", string(service))
+}
+
+func TestIsTrustedTemplate(t *testing.T) {
+	t.Parallel()
+
+	trusted := []string{"git@github.com:acme/*", "github.com/acme/templates//modules/service?ref=v1.0.0"}
+
+	testCases := []struct {
+		templateURL string
+		expected    bool
+	}{
+		{"git@github.com:acme/templates.git//modules/service?ref=v1.0.0", true},
+		{"github.com/acme/templates//modules/service?ref=v1.0.0", true},
+		{"github.com/acme/templates//modules/service?ref=v2.0.0", false},
+		{"git@github.com:acme-evil/templates.git//modules/service", false},
+		{"https://example.com/git@github.com:acme/templates.git", false},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, isTrustedTemplate(testCase.templateURL, trusted), testCase.templateURL)
+	}
+
+	assert.True(t, isTrustedTemplate("github.com/anyone/anything", nil))
+}
+
+func TestProcessTemplateRejectsUntrustedDependency(t *testing.T) {
+	t.Parallel()
+
+	templateDir := t.TempDir()
+
+	boilerplateYml := `dependencies:
+  - name: untrusted
+    template-url: git::https://github.com/someone-else/templates.git//service?ref=v1.0.0
+    output-folder: service
+`
+	err := os.WriteFile(filepath.Join(templateDir, "boilerplate.yml"), []byte(boilerplateYml), 0644)
+	require.NoError(t, err)
+
+	opts := &options.BoilerplateOptions{
+		TemplateFolder:   templateDir,
+		OutputFolder:     t.TempDir(),
+		NonInteractive:   true,
+		OnMissingKey:     options.ExitWithError,
+		OnMissingConfig:  options.Exit,
+		TrustedTemplates: []string{"git::https://github.com/acme/*"},
+	}
+
+	err = ProcessTemplate(logging.Discard(), opts, opts, &variables.Dependency{})
+	require.ErrorAs(t, err, &UntrustedTemplate{})
+}
+
+func TestProcessTemplateDependencyOutputs(t *testing.T) {
+	t.Parallel()
+
+	templatesDir := t.TempDir()
+
+	files := map[string]string{
+		"network/boilerplate.yml": `variables:
+  - name: Name
+outputs:
+  vpc_id: "vpc-{{ .Name }}"
+`,
+		"network/network.txt": "network",
+		"database/boilerplate.yml": `variables:
+  - name: VpcId
+outputs:
+  endpoint: "db.{{ .VpcId }}.internal"
+`,
+		"database/database.txt": "vpc={{ .VpcId }}",
+		"service/boilerplate.yml": `outputs:
+  url: "https://{{ .__each__ }}.example.com"
+`,
+		"service/service.txt": "{{ .__each__ }}",
+		"root/boilerplate.yml": `variables:
+  - name: Name
+dependencies:
+  - name: database
+    template-url: ../database
+    output-folder: database
+    depends_on: [network]
+    variables:
+      - name: VpcId
+        default: "{{ .Deps.network.Outputs.vpc_id }}"
+  - name: network
+    template-url: ../network
+    output-folder: network
+  - name: service
+    template-url: ../service
+    output-folder: "{{ .__each__ }}"
+    for_each: [users, orders]
+  - name: legacy
+    template-url: ../service
+    output-folder: legacy
+    skip: "true"
+`,
+		"root/summary.txt": `{{ .Deps.database.Outputs.endpoint }}
+{{ range .Deps.service.Outputs }}{{ .url }} {{ end }}
+{{ .Deps.legacy.Skipped }}`,
+	}
+
+	testutil.WriteFiles(t, templatesDir, files)
+
+	outputDir := t.TempDir()
+	opts := testutil.CreateTestOptionsWithOutput(filepath.Join(templatesDir, "root"), outputDir)
+	opts.NonInteractive = true
+	opts.Vars = map[string]any{"Name": "main"}
+
+	result, err := ProcessTemplateWithContext(t.Context(), logging.Discard(), opts, opts, &variables.Dependency{})
+	require.NoError(t, err)
+
+	// The outputs of a dependency can be read by the dependencies that depend on it, and by the parent template
+	summary, err := os.ReadFile(filepath.Join(outputDir, "summary.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "db.vpc-main.internal\nhttps://users.example.com https://orders.example.com \ntrue", string(summary))
+
+	database, err := os.ReadFile(filepath.Join(outputDir, "database", "database.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "vpc=vpc-main", string(database))
+
+	// The outputs are not recorded as variables
+	assert.NotContains(t, result.Variables, depsVarName)
+}
+
+func TestProcessTemplateDependencyOutputsRequireDependsOn(t *testing.T) {
+	t.Parallel()
+
+	templatesDir := t.TempDir()
+
+	files := map[string]string{
+		"network/boilerplate.yml": `outputs:
+  vpc_id: vpc-main
+`,
+		"database/boilerplate.yml": `variables:
+  - name: VpcId
+`,
+		"root/boilerplate.yml": `dependencies:
+  - name: network
+    template-url: ../network
+    output-folder: network
+  - name: database
+    template-url: ../database
+    output-folder: database
+    variables:
+      - name: VpcId
+        default: "{{ .Deps.network.Outputs.vpc_id }}"
+`,
+	}
+
+	testutil.WriteFiles(t, templatesDir, files)
+
+	opts := testutil.CreateTestOptionsWithOutput(filepath.Join(templatesDir, "root"), t.TempDir())
+	opts.NonInteractive = true
+
+	// network is declared first, but database doesn't list it in depends_on, so its outputs can't be relied on
+	_, err := ProcessTemplateWithContext(t.Context(), logging.Discard(), opts, opts, &variables.Dependency{})
+	require.ErrorAs(t, err, &DependencyNotInDependsOn{})
+	assert.Contains(t, err.Error(), "Dependency 'database' reads .Deps.network, but 'network' is not in its depends_on")
+}
+
+func TestProcessTemplateForEachOverMapsAndObjects(t *testing.T) {
+	t.Parallel()
+
+	templatesDir := t.TempDir()
+
+	files := map[string]string{
+		"region/boilerplate.yml":  "",
+		"region/region.txt":       "{{ .__each__.key }}={{ .__each__.value }}",
+		"service/boilerplate.yml": "",
+		"service/service.txt":     "{{ .__each__.name }}:{{ .__each__.port }}",
+		"root/boilerplate.yml": `variables:
+  - name: Services
+    type: list
+dependencies:
+  - name: region
+    template-url: ../region
+    output-folder: "regions/{{ .__each__.key }}"
+    for_each:
+      us-west-2: 3
+      eu-west-1: 2
+  - name: service
+    template-url: ../service
+    output-folder: "services/{{ .__each__.name }}"
+    for_each_reference: Services
+`,
+	}
+
+	testutil.WriteFiles(t, templatesDir, files)
+
+	outputDir := t.TempDir()
+	opts := testutil.CreateTestOptionsWithOutput(filepath.Join(templatesDir, "root"), outputDir)
+	opts.NonInteractive = true
+	opts.Vars = map[string]any{"Services": []any{
+		map[string]any{"name": "users", "port": 8080},
+		map[string]any{"name": "orders", "port": 8081},
+	}}
+
+	result, err := ProcessTemplateWithContext(t.Context(), logging.Discard(), opts, opts, &variables.Dependency{})
+	require.NoError(t, err)
+
+	expected := map[string]string{
+		"regions/eu-west-1/region.txt": "eu-west-1=2",
+		"regions/us-west-2/region.txt": "us-west-2=3",
+		"services/users/service.txt":   "users:8080",
+		"services/orders/service.txt":  "orders:8081",
+	}
+
+	for path, contents := range expected {
+		actual, readErr := os.ReadFile(filepath.Join(outputDir, path))
+		require.NoError(t, readErr)
+		assert.Equal(t, contents, string(actual))
+	}
+
+	// The manifest records the structured item each iteration was processed for
+	require.Len(t, result.Dependencies, 4)
+	assert.Equal(t, []any{map[string]any{"key": "eu-west-1", "value": 2}}, result.Dependencies[0].ForEachValues)
+	assert.Equal(t, []any{map[string]any{"name": "orders", "port": 8081}}, result.Dependencies[3].ForEachValues)
+	assert.Nil(t, result.Dependencies[3].ForEach)
+}
+
+func TestProcessTemplateDownloadsRemoteSourceOnce(t *testing.T) {
+	t.Parallel()
+
+	_, repoDir, _ := createLockTemplates(t, "shared")
+
+	rootDir := t.TempDir()
+	rootConfig := `dependencies:
+  - name: svc
+    template-url: "git::file://` + repoDir + `?ref=main"
+    output-folder: "{{ .__each__ }}"
+    for_each: [a, b, c, d]
+`
+	require.NoError(t, os.WriteFile(filepath.Join(rootDir, "boilerplate.yml"), []byte(rootConfig), 0644))
+
+	outputDir := t.TempDir()
+	opts := testutil.CreateTestOptionsWithOutput(rootDir, outputDir)
+	opts.Parallelism = 4
+	opts.DownloadCache = getterhelper.NewDownloadCache()
+
+	defer opts.DownloadCache.Cleanup(logging.Discard())
+
+	_, err := ProcessTemplateWithContext(t.Context(), logging.Discard(), opts, opts, &variables.Dependency{})
+	require.NoError(t, err)
+
+	for _, item := range []string{"a", "b", "c", "d"} {
+		content, readErr := os.ReadFile(filepath.Join(outputDir, item, "service.txt"))
+		require.NoError(t, readErr)
+		assert.Equal(t, "shared", string(content))
+	}
+
+	assert.Equal(t, 1, opts.DownloadCache.Downloads(), "only the first iteration should download the template")
+}
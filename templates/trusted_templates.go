@@ -0,0 +1,45 @@
+package templates
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// isTrustedTemplate returns true if the given remote template URL matches one of the given trusted template patterns,
+// or if there are no patterns, in which case any template is trusted. A pattern matches the whole URL, and may contain
+// * wildcards that match any characters, including slashes, e.g. git@github.com:acme/*.
+func isTrustedTemplate(templateURL string, trustedTemplates []string) bool {
+	if len(trustedTemplates) == 0 {
+		return true
+	}
+
+	for _, pattern := range trustedTemplates {
+		if trustedTemplatePatternToRegex(pattern).MatchString(templateURL) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Convert the given trusted template pattern to a regex that matches the whole URL.
+func trustedTemplatePatternToRegex(pattern string) *regexp.Regexp {
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+
+	return regexp.MustCompile("^" + strings.Join(parts, ".*") + "$")
+}
+
+// Custom error types
+
+type UntrustedTemplate struct {
+	TemplateURL      string
+	TrustedTemplates []string
+}
+
+func (err UntrustedTemplate) Error() string {
+	return fmt.Sprintf("The template %s does not match any of the trusted templates in your defaults files: %s", err.TemplateURL, strings.Join(err.TrustedTemplates, ", "))
+}
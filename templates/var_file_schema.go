@@ -0,0 +1,306 @@
+package templates
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/gruntwork-io/boilerplate/validation"
+	"github.com/gruntwork-io/boilerplate/variables"
+)
+
+// varFileSchemaDialect is the version of JSON Schema that VarFileSchema produces
+const varFileSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Patterns for the validation rules that are implemented with regular expressions by ozzo-validation
+const (
+	alphaPattern        = `^[a-zA-Z]+$`
+	digitPattern        = `^[0-9]+$`
+	alphanumericPattern = `^[a-zA-Z0-9]+$`
+)
+
+// VarFileSchema returns a JSON Schema that describes a var file for the template and its dependencies, so editors can
+// autocomplete and check var files. Each variable is described by its type, options, default, and as many of its
+// validations as JSON Schema can express; the rest are listed in its description. Unknown keys are rejected, unless
+// the variables of some dependency could not be determined.
+func (declared *DeclaredVariables) VarFileSchema() map[string]any {
+	properties := map[string]any{}
+	complete := true
+
+	for _, section := range declared.varFileSections() {
+		if section.Dependency != nil && section.Dependency.Err != nil {
+			complete = false
+		}
+
+		for _, entry := range section.Entries {
+			schema := variableSchema(entry.Variable)
+			properties[entry.Key] = schema
+
+			if _, alreadyDeclared := properties[entry.Variable.Name()]; entry.Unnamespaced && !alreadyDeclared {
+				properties[entry.Variable.Name()] = schema
+			}
+
+			// The old names of renamed variables are still accepted, but are marked as deprecated
+			namespace := strings.TrimSuffix(entry.Key, entry.Variable.Name())
+			for _, alias := range entry.Variable.Aliases() {
+				aliasSchema := maps.Clone(schema)
+				aliasSchema["deprecated"] = true
+				description, _ := schema["description"].(string)
+				aliasSchema["description"] = strings.TrimSpace(fmt.Sprintf("Renamed to %s%s. %s", namespace, entry.Variable.Name(), description))
+
+				properties[namespace+alias] = aliasSchema
+
+				if _, alreadyDeclared := properties[alias]; entry.Unnamespaced && !alreadyDeclared {
+					properties[alias] = aliasSchema
+				}
+			}
+		}
+	}
+
+	return map[string]any{
+		"$schema":              varFileSchemaDialect,
+		"title":                "Var file for " + declared.TemplateURL,
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": !complete,
+	}
+}
+
+// Return the JSON Schema for the value of the given variable.
+func variableSchema(variable variables.Variable) map[string]any {
+	schema := typeSchema(variable)
+
+	// Validations on typed lists and maps apply to each element
+	validatedSchema := schema
+	validatedType := variable.Type()
+
+	if elementType := variable.Type().ElementType(); elementType != "" && !variable.Type().IsObject() {
+		if items, hasItems := schema["items"].(map[string]any); hasItems {
+			validatedSchema = items
+		} else if values, hasValues := schema["additionalProperties"].(map[string]any); hasValues {
+			validatedSchema = values
+		}
+
+		validatedType = elementType
+	}
+
+	unchecked := []string{}
+
+	for _, rule := range variable.Validations() {
+		if !addValidationToSchema(validatedSchema, validatedType, rule) {
+			unchecked = append(unchecked, rule.DescriptionText())
+		}
+	}
+
+	description := variable.Description()
+	if variable.Deprecated() != "" {
+		schema["deprecated"] = true
+		description = strings.TrimSpace("Deprecated: " + variable.Deprecated() + "\n\n" + description)
+	}
+
+	if len(unchecked) > 0 {
+		description = strings.TrimSpace(description + "\n\nValidations: " + strings.Join(unchecked, "; "))
+	}
+
+	if description != "" {
+		schema["description"] = description
+	}
+
+	if defaultValue := variable.Default(); defaultValue != nil && !variable.Sensitive() && !strings.Contains(fmt.Sprint(defaultValue), "{{") {
+		schema["default"] = defaultValue
+	}
+
+	return schema
+}
+
+// Return the JSON Schema for the type of the given variable, without its validations.
+func typeSchema(variable variables.Variable) map[string]any {
+	switch variable.Type() {
+	case variables.Enum:
+		return optionsSchema(variable)
+	case variables.ListOfEnums:
+		schema := map[string]any{"type": "array", "items": optionsSchema(variable), "uniqueItems": true}
+		if variable.MinSelections() > 0 {
+			schema["minItems"] = variable.MinSelections()
+		}
+
+		if variable.MaxSelections() > 0 {
+			schema["maxItems"] = variable.MaxSelections()
+		}
+
+		return schema
+	case variables.Object:
+		return objectSchema(variable.Fields())
+	case variables.ListOfObjects:
+		return map[string]any{"type": "array", "items": objectSchema(variable.Fields())}
+	default:
+		return schemaForType(variable.Type())
+	}
+}
+
+// Return the JSON Schema for one of the options of the given variable. Options that are rendered from an expression are
+// only known at run time, so they can be any string.
+func optionsSchema(variable variables.Variable) map[string]any {
+	if variable.OptionsExpression() != "" {
+		return map[string]any{"type": "string"}
+	}
+
+	return map[string]any{"enum": variable.Options()}
+}
+
+// Return the JSON Schema for a type that has no options or fields.
+func schemaForType(boilerplateType variables.BoilerplateType) map[string]any {
+	switch boilerplateType.BaseType() {
+	case variables.String:
+		return map[string]any{"type": "string"}
+	case variables.Int:
+		return map[string]any{"type": "integer"}
+	case variables.Float:
+		return map[string]any{"type": "number"}
+	case variables.Bool:
+		return map[string]any{"type": "boolean"}
+	case variables.List:
+		if elementType := boilerplateType.ElementType(); elementType != "" {
+			return map[string]any{"type": "array", "items": schemaForType(elementType)}
+		}
+
+		return map[string]any{"type": "array"}
+	case variables.Map:
+		if elementType := boilerplateType.ElementType(); elementType != "" {
+			return map[string]any{"type": "object", "additionalProperties": schemaForType(elementType)}
+		}
+
+		return map[string]any{"type": "object"}
+	default:
+		return map[string]any{}
+	}
+}
+
+// Return the JSON Schema for an object with the given fields. Fields that are left out take their default value, so
+// none of them are required, but keys that are not declared as fields are rejected.
+func objectSchema(fields []variables.Variable) map[string]any {
+	properties := map[string]any{}
+	for _, field := range fields {
+		properties[field.Name()] = variableSchema(field)
+	}
+
+	return map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+}
+
+// Add the given validation rule, which applies to a value of the given type, to the given schema. Returns false if the
+// rule can't be expressed in JSON Schema.
+func addValidationToSchema(schema map[string]any, boilerplateType variables.BoilerplateType, rule validation.CustomValidationRule) bool {
+	baseType := boilerplateType.BaseType()
+
+	switch rule.Name {
+	case "required":
+		return setSizeBounds(schema, baseType, 1, -1)
+	case "url":
+		schema["format"] = "uri"
+	case "email", "ipv4", "hostname":
+		schema["format"] = rule.Name
+	case "alpha":
+		addPattern(schema, alphaPattern)
+	case "digit":
+		addPattern(schema, digitPattern)
+	case "alphanumeric":
+		addPattern(schema, alphanumericPattern)
+	case "dns_label":
+		addPattern(schema, validation.DNSLabelPattern)
+	case "regex":
+		pattern, isString := rule.Args[0].(string)
+		if !isString {
+			return false
+		}
+
+		addPattern(schema, pattern)
+	case "min", "max", "range":
+		if baseType != variables.Int && baseType != variables.Float {
+			return false
+		}
+
+		switch rule.Name {
+		case "min":
+			schema["minimum"] = rule.Args[0]
+		case "max":
+			schema["maximum"] = rule.Args[0]
+		default:
+			schema["minimum"] = rule.Args[0]
+			schema["maximum"] = rule.Args[1]
+		}
+	case "oneOf":
+		if baseType != variables.String {
+			return false
+		}
+
+		schema["enum"] = rule.Args[0]
+	case "length":
+		minLength, minIsInt := rule.Args[0].(int)
+		maxLength, maxIsInt := rule.Args[1].(int)
+
+		if !minIsInt || !maxIsInt {
+			return false
+		}
+
+		return setSizeBounds(schema, baseType, minLength, maxLength)
+	case "each":
+		if rule.Element == nil {
+			return false
+		}
+
+		if baseType != variables.List {
+			return addValidationToSchema(schema, boilerplateType, *rule.Element)
+		}
+
+		items, hasItems := schema["items"].(map[string]any)
+		if !hasItems {
+			items = map[string]any{}
+			schema["items"] = items
+		}
+
+		return addValidationToSchema(items, boilerplateType.ElementType(), *rule.Element)
+	default:
+		return false
+	}
+
+	return true
+}
+
+// Set the minimum and, unless it is negative, the maximum size of a value of the given type: the length of a string,
+// the number of items in a list, or the number of keys in a map or object. Returns false for other types.
+func setSizeBounds(schema map[string]any, baseType variables.BoilerplateType, minSize int, maxSize int) bool {
+	var minKeyword, maxKeyword string
+
+	switch baseType {
+	case variables.String:
+		minKeyword, maxKeyword = "minLength", "maxLength"
+	case variables.List:
+		minKeyword, maxKeyword = "minItems", "maxItems"
+	case variables.Map, variables.Object:
+		minKeyword, maxKeyword = "minProperties", "maxProperties"
+	default:
+		return false
+	}
+
+	schema[minKeyword] = minSize
+	if maxSize >= 0 {
+		schema[maxKeyword] = maxSize
+	}
+
+	return true
+}
+
+// Add a pattern to the given schema. A schema can only have one pattern, so additional patterns go into allOf.
+func addPattern(schema map[string]any, pattern string) {
+	if _, hasPattern := schema["pattern"]; !hasPattern {
+		schema["pattern"] = pattern
+		return
+	}
+
+	allOf, _ := schema["allOf"].([]any)
+	schema["allOf"] = append(allOf, map[string]any{"pattern": pattern})
+}
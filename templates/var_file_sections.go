@@ -0,0 +1,93 @@
+package templates
+
+import (
+	"strings"
+
+	"github.com/gruntwork-io/boilerplate/variables"
+)
+
+// varFileSection contains the variables of a single template that can be set in a var file: either the root template
+// or one of its dependencies, in the order boilerplate processes them.
+type varFileSection struct {
+	// Dependency is nil for the root template
+	Dependency *DeclaredDependency
+	// Path is the name of the dependency, namespaced with the names of its parent dependencies, such as "dep1.dep2".
+	// It is empty for the root template.
+	Path    string
+	Entries []varFileEntry
+}
+
+// varFileEntry is a variable that can be set in a var file, along with the key to set it with.
+type varFileEntry struct {
+	Variable variables.Variable
+	Key      string
+	// Unnamespaced is true if the variable can also be set using just its name, as every dependency between it and the
+	// root template inherits variables.
+	Unnamespaced bool
+}
+
+// varFileSections returns the variables that can be set in a var file, grouped by the template that declares them.
+// Variables that a dependency inherits from a parent template are only listed for the parent, and computed variables
+// that can't be overridden are left out entirely.
+func (declared *DeclaredVariables) varFileSections() []varFileSection {
+	sections := []varFileSection{}
+	declared.appendVarFileSections(&sections, nil, "", map[string]bool{}, true)
+
+	return sections
+}
+
+func (declared *DeclaredVariables) appendVarFileSections(
+	sections *[]varFileSection,
+	dependency *DeclaredDependency,
+	path string,
+	inherited map[string]bool,
+	unnamespaced bool,
+) {
+	section := varFileSection{Dependency: dependency, Path: path}
+	inheritedByChildren := map[string]bool{}
+
+	for name := range inherited {
+		inheritedByChildren[name] = true
+	}
+
+	for _, variable := range declared.Variables {
+		inheritedByChildren[variable.Name()] = true
+
+		if inherited[variable.Name()] || (variable.Value() != nil && !variable.Overridable()) {
+			continue
+		}
+
+		key := variable.Name()
+		if path != "" {
+			key = path + "." + variable.Name()
+		}
+
+		section.Entries = append(section.Entries, varFileEntry{
+			Variable:     variable,
+			Key:          key,
+			Unnamespaced: unnamespaced && path != "",
+		})
+	}
+
+	*sections = append(*sections, section)
+
+	for i := range declared.Dependencies {
+		child := &declared.Dependencies[i]
+
+		childPath := strings.TrimPrefix(path+"."+child.Dependency.Name, ".")
+		childInherited := inheritedByChildren
+		childUnnamespaced := unnamespaced
+
+		if child.Dependency.DontInheritVariables {
+			childInherited = map[string]bool{}
+			childUnnamespaced = false
+		}
+
+		if child.Variables == nil {
+			*sections = append(*sections, varFileSection{Dependency: child, Path: childPath})
+			continue
+		}
+
+		child.Variables.appendVarFileSections(sections, child, childPath, childInherited, childUnnamespaced)
+	}
+}
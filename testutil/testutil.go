@@ -2,6 +2,12 @@
 package testutil
 
 import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
 	"github.com/gruntwork-io/boilerplate/options"
 )
 
@@ -43,3 +49,15 @@ func CreateTestOptionsForShell(nonInteractive bool, noShell bool) *options.Boile
 		ExecuteAllShellCommands: false,
 	}
 }
+
+// WriteFiles writes the given files, keyed by their slash-separated path relative to dir, creating their parent
+// folders as needed
+func WriteFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for relPath, contents := range files {
+		path := filepath.Join(dir, filepath.FromSlash(relPath))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0644))
+	}
+}
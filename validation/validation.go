@@ -6,12 +6,17 @@ package validation
 import (
 	"errors"
 	"fmt"
+	"math"
+	"net"
+	"reflect"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/go-ozzo/ozzo-validation/is"
+	"gopkg.in/yaml.v3"
 )
 
 var errInvalidRegexPattern = errors.New("pattern must be a quoted string (e.g. regex(\"pattern\") or regex(`pattern`))")
@@ -20,8 +25,16 @@ var errInvalidRegexPattern = errors.New("pattern must be a quoted string (e.g. r
 // validator and a human-readable description message.
 type CustomValidationRule struct {
 	Validator validation.Rule
+	Name      string // Name of the rule, e.g. "required", "regex" or "each".
 	Message   string
 	Args      []any // Original arguments for parameterized rules (e.g., regex pattern, length bounds).
+
+	// Element is the rule that an each() rule applies to every element of a list or map.
+	Element *CustomValidationRule
+
+	// Expression is the Go template expression of an expr() rule. Expression rules have no Validator, as they are
+	// evaluated against the values of all variables rather than against a single value.
+	Expression string
 }
 
 // CustomValidationRuleCollection is a slice of CustomValidationRule.
@@ -31,6 +44,10 @@ type CustomValidationRuleCollection []CustomValidationRule
 func (c CustomValidationRuleCollection) GetValidators() []validation.Rule {
 	validatorsToReturn := make([]validation.Rule, 0, len(c))
 	for _, rule := range c {
+		if rule.IsExpression() {
+			continue
+		}
+
 		validatorsToReturn = append(validatorsToReturn, rule.Validator)
 	}
 
@@ -42,6 +59,12 @@ func (c CustomValidationRule) DescriptionText() string {
 	return c.Message
 }
 
+// IsExpression returns true if this is an expr() rule, which must be evaluated against all variables instead of
+// through its Validator.
+func (c CustomValidationRule) IsExpression() bool {
+	return c.Expression != ""
+}
+
 // convertSingleValidationRule converts a single validation rule string into a CustomValidationRule.
 // Rule names are case-sensitive and must match exactly (e.g. "required", not "Required").
 func convertSingleValidationRule(rule string) (CustomValidationRule, error) {
@@ -50,44 +73,179 @@ func convertSingleValidationRule(rule string) (CustomValidationRule, error) {
 	switch {
 	case rule == "required":
 		return CustomValidationRule{
+			Name:      "required",
 			Validator: validation.Required,
 			Message:   "Must not be empty",
 		}, nil
 	case rule == "url":
 		return CustomValidationRule{
+			Name:      "url",
 			Validator: is.URL,
 			Message:   "Must be a valid URL",
 		}, nil
 	case rule == "email":
 		return CustomValidationRule{
+			Name:      "email",
 			Validator: is.Email,
 			Message:   "Must be a valid email address",
 		}, nil
 	case rule == "alpha":
 		return CustomValidationRule{
+			Name:      "alpha",
 			Validator: is.Alpha,
 			Message:   "Must contain English letters only",
 		}, nil
 	case rule == "digit":
 		return CustomValidationRule{
+			Name:      "digit",
 			Validator: is.Digit,
 			Message:   "Must contain digits only",
 		}, nil
 	case rule == "alphanumeric":
 		return CustomValidationRule{
+			Name:      "alphanumeric",
 			Validator: is.Alphanumeric,
 			Message:   "Can contain English letters and digits only",
 		}, nil
 	case rule == "countrycode2":
 		return CustomValidationRule{
+			Name:      "countrycode2",
 			Validator: is.CountryCode2,
 			Message:   "Must be a valid ISO3166 Alpha 2 Country code",
 		}, nil
 	case rule == "semver":
 		return CustomValidationRule{
+			Name:      "semver",
 			Validator: is.Semver,
 			Message:   "Must be a valid semantic version",
 		}, nil
+	case rule == "cidr":
+		return CustomValidationRule{
+			Name:      "cidr",
+			Validator: validation.NewStringRule(isCIDR, "must be a valid CIDR block"),
+			Message:   "Must be a valid CIDR block (e.g., 10.0.0.0/16)",
+		}, nil
+	case rule == "ipv4":
+		return CustomValidationRule{
+			Name:      "ipv4",
+			Validator: is.IPv4,
+			Message:   "Must be a valid IPv4 address",
+		}, nil
+	case rule == "hostname":
+		return CustomValidationRule{
+			Name:      "hostname",
+			Validator: is.DNSName,
+			Message:   "Must be a valid hostname",
+		}, nil
+	case rule == "dns_label":
+		return CustomValidationRule{
+			Name:      "dns_label",
+			Validator: validation.Match(dnsLabelRegex),
+			Message:   "Must be a valid DNS label: at most 63 lowercase letters, digits and hyphens, starting and ending with a letter or digit",
+		}, nil
+	case strings.HasPrefix(rule, "min(") && strings.HasSuffix(rule, ")"):
+		min, err := parseNumberArg(rule, "min(")
+		if err != nil {
+			return CustomValidationRule{}, err
+		}
+
+		return CustomValidationRule{
+			Name:      "min",
+			Validator: numberRule(min, math.Inf(1)),
+			Message:   "Must be at least " + formatNumber(min),
+			Args:      []any{min},
+		}, nil
+	case strings.HasPrefix(rule, "max(") && strings.HasSuffix(rule, ")"):
+		max, err := parseNumberArg(rule, "max(")
+		if err != nil {
+			return CustomValidationRule{}, err
+		}
+
+		return CustomValidationRule{
+			Name:      "max",
+			Validator: numberRule(math.Inf(-1), max),
+			Message:   "Must be at most " + formatNumber(max),
+			Args:      []any{max},
+		}, nil
+	case strings.HasPrefix(rule, "range(") && strings.HasSuffix(rule, ")"):
+		// rangeArgCount is the expected number of arguments for the range() validation rule.
+		const rangeArgCount = 2
+
+		inner := strings.TrimSuffix(strings.TrimPrefix(rule, "range("), ")")
+		parts := strings.SplitN(inner, ",", rangeArgCount)
+
+		if len(parts) != rangeArgCount {
+			return CustomValidationRule{}, fmt.Errorf("invalid range validation %q: expected range(min, max)", rule)
+		}
+
+		min, minErr := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+		if minErr != nil {
+			return CustomValidationRule{}, fmt.Errorf("invalid min in range validation %q: %w", rule, minErr)
+		}
+
+		max, maxErr := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		if maxErr != nil {
+			return CustomValidationRule{}, fmt.Errorf("invalid max in range validation %q: %w", rule, maxErr)
+		}
+
+		if min > max {
+			return CustomValidationRule{}, fmt.Errorf("invalid range validation %q: min must be less than max", rule)
+		}
+
+		return CustomValidationRule{
+			Name:      "range",
+			Validator: numberRule(min, max),
+			Message:   fmt.Sprintf("Must be between %s and %s", formatNumber(min), formatNumber(max)),
+			Args:      []any{min, max},
+		}, nil
+	case strings.HasPrefix(rule, "oneOf(") && strings.HasSuffix(rule, ")"):
+		allowed, err := parseOneOfArgs(rule)
+		if err != nil {
+			return CustomValidationRule{}, err
+		}
+
+		return CustomValidationRule{
+			Name:      "oneOf",
+			Validator: oneOfRule(allowed),
+			Message:   "Must be one of: " + strings.Join(allowed, ", "),
+			Args:      []any{allowed},
+		}, nil
+	case strings.HasPrefix(rule, "each(") && strings.HasSuffix(rule, ")"):
+		innerRule, err := convertSingleValidationRule(rule[len("each(") : len(rule)-1])
+		if err != nil {
+			return CustomValidationRule{}, fmt.Errorf("invalid each validation %q: %w", rule, err)
+		}
+
+		if innerRule.IsExpression() {
+			return CustomValidationRule{}, fmt.Errorf("invalid each validation %q: expr() rules can't be applied to each element", rule)
+		}
+
+		return CustomValidationRule{
+			Name:      "each",
+			Validator: eachRule(innerRule.Validator),
+			Element:   &innerRule,
+			Message:   "Each element: " + innerRule.Message,
+			Args:      innerRule.Args,
+		}, nil
+	case strings.HasPrefix(rule, "expr(") && strings.HasSuffix(rule, ")"):
+		quoted := rule[len("expr(") : len(rule)-1]
+
+		expression, err := unquoteRegexPattern(quoted)
+		if err != nil {
+			return CustomValidationRule{}, fmt.Errorf(
+				"invalid expr validation %q: the expression must be a quoted string (e.g. expr(\"{{ ... }}\") or expr(`{{ ... }}`))", rule)
+		}
+
+		if strings.TrimSpace(expression) == "" {
+			return CustomValidationRule{}, fmt.Errorf("invalid expr validation %q: the expression must not be empty", rule)
+		}
+
+		return CustomValidationRule{
+			Name:       "expr",
+			Message:    "Must satisfy: " + expression,
+			Args:       []any{expression},
+			Expression: expression,
+		}, nil
 	case strings.HasPrefix(rule, "length(") && strings.HasSuffix(rule, ")"):
 		// lengthArgCount is the expected number of arguments for the length() validation rule.
 		const lengthArgCount = 2
@@ -114,6 +272,7 @@ func convertSingleValidationRule(rule string) (CustomValidationRule, error) {
 		}
 
 		return CustomValidationRule{
+			Name:      "length",
 			Validator: validation.Length(min, max),
 			Message:   fmt.Sprintf("Must be between %d and %d characters long", min, max),
 			Args:      []any{min, max},
@@ -133,6 +292,7 @@ func convertSingleValidationRule(rule string) (CustomValidationRule, error) {
 		}
 
 		return CustomValidationRule{
+			Name:      "regex",
 			Validator: validation.Match(compiledRegex),
 			Message:   "Must match pattern: " + pattern,
 			Args:      []any{pattern},
@@ -142,6 +302,169 @@ func convertSingleValidationRule(rule string) (CustomValidationRule, error) {
 	}
 }
 
+// DNSLabelPattern matches a DNS label as defined in RFC 1123, restricted to lowercase letters.
+const DNSLabelPattern = `^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`
+
+var dnsLabelRegex = regexp.MustCompile(DNSLabelPattern)
+
+// isCIDR returns true if the given string is a valid IPv4 or IPv6 CIDR block.
+func isCIDR(value string) bool {
+	_, _, err := net.ParseCIDR(value)
+	return err == nil
+}
+
+// parseNumberArg parses the single numeric argument of a rule such as min(n) or max(n).
+func parseNumberArg(rule string, prefix string) (float64, error) {
+	inner := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(rule, prefix), ")"))
+
+	number, err := strconv.ParseFloat(inner, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s validation %q: %w", strings.TrimSuffix(prefix, "("), rule, err)
+	}
+
+	return number, nil
+}
+
+// formatNumber formats a rule argument without a trailing ".0" for whole numbers.
+func formatNumber(number float64) string {
+	return strconv.FormatFloat(number, 'f', -1, 64)
+}
+
+// numberRule returns a rule that checks that a number, or a string holding a number, is within the given bounds.
+// Like the other ozzo-validation rules, empty values are considered valid, so use "required" to reject those.
+func numberRule(min float64, max float64) validation.Rule {
+	return validation.By(func(value any) error {
+		var number float64
+
+		switch typed := value.(type) {
+		case nil:
+			return nil
+		case int:
+			number = float64(typed)
+		case int64:
+			number = float64(typed)
+		case float64:
+			number = typed
+		case string:
+			if typed == "" {
+				return nil
+			}
+
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(typed), 64)
+			if err != nil {
+				return errors.New("must be a number")
+			}
+
+			number = parsed
+		default:
+			return fmt.Errorf("must be a number, got %T", value)
+		}
+
+		if number < min || number > max {
+			return numberOutOfBounds(min, max)
+		}
+
+		return nil
+	})
+}
+
+// numberOutOfBounds returns the error for a number outside the given bounds, only mentioning the bounds that are set.
+func numberOutOfBounds(min float64, max float64) error {
+	switch {
+	case math.IsInf(max, 1):
+		return errors.New("must be at least " + formatNumber(min))
+	case math.IsInf(min, -1):
+		return errors.New("must be at most " + formatNumber(max))
+	default:
+		return fmt.Errorf("must be between %s and %s", formatNumber(min), formatNumber(max))
+	}
+}
+
+// parseOneOfArgs parses the comma-separated arguments of a oneOf(...) rule. Arguments can optionally be quoted with
+// double quotes or backticks, which is required for values that contain commas.
+func parseOneOfArgs(rule string) ([]string, error) {
+	inner := rule[len("oneOf(") : len(rule)-1]
+
+	var (
+		allowed []string
+		current strings.Builder
+		quote   rune
+	)
+
+	for _, char := range inner {
+		switch {
+		case quote != 0 && char == quote:
+			quote = 0
+		case quote != 0:
+			current.WriteRune(char)
+		case char == '"' || char == '`':
+			quote = char
+		case char == ',':
+			allowed = append(allowed, strings.TrimSpace(current.String()))
+			current.Reset()
+		default:
+			current.WriteRune(char)
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("invalid oneOf validation %q: unterminated quote", rule)
+	}
+
+	allowed = append(allowed, strings.TrimSpace(current.String()))
+
+	if slices.Contains(allowed, "") {
+		return nil, fmt.Errorf("invalid oneOf validation %q: expected oneOf(value1, value2, ...)", rule)
+	}
+
+	return allowed, nil
+}
+
+// oneOfRule returns a rule that checks that the string form of a value is one of the allowed values.
+func oneOfRule(allowed []string) validation.Rule {
+	return validation.By(func(value any) error {
+		if value == nil || value == "" {
+			return nil
+		}
+
+		if !slices.Contains(allowed, fmt.Sprintf("%v", value)) {
+			return errors.New("must be one of: " + strings.Join(allowed, ", "))
+		}
+
+		return nil
+	})
+}
+
+// eachRule returns a rule that applies the given rule to each element of a list. A string is parsed as a YAML or JSON
+// list, as that is what the user enters at an interactive prompt. Values that are not lists are checked as a single
+// element, which is the case for the elements of typed lists such as list(string), whose rules already apply to
+// each element.
+func eachRule(rule validation.Rule) validation.Rule {
+	return validation.By(func(value any) error {
+		elements := []any{value}
+
+		if asString, isString := value.(string); isString {
+			var parsed []any
+			if err := yaml.Unmarshal([]byte(asString), &parsed); err == nil && parsed != nil {
+				elements = parsed
+			}
+		} else if reflected := reflect.ValueOf(value); reflected.Kind() == reflect.Slice {
+			elements = make([]any, 0, reflected.Len())
+			for i := range reflected.Len() {
+				elements = append(elements, reflected.Index(i).Interface())
+			}
+		}
+
+		for i, element := range elements {
+			if err := validation.Validate(element, rule); err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+
+		return nil
+	})
+}
+
 // unquoteRegexPattern extracts a regex pattern from a quoted string. It accepts
 // double-quoted ("pattern") and backtick-quoted (`pattern`) strings. Unlike
 // strconv.Unquote, double-quoted strings are treated as nearly raw: only \"
@@ -314,10 +314,161 @@ func TestConvertSingleValidationRule_Length(t *testing.T) {
 	}
 }
 
+func TestConvertSingleValidationRule_ValueRules(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		ruleInput       string
+		expectedMessage string
+		validValues     []any
+		invalidValues   []any
+	}{
+		{
+			name:            "min",
+			ruleInput:       "min(1)",
+			expectedMessage: "Must be at least 1",
+			validValues:     []any{1, 2.5, "10", ""},
+			invalidValues:   []any{0, -1.5, "0", "abc"},
+		},
+		{
+			name:            "max",
+			ruleInput:       "max(2.5)",
+			expectedMessage: "Must be at most 2.5",
+			validValues:     []any{2, 2.5, "-3"},
+			invalidValues:   []any{3, "2.6"},
+		},
+		{
+			name:            "range",
+			ruleInput:       "range(1, 65535)",
+			expectedMessage: "Must be between 1 and 65535",
+			validValues:     []any{1, 443, "8080"},
+			invalidValues:   []any{0, 70000, "port"},
+		},
+		{
+			name:            "cidr",
+			ruleInput:       "cidr",
+			expectedMessage: "Must be a valid CIDR block (e.g., 10.0.0.0/16)",
+			validValues:     []any{"10.0.0.0/16", "2001:db8::/32"},
+			invalidValues:   []any{"10.0.0.0", "10.0.0.0/33", "vpc"},
+		},
+		{
+			name:            "ipv4",
+			ruleInput:       "ipv4",
+			expectedMessage: "Must be a valid IPv4 address",
+			validValues:     []any{"10.0.0.1"},
+			invalidValues:   []any{"10.0.0.256", "2001:db8::1"},
+		},
+		{
+			name:            "hostname",
+			ruleInput:       "hostname",
+			expectedMessage: "Must be a valid hostname",
+			validValues:     []any{"example.com", "api.internal"},
+			invalidValues:   []any{"not a host", "-bad.com"},
+		},
+		{
+			name:            "dns_label",
+			ruleInput:       "dns_label",
+			expectedMessage: "Must be a valid DNS label: at most 63 lowercase letters, digits and hyphens, starting and ending with a letter or digit",
+			validValues:     []any{"my-bucket-1"},
+			invalidValues:   []any{"My-Bucket", "-bucket", "bucket-", "a.b"},
+		},
+		{
+			name:            "oneOf",
+			ruleInput:       `oneOf(dev, stage, "prod, eu")`,
+			expectedMessage: "Must be one of: dev, stage, prod, eu",
+			validValues:     []any{"dev", "prod, eu", ""},
+			invalidValues:   []any{"prod", "eu"},
+		},
+		{
+			name:            "each",
+			ruleInput:       "each(cidr)",
+			expectedMessage: "Each element: Must be a valid CIDR block (e.g., 10.0.0.0/16)",
+			validValues:     []any{[]any{"10.0.0.0/24", "10.0.1.0/24"}, `["10.0.0.0/24"]`, "10.0.0.0/24"},
+			invalidValues:   []any{[]any{"10.0.0.0/24", "nope"}, `["nope"]`, "nope"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rule, err := normalizeAndConvert(tc.ruleInput)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedMessage, rule.Message)
+			assert.False(t, rule.IsExpression())
+
+			for _, val := range tc.validValues {
+				require.NoError(t, rule.Validator.Validate(val), "expected %v to pass", val)
+			}
+
+			for _, val := range tc.invalidValues {
+				assert.Error(t, rule.Validator.Validate(val), "expected %v to fail", val)
+			}
+		})
+	}
+}
+
+func TestNumberRuleErrors(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		ruleInput     string
+		value         any
+		expectedError string
+	}{
+		{ruleInput: "min(1)", value: 0, expectedError: "must be at least 1"},
+		{ruleInput: "max(2.5)", value: 3, expectedError: "must be at most 2.5"},
+		{ruleInput: "range(1, 65535)", value: 70000, expectedError: "must be between 1 and 65535"},
+	}
+
+	for _, tc := range testCases {
+		rule, err := normalizeAndConvert(tc.ruleInput)
+		require.NoError(t, err)
+		require.EqualError(t, rule.Validator.Validate(tc.value), tc.expectedError, tc.ruleInput)
+	}
+}
+
+func TestConvertSingleValidationRule_Expr(t *testing.T) {
+	t.Parallel()
+
+	rule, err := normalizeAndConvert(`expr("{{ cidrContains .VpcCidr .SubnetCidr }}")`)
+	require.NoError(t, err)
+	assert.True(t, rule.IsExpression())
+	assert.Nil(t, rule.Validator)
+	assert.Equal(t, "{{ cidrContains .VpcCidr .SubnetCidr }}", rule.Expression)
+	assert.Equal(t, "Must satisfy: {{ cidrContains .VpcCidr .SubnetCidr }}", rule.Message)
+
+	rule, err = normalizeAndConvert("expr(`{{ ne .Name \"admin\" }}`)")
+	require.NoError(t, err)
+	assert.Equal(t, `{{ ne .Name "admin" }}`, rule.Expression)
+
+	errorCases := map[string]string{
+		"unquoted expression": "expr({{ .Name }})",
+		"empty expression":    `expr("")`,
+		"expr inside each":    `each(expr("{{ true }}"))`,
+		"min without number":  "min(abc)",
+		"range min over max":  "range(10, 1)",
+		"range missing max":   "range(10)",
+		"oneOf empty value":   "oneOf(a, , b)",
+		"oneOf open quote":    `oneOf("a, b)`,
+		"each unknown rule":   "each(nope)",
+	}
+
+	for name, ruleInput := range errorCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := normalizeAndConvert(ruleInput)
+			require.Error(t, err)
+		})
+	}
+}
+
 func TestConvertSingleValidationRule_SimpleRulesHaveNilArgs(t *testing.T) {
 	t.Parallel()
 
-	simpleRules := []string{"required", "url", "email", "alpha", "digit", "alphanumeric", "countrycode2", "semver"}
+	simpleRules := []string{"required", "url", "email", "alpha", "digit", "alphanumeric", "countrycode2", "semver", "cidr", "ipv4", "hostname", "dns_label"}
 
 	for _, ruleStr := range simpleRules {
 		t.Run(ruleStr, func(t *testing.T) {
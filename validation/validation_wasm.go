@@ -8,8 +8,15 @@ package validation
 // transitive crypto dependencies) into the WASM binary.
 type CustomValidationRule struct {
 	Validator any
+	Name      string // Name of the rule, e.g. "required", "regex" or "each".
 	Message   string
 	Args      []any // Original arguments for parameterized rules (e.g., regex pattern, length bounds).
+
+	// Element is the rule that an each() rule applies to every element of a list or map.
+	Element *CustomValidationRule
+
+	// Expression is the Go template expression of an expr() rule.
+	Expression string
 }
 
 // CustomValidationRuleCollection is a slice of CustomValidationRule.
@@ -20,6 +27,11 @@ func (c CustomValidationRule) DescriptionText() string {
 	return c.Message
 }
 
+// IsExpression returns true if this is an expr() rule.
+func (c CustomValidationRule) IsExpression() bool {
+	return c.Expression != ""
+}
+
 // UnmarshalValidationsField is a stub for WASM builds. Validation is never
 // performed in the WASM environment. This is called from
 // UnmarshalVariableFromBoilerplateConfigYaml in variables.go, which compiles
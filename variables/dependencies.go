@@ -3,6 +3,8 @@ package variables
 
 import (
 	"fmt"
+	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/gruntwork-io/boilerplate/util"
@@ -10,14 +12,18 @@ import (
 
 // Dependency represents a single boilerplate template that this boilerplate.yml depends on being executed first
 type Dependency struct {
-	Name                 string
-	TemplateURL          string
-	OutputFolder         string
-	Skip                 string
-	ForEachReference     string
-	Variables            []Variable
-	VarFiles             []string
-	ForEach              []string
+	Name             string
+	TemplateURL      string
+	OutputFolder     string
+	Skip             string
+	ForEachReference string
+	Variables        []Variable
+	VarFiles         []string
+	ForEach          []string
+	// ForEachValues are the items of for_each when they are not all strings, such as objects or the {key, value}
+	// entries of a map. At most one of ForEach and ForEachValues is set, see ForEachList.
+	ForEachValues        []any
+	DependsOn            []string
 	DontInheritVariables bool
 }
 
@@ -28,6 +34,25 @@ func (d *Dependency) TemplateUrl() string {
 	return d.TemplateURL
 }
 
+// ForEachList returns the items the dependency iterates over with for_each, whether they are strings, recorded in
+// ForEach, or other values, recorded in ForEachValues.
+func (d *Dependency) ForEachList() []any {
+	if len(d.ForEachValues) > 0 {
+		return d.ForEachValues
+	}
+
+	if len(d.ForEach) == 0 {
+		return nil
+	}
+
+	items := make([]any, 0, len(d.ForEach))
+	for _, item := range d.ForEach {
+		items = append(items, item)
+	}
+
+	return items
+}
+
 // MarshalYAML implements the go-yaml marshaler interface so that the config can be marshaled into yaml. We use a custom marshaler
 // instead of defining the fields as tags so that we skip the attributes that are empty.
 func (d *Dependency) MarshalYAML() (any, error) {
@@ -73,10 +98,18 @@ func (d *Dependency) MarshalYAML() (any, error) {
 		depYml["for_each"] = d.ForEach
 	}
 
+	if len(d.ForEachValues) > 0 {
+		depYml["for_each"] = d.ForEachValues
+	}
+
 	if len(d.ForEachReference) > 0 {
 		depYml["for_each_reference"] = d.ForEachReference
 	}
 
+	if len(d.DependsOn) > 0 {
+		depYml["depends_on"] = d.DependsOn
+	}
+
 	return depYml, nil
 }
 
@@ -132,9 +165,72 @@ func UnmarshalDependenciesFromBoilerplateConfigYaml(fields map[string]any) ([]De
 		unmarshalledDependencies = append(unmarshalledDependencies, *dependency)
 	}
 
+	if _, err := DependencyOrder(unmarshalledDependencies); err != nil {
+		return unmarshalledDependencies, err
+	}
+
 	return unmarshalledDependencies, nil
 }
 
+// DependencyOrder returns the indices of the given dependencies in the order to process them in, which is the order
+// they are declared in, except that each dependency comes after the dependencies it depends_on. Returns an error if
+// a dependency depends on a dependency that doesn't exist, or if the dependencies form a cycle.
+func DependencyOrder(dependencies []Dependency) ([]int, error) {
+	indices := map[string]int{}
+	for i, dependency := range dependencies {
+		indices[dependency.Name] = i
+	}
+
+	for _, dependency := range dependencies {
+		for _, dependsOn := range dependency.DependsOn {
+			if _, exists := indices[dependsOn]; !exists {
+				return nil, UnknownDependsOn{DependencyName: dependency.Name, DependsOn: dependsOn}
+			}
+		}
+	}
+
+	order := make([]int, 0, len(dependencies))
+	visited := make([]bool, len(dependencies))
+	inProgress := make([]bool, len(dependencies))
+
+	// Depth-first, so each dependency is added after the dependencies it depends on, and otherwise in declaration order
+	var visit func(i int, path []string) error
+
+	visit = func(i int, path []string) error {
+		path = append(path, dependencies[i].Name)
+
+		if inProgress[i] {
+			return DependencyCycle(path[slices.Index(path, dependencies[i].Name):])
+		}
+
+		if visited[i] {
+			return nil
+		}
+
+		inProgress[i] = true
+
+		for _, dependsOn := range dependencies[i].DependsOn {
+			if err := visit(indices[dependsOn], path); err != nil {
+				return err
+			}
+		}
+
+		inProgress[i] = false
+		visited[i] = true
+		order = append(order, i)
+
+		return nil
+	}
+
+	for i := range dependencies {
+		if err := visit(i, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
+
 // UnmarshalDependencyFromBoilerplateConfigYaml given a map of key:value pairs read from a Boilerplate YAML config file of the format:
 //
 // name: <NAME>
@@ -183,11 +279,13 @@ func UnmarshalDependencyFromBoilerplateConfigYaml(fields map[string]any) (*Depen
 		return nil, err
 	}
 
-	forEach, err := UnmarshalListOfStrings(fields, "for_each")
+	forEachItems, err := unmarshalForEachField(fields, *name)
 	if err != nil {
 		return nil, err
 	}
 
+	forEach, forEachValues := SplitForEachItems(forEachItems)
+
 	forEachReferencePtr, err := UnmarshalString(fields, "for_each_reference", false)
 	if err != nil {
 		return nil, err
@@ -198,6 +296,11 @@ func UnmarshalDependencyFromBoilerplateConfigYaml(fields map[string]any) (*Depen
 		forEachReference = *forEachReferencePtr
 	}
 
+	dependsOn, err := UnmarshalListOfStrings(fields, "depends_on")
+	if err != nil {
+		return nil, err
+	}
+
 	return &Dependency{
 		Name:                 *name,
 		TemplateURL:          *templateURL,
@@ -207,10 +310,83 @@ func UnmarshalDependencyFromBoilerplateConfigYaml(fields map[string]any) (*Depen
 		Variables:            variables,
 		VarFiles:             varFiles,
 		ForEach:              forEach,
+		ForEachValues:        forEachValues,
 		ForEachReference:     forEachReference,
+		DependsOn:            dependsOn,
 	}, nil
 }
 
+// Given a map of key:value pairs read from a Boilerplate YAML config file of the format:
+//
+// for_each: <LIST_OR_MAP>
+//
+// This method unmarshals the value into the items to iterate over (see ForEachItems).
+func unmarshalForEachField(fields map[string]any, context string) ([]any, error) {
+	value, hasValue := fields["for_each"]
+	if !hasValue || value == nil {
+		return nil, nil
+	}
+
+	items, err := ForEachItems(value)
+	if err != nil {
+		return nil, InvalidTypeForField{FieldName: "for_each", ExpectedType: "list or map", ActualType: reflect.TypeOf(value), Context: context}
+	}
+
+	return items, nil
+}
+
+// ForEachItems returns the items a dependency with the given for_each value iterates over: the elements of a list,
+// which may be strings, objects or any other values, or, for a map, an object with the key and value of each entry,
+// sorted by key, so templates can use .__each__.key and .__each__.value.
+func ForEachItems(value any) ([]any, error) {
+	reflected := reflect.ValueOf(value)
+
+	switch reflected.Kind() {
+	case reflect.Slice, reflect.Array:
+		items := make([]any, 0, reflected.Len())
+		for i := range reflected.Len() {
+			items = append(items, reflected.Index(i).Interface())
+		}
+
+		return items, nil
+	case reflect.Map:
+		keys := reflected.MapKeys()
+		slices.SortFunc(keys, func(a, b reflect.Value) int {
+			return strings.Compare(fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+		})
+
+		items := make([]any, 0, len(keys))
+		for _, key := range keys {
+			items = append(items, map[string]any{"key": key.Interface(), "value": reflected.MapIndex(key).Interface()})
+		}
+
+		return items, nil
+	default:
+		return nil, InvalidForEachValue{ActualType: reflect.TypeOf(value)}
+	}
+}
+
+// SplitForEachItems returns the given for_each items as a list of strings, if they are all strings, or as they are
+// otherwise, so they can be recorded in the ForEach or ForEachValues field of a Dependency or manifest entry.
+func SplitForEachItems(items []any) ([]string, []any) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	strs := make([]string, 0, len(items))
+
+	for _, item := range items {
+		str, isString := item.(string)
+		if !isString {
+			return nil, items
+		}
+
+		strs = append(strs, str)
+	}
+
+	return strs, nil
+}
+
 // Custom error types
 
 type DuplicateDependencyName string
@@ -218,3 +394,26 @@ type DuplicateDependencyName string
 func (name DuplicateDependencyName) Error() string {
 	return fmt.Sprintf("Found a duplicate dependency name: %s. All dependency names must be unique!", string(name))
 }
+
+type UnknownDependsOn struct {
+	DependencyName string
+	DependsOn      string
+}
+
+func (err UnknownDependsOn) Error() string {
+	return fmt.Sprintf("Dependency '%s' depends on '%s', but there is no dependency with that name", err.DependencyName, err.DependsOn)
+}
+
+type DependencyCycle []string
+
+func (cycle DependencyCycle) Error() string {
+	return fmt.Sprintf("The depends_on of the dependencies form a cycle: %s", strings.Join(cycle, " -> "))
+}
+
+type InvalidForEachValue struct {
+	ActualType reflect.Type
+}
+
+func (err InvalidForEachValue) Error() string {
+	return fmt.Sprintf("Can only iterate over a list or a map with for_each, but got a value of type %s", err.ActualType)
+}
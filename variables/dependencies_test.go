@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/gruntwork-io/boilerplate/variables"
 )
@@ -29,3 +30,47 @@ func TestSplitIntoDependencyNameAndVariableName(t *testing.T) {
 		assert.Equal(t, testCase.expectedOriginalVariableName, actualOriginalVariableName, "Variable name: %s", testCase.variableName)
 	}
 }
+
+func TestDependencyOrder(t *testing.T) {
+	t.Parallel()
+
+	dependencies := []variables.Dependency{
+		{Name: "app", DependsOn: []string{"database", "network"}},
+		{Name: "database", DependsOn: []string{"network"}},
+		{Name: "monitoring"},
+		{Name: "network"},
+	}
+
+	order, err := variables.DependencyOrder(dependencies)
+	require.NoError(t, err)
+	assert.Equal(t, []int{3, 1, 0, 2}, order)
+
+	dependencies[3].DependsOn = []string{"app"}
+
+	_, err = variables.DependencyOrder(dependencies)
+	require.ErrorAs(t, err, &variables.DependencyCycle{})
+	assert.EqualError(t, err, "The depends_on of the dependencies form a cycle: app -> database -> network -> app")
+
+	dependencies[3].DependsOn = []string{"vpc"}
+
+	_, err = variables.DependencyOrder(dependencies)
+	require.ErrorAs(t, err, &variables.UnknownDependsOn{})
+}
+
+func TestForEachItems(t *testing.T) {
+	t.Parallel()
+
+	items, err := variables.ForEachItems([]any{"dev", map[string]any{"name": "prod"}})
+	require.NoError(t, err)
+	assert.Equal(t, []any{"dev", map[string]any{"name": "prod"}}, items)
+
+	items, err = variables.ForEachItems(map[string]any{"us-west-2": 3, "eu-west-1": 2})
+	require.NoError(t, err)
+	assert.Equal(t, []any{
+		map[string]any{"key": "eu-west-1", "value": 2},
+		map[string]any{"key": "us-west-2", "value": 3},
+	}, items)
+
+	_, err = variables.ForEachItems("dev")
+	require.ErrorAs(t, err, &variables.InvalidForEachValue{})
+}
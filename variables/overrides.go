@@ -0,0 +1,132 @@
+package variables
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// VariableOverride is a value passed in via --set that overrides a single, possibly nested, key of a variable. Path is
+// the dotted path to the key, which may start with the names of dependencies, such as "Tags.env" or
+// "dep1.dep2.Config.port".
+type VariableOverride struct {
+	Value any
+	Path  string
+}
+
+// ParseVariableOverrides parses a list of PATH=VALUE pairs passed in via --set into a list of overrides, in the order
+// they were passed in. Along the way, each VALUE is parsed as YAML.
+func ParseVariableOverrides(setList []string) ([]VariableOverride, error) {
+	overrides := []VariableOverride{}
+
+	for _, set := range setList {
+		path, value, found := strings.Cut(set, "=")
+		if !found {
+			return nil, InvalidVarSyntax(set)
+		}
+
+		if path == "" {
+			return nil, VariableNameCannotBeEmpty(set)
+		}
+
+		if slices.Contains(strings.Split(path, "."), "") {
+			return nil, InvalidOverridePath(path)
+		}
+
+		parsedValue, err := ParseYamlString(value)
+		if err != nil {
+			return nil, err
+		}
+
+		overrides = append(overrides, VariableOverride{Path: path, Value: parsedValue})
+	}
+
+	return overrides, nil
+}
+
+// OverridesForVariable returns the overrides in the given list that target the variable with the given name, with
+// their paths made relative to the variable. An override of the whole variable has an empty path.
+func OverridesForVariable(overrides []VariableOverride, variableName string) []VariableOverride {
+	var forVariable []VariableOverride
+
+	for _, override := range overrides {
+		switch {
+		case override.Path == variableName:
+			forVariable = append(forVariable, VariableOverride{Value: override.Value})
+		case strings.HasPrefix(override.Path, variableName+"."):
+			forVariable = append(forVariable, VariableOverride{Value: override.Value, Path: strings.TrimPrefix(override.Path, variableName+".")})
+		}
+	}
+
+	return forVariable
+}
+
+// InheritedOverrides returns the overrides in the given list that this dependency should get. This follows the same
+// rules as variables passed in via --var: overrides namespaced with the name of the dependency have that namespace
+// removed, and all other overrides are passed through unchanged, unless the dependency does not inherit variables.
+func (d *Dependency) InheritedOverrides(overrides []VariableOverride) []VariableOverride {
+	var inherited []VariableOverride
+
+	for _, override := range overrides {
+		dependencyName, path := SplitIntoDependencyNameAndVariableName(override.Path)
+
+		switch {
+		case dependencyName == d.Name:
+			inherited = append(inherited, VariableOverride{Value: override.Value, Path: path})
+		case !d.DontInheritVariables:
+			inherited = append(inherited, override)
+		}
+	}
+
+	return inherited
+}
+
+// ApplyVariableOverrides deep-merges the given overrides, whose paths are relative to the variable with the given name
+// (see OverridesForVariable), into the given value of that variable in order, and returns the result. The given value is
+// not modified. Maps are created along the path as needed.
+func ApplyVariableOverrides(variableName string, value any, overrides []VariableOverride) (any, error) {
+	for _, override := range overrides {
+		if override.Path == "" {
+			value = override.Value
+			continue
+		}
+
+		updated, err := setNestedValue(value, strings.Split(override.Path, "."), override.Value)
+		if err != nil {
+			return nil, InvalidOverride{Err: err, Path: variableName + "." + override.Path}
+		}
+
+		value = updated
+	}
+
+	return value, nil
+}
+
+// Return a copy of the given value with the key at the given path set to newValue. Only the maps along the path are
+// copied.
+func setNestedValue(value any, path []string, newValue any) (any, error) {
+	var asMap map[string]any
+
+	switch typedValue := value.(type) {
+	case nil:
+		asMap = map[string]any{}
+	case map[string]any:
+		asMap = maps.Clone(typedValue)
+	default:
+		return nil, OverrideTargetNotMap{Key: path[0], Value: value}
+	}
+
+	if len(path) == 1 {
+		asMap[path[0]] = newValue
+		return asMap, nil
+	}
+
+	nested, err := setNestedValue(asMap[path[0]], path[1:], newValue)
+	if err != nil {
+		return nil, err
+	}
+
+	asMap[path[0]] = nested
+
+	return asMap, nil
+}
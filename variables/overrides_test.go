@@ -0,0 +1,83 @@
+package variables //nolint:testpackage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVariableOverrides(t *testing.T) {
+	t.Parallel()
+
+	overrides, err := ParseVariableOverrides([]string{"Tags.env=prod", "dep1.dep2.Config.port=8080", "Name=web"})
+	require.NoError(t, err)
+	assert.Equal(t, []VariableOverride{
+		{Path: "Tags.env", Value: "prod"},
+		{Path: "dep1.dep2.Config.port", Value: 8080},
+		{Path: "Name", Value: "web"},
+	}, overrides)
+
+	_, err = ParseVariableOverrides([]string{"Tags.env"})
+	require.ErrorIs(t, err, InvalidVarSyntax("Tags.env"))
+
+	_, err = ParseVariableOverrides([]string{"=prod"})
+	require.ErrorIs(t, err, VariableNameCannotBeEmpty("=prod"))
+
+	_, err = ParseVariableOverrides([]string{"Tags..env=prod"})
+	require.ErrorIs(t, err, InvalidOverridePath("Tags..env"))
+}
+
+func TestApplyVariableOverrides(t *testing.T) {
+	t.Parallel()
+
+	original := map[string]any{"env": "dev", "team": "core", "nested": map[string]any{"a": 1}}
+
+	overrides := OverridesForVariable([]VariableOverride{
+		{Path: "Tags.env", Value: "prod"},
+		{Path: "Tags.nested.b", Value: 2},
+		{Path: "Tags.new.key", Value: true},
+		{Path: "Other.env", Value: "ignored"},
+	}, "Tags")
+
+	actual, err := ApplyVariableOverrides("Tags", original, overrides)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"env":    "prod",
+		"team":   "core",
+		"nested": map[string]any{"a": 1, "b": 2},
+		"new":    map[string]any{"key": true},
+	}, actual)
+
+	// The original value is left untouched
+	assert.Equal(t, map[string]any{"env": "dev", "team": "core", "nested": map[string]any{"a": 1}}, original)
+
+	// Overriding the whole variable replaces the value, and later overrides are merged on top of it
+	actual, err = ApplyVariableOverrides("Tags", original, []VariableOverride{{Value: map[string]any{"x": "y"}}, {Path: "env", Value: "prod"}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"x": "y", "env": "prod"}, actual)
+
+	_, err = ApplyVariableOverrides("Tags", original, []VariableOverride{{Path: "env.name", Value: "prod"}})
+	require.ErrorAs(t, err, &InvalidOverride{})
+	assert.Contains(t, err.Error(), "Tags.env.name")
+}
+
+func TestDependencyInheritedOverrides(t *testing.T) {
+	t.Parallel()
+
+	overrides := []VariableOverride{
+		{Path: "Tags.env", Value: "prod"},
+		{Path: "dep1.dep2.Config.port", Value: 8080},
+		{Path: "other.Name", Value: "web"},
+	}
+
+	assert.Equal(t, []VariableOverride{
+		{Path: "Tags.env", Value: "prod"},
+		{Path: "dep2.Config.port", Value: 8080},
+		{Path: "other.Name", Value: "web"},
+	}, (&Dependency{Name: "dep1"}).InheritedOverrides(overrides))
+
+	assert.Equal(t, []VariableOverride{
+		{Path: "dep2.Config.port", Value: 8080},
+	}, (&Dependency{Name: "dep1", DontInheritVariables: true}).InheritedOverrides(overrides))
+}
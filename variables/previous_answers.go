@@ -0,0 +1,109 @@
+package variables
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// PreviousAnswers contains the values of the variables of a template, and of each of its dependencies, that were
+// recorded in the manifest of a previous run. With --reuse-answers, these take the place of the defaults, so
+// re-running a template does not mean answering every prompt again.
+type PreviousAnswers struct {
+	Variables    map[string]any
+	Dependencies []PreviousDependencyAnswers
+}
+
+// PreviousDependencyAnswers contains the previous answers for a single dependency. A dependency with for_each has one
+// of these for each item, which is recorded in ForEach.
+type PreviousDependencyAnswers struct {
+	Answers *PreviousAnswers
+	Name    string
+	ForEach []any
+}
+
+// Lookup returns the previous answer for the variable with the given name. The values of sensitive variables are
+// redacted in the manifest, so they are never returned. This is safe to call on a nil PreviousAnswers.
+func (answers *PreviousAnswers) Lookup(name string) (any, bool) {
+	if answers == nil {
+		return nil, false
+	}
+
+	value, hasValue := answers.Variables[name]
+	if !hasValue || value == nil || value == SensitiveValuePlaceholder {
+		return nil, false
+	}
+
+	return value, true
+}
+
+// ForDependency returns the previous answers for the dependency with the given name or nil if there are none. For a
+// dependency with for_each, forEachItem is the item being processed, and only answers recorded for that item are
+// returned; otherwise it is nil or empty. This is safe to call on a nil PreviousAnswers.
+func (answers *PreviousAnswers) ForDependency(name string, forEachItem any) *PreviousAnswers {
+	if answers == nil {
+		return nil
+	}
+
+	for _, dependency := range answers.Dependencies {
+		if dependency.Name != name {
+			continue
+		}
+
+		isRecordedItem := func(recorded any) bool { return forEachItemKey(recorded) == forEachItemKey(forEachItem) }
+
+		if forEachItem == nil || forEachItem == "" || slices.ContainsFunc(dependency.ForEach, isRecordedItem) {
+			return dependency.Answers
+		}
+	}
+
+	return nil
+}
+
+// Return a string that identifies the given for_each item. Items that are not strings are compared as JSON, as the
+// numbers in the items recorded in a manifest may be read back with a different type.
+func forEachItemKey(item any) string {
+	if asString, isString := item.(string); isString {
+		return asString
+	}
+
+	asJSON, err := json.Marshal(item)
+	if err != nil {
+		return fmt.Sprint(item)
+	}
+
+	return string(asJSON)
+}
+
+// WithPreviousAnswer returns a copy of the given variable whose default is the given answer from a previous run. The
+// given variable is not modified. Variables without a default had to be prompted for in the previous run, so the copy
+// is marked to confirm its default, which means the user is still prompted for it, with the previous answer offered
+// as the default.
+func WithPreviousAnswer(variable Variable, answer any) Variable {
+	original, isDefaultVariable := variable.(*defaultVariable)
+	if !isDefaultVariable {
+		return variable
+	}
+
+	copied := *original
+	copied.confirm = original.confirm || original.defaultValue == nil
+	copied.defaultValue = answer
+
+	return &copied
+}
+
+// WithFallbackDefault returns a copy of the given variable whose default is the given value, such as a value set in a
+// defaults file, if the variable doesn't declare a default of its own. The given variable is not modified. As with
+// WithPreviousAnswer, the copy is marked to confirm its default, so the user is still prompted for it.
+func WithFallbackDefault(variable Variable, value any) Variable {
+	original, isDefaultVariable := variable.(*defaultVariable)
+	if !isDefaultVariable || original.defaultValue != nil {
+		return variable
+	}
+
+	copied := *original
+	copied.confirm = true
+	copied.defaultValue = value
+
+	return &copied
+}
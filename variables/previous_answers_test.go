@@ -0,0 +1,85 @@
+package variables //nolint:testpackage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreviousAnswersLookup(t *testing.T) {
+	t.Parallel()
+
+	answers := &PreviousAnswers{Variables: map[string]any{"Name": "web", "Token": SensitiveValuePlaceholder}}
+
+	value, hasValue := answers.Lookup("Name")
+	assert.True(t, hasValue)
+	assert.Equal(t, "web", value)
+
+	_, hasValue = answers.Lookup("Token")
+	assert.False(t, hasValue, "redacted sensitive values should not be reused")
+
+	_, hasValue = answers.Lookup("Missing")
+	assert.False(t, hasValue)
+
+	var noAnswers *PreviousAnswers
+
+	_, hasValue = noAnswers.Lookup("Name")
+	assert.False(t, hasValue)
+	assert.Nil(t, noAnswers.ForDependency("backend", ""))
+}
+
+func TestPreviousAnswersForDependency(t *testing.T) {
+	t.Parallel()
+
+	backend := &PreviousAnswers{Variables: map[string]any{"Port": 8080}}
+	envDev := &PreviousAnswers{Variables: map[string]any{"Replicas": 1}}
+	envProd := &PreviousAnswers{Variables: map[string]any{"Replicas": 3}}
+
+	answers := &PreviousAnswers{
+		Dependencies: []PreviousDependencyAnswers{
+			{Name: "backend", Answers: backend},
+			{Name: "env", ForEach: []any{"dev"}, Answers: envDev},
+			{Name: "env", ForEach: []any{"prod"}, Answers: envProd},
+		},
+	}
+
+	assert.Same(t, backend, answers.ForDependency("backend", ""))
+	assert.Same(t, envProd, answers.ForDependency("env", "prod"))
+	assert.Same(t, envDev, answers.ForDependency("env", "dev"))
+	assert.Nil(t, answers.ForDependency("env", "staging"))
+	assert.Nil(t, answers.ForDependency("frontend", ""))
+}
+
+func TestPreviousAnswersForDependencyWithObjectItems(t *testing.T) {
+	t.Parallel()
+
+	regionA := &PreviousAnswers{Variables: map[string]any{"Replicas": 2}}
+
+	answers := &PreviousAnswers{
+		Dependencies: []PreviousDependencyAnswers{
+			{Name: "region", ForEach: []any{map[string]any{"key": "a", "value": float64(1)}}, Answers: regionA},
+		},
+	}
+
+	assert.Same(t, regionA, answers.ForDependency("region", map[string]any{"key": "a", "value": 1}))
+	assert.Nil(t, answers.ForDependency("region", map[string]any{"key": "b", "value": 1}))
+}
+
+func TestWithPreviousAnswer(t *testing.T) {
+	t.Parallel()
+
+	required := NewStringVariable("Name")
+	withAnswer := WithPreviousAnswer(required, "web")
+
+	assert.Equal(t, "web", withAnswer.Default())
+	assert.True(t, withAnswer.Confirm(), "variables that were prompted for should still be prompted for")
+	assert.Nil(t, required.Default(), "the original variable should not be modified")
+	assert.False(t, required.Confirm())
+
+	withDefault := NewIntVariable("Port").WithDefault(8080)
+	withAnswer = WithPreviousAnswer(withDefault, 9090)
+
+	assert.Equal(t, 9090, withAnswer.Default())
+	assert.False(t, withAnswer.Confirm())
+	assert.Equal(t, 8080, withDefault.Default())
+}
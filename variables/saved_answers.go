@@ -0,0 +1,229 @@
+package variables
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DecisionsVarName is the key under which a var file written by --save-answers records the answers to the yes/no
+// prompts for dependencies, hooks, and shell commands.
+const DecisionsVarName = "BoilerplateDecisions"
+
+// Decisions are the answers to the yes/no prompts of a run. Dependencies are keyed by their name, namespaced with the
+// names of their parent dependencies, such as "dep1.dep2". Hooks and shell commands are keyed by a hash of their
+// rendered command, arguments, environment and working directory.
+type Decisions struct {
+	Dependencies  map[string]bool `yaml:"dependencies,omitempty"`
+	Hooks         map[string]bool `yaml:"hooks,omitempty"`
+	ShellCommands map[string]bool `yaml:"shell_commands,omitempty"`
+}
+
+// ExtractDecisions removes the decisions recorded by --save-answers from the given variable values, if there are any,
+// and returns them. Returns nil if there are no decisions.
+func ExtractDecisions(vars map[string]any) (*Decisions, error) {
+	value, hasDecisions := vars[DecisionsVarName]
+	if !hasDecisions {
+		return nil, nil
+	}
+
+	delete(vars, DecisionsVarName)
+
+	// The decisions may have been read from any var file format, so convert them by round-tripping through YAML
+	asYAML, err := yaml.Marshal(value)
+	if err != nil {
+		return nil, InvalidDecisions{Err: err}
+	}
+
+	decisions := &Decisions{}
+	if err := yaml.Unmarshal(asYAML, decisions); err != nil {
+		return nil, InvalidDecisions{Err: err}
+	}
+
+	return decisions, nil
+}
+
+// AnswerRecorder records the answers the user gives at interactive prompts, so they can be written to a var file with
+// --save-answers and replayed with --var-file and --non-interactive. It is safe to use from multiple goroutines, and
+// all its methods are no-ops on a nil AnswerRecorder.
+type AnswerRecorder struct {
+	answers *recordedAnswers
+	// namespace is prepended to the names of variables and dependencies, so they are recorded with the namespaced
+	// names that pass them to the right dependency when replayed.
+	namespace string
+}
+
+type recordedAnswers struct {
+	variables map[string]any
+	decisions Decisions
+	sensitive []string
+	mutex     sync.Mutex
+}
+
+// NewAnswerRecorder creates a new AnswerRecorder for the root template.
+func NewAnswerRecorder() *AnswerRecorder {
+	return &AnswerRecorder{
+		answers: &recordedAnswers{
+			variables: map[string]any{},
+			decisions: Decisions{
+				Dependencies:  map[string]bool{},
+				Hooks:         map[string]bool{},
+				ShellCommands: map[string]bool{},
+			},
+		},
+	}
+}
+
+// ForDependency returns an AnswerRecorder that records the answers for the dependency with the given name.
+func (recorder *AnswerRecorder) ForDependency(dependencyName string) *AnswerRecorder {
+	if recorder == nil {
+		return nil
+	}
+
+	return &AnswerRecorder{answers: recorder.answers, namespace: recorder.namespace + dependencyName + "."}
+}
+
+// RecordVariable records the value the user entered for the given variable. The values of sensitive variables are
+// not recorded, so they don't end up in plain text in the var file; only their names are.
+func (recorder *AnswerRecorder) RecordVariable(variable Variable, value any) {
+	if recorder == nil {
+		return
+	}
+
+	name := recorder.namespace + variable.Name()
+
+	recorder.answers.mutex.Lock()
+	defer recorder.answers.mutex.Unlock()
+
+	if variable.Sensitive() {
+		if !slices.Contains(recorder.answers.sensitive, name) {
+			recorder.answers.sensitive = append(recorder.answers.sensitive, name)
+		}
+
+		return
+	}
+
+	// Prompts return strings, so record the value with its declared type to make the var file easier to read and edit
+	if converted, err := ConvertType(value, variable); err == nil {
+		value = converted
+	}
+
+	recorder.answers.variables[name] = value
+}
+
+// ForgetVariable removes the value recorded for the given variable, such as when the user goes back to an earlier
+// question, or when the variable no longer applies because its "when" condition is now false.
+func (recorder *AnswerRecorder) ForgetVariable(variable Variable) {
+	if recorder == nil {
+		return
+	}
+
+	name := recorder.namespace + variable.Name()
+
+	recorder.answers.mutex.Lock()
+	defer recorder.answers.mutex.Unlock()
+
+	delete(recorder.answers.variables, name)
+	recorder.answers.sensitive = slices.DeleteFunc(recorder.answers.sensitive, func(sensitiveName string) bool {
+		return sensitiveName == name
+	})
+}
+
+// RecordDependency records whether the user chose to process the dependency with the given name.
+func (recorder *AnswerRecorder) RecordDependency(dependencyName string, process bool) {
+	if recorder == nil {
+		return
+	}
+
+	recorder.answers.mutex.Lock()
+	defer recorder.answers.mutex.Unlock()
+
+	recorder.answers.decisions.Dependencies[recorder.namespace+dependencyName] = process
+}
+
+// RecordHook records whether the user chose to execute the hook with the given key.
+func (recorder *AnswerRecorder) RecordHook(hookKey string, execute bool) {
+	if recorder == nil {
+		return
+	}
+
+	recorder.answers.mutex.Lock()
+	defer recorder.answers.mutex.Unlock()
+
+	recorder.answers.decisions.Hooks[hookKey] = execute
+}
+
+// RecordShellCommand records whether the user chose to execute the shell command with the given key.
+func (recorder *AnswerRecorder) RecordShellCommand(shellKey string, execute bool) {
+	if recorder == nil {
+		return
+	}
+
+	recorder.answers.mutex.Lock()
+	defer recorder.answers.mutex.Unlock()
+
+	recorder.answers.decisions.ShellCommands[shellKey] = execute
+}
+
+// VarFile returns the recorded answers as a YAML var file. The decisions are recorded under DecisionsVarName, after
+// the variables.
+func (recorder *AnswerRecorder) VarFile() ([]byte, error) {
+	if recorder == nil {
+		return nil, nil
+	}
+
+	recorder.answers.mutex.Lock()
+	defer recorder.answers.mutex.Unlock()
+
+	var out bytes.Buffer
+
+	out.WriteString("# Answers recorded with --save-answers. Replay them with --var-file and --non-interactive.\n")
+
+	if len(recorder.answers.sensitive) > 0 {
+		out.WriteString("# The values of sensitive variables are not recorded, so pass them in some other way: ")
+		out.WriteString(strings.Join(slices.Sorted(slices.Values(recorder.answers.sensitive)), ", ") + "\n")
+	}
+
+	if len(recorder.answers.variables) > 0 {
+		if err := encodeYAML(&out, recorder.answers.variables); err != nil {
+			return nil, err
+		}
+	}
+
+	decisions := recorder.answers.decisions
+	if len(decisions.Dependencies)+len(decisions.Hooks)+len(decisions.ShellCommands) > 0 {
+		if err := encodeYAML(&out, map[string]any{DecisionsVarName: decisions}); err != nil {
+			return nil, err
+		}
+	}
+
+	return out.Bytes(), nil
+}
+
+func encodeYAML(out *bytes.Buffer, value any) error {
+	encoder := yaml.NewEncoder(out)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(value); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+// Custom error types
+
+type InvalidDecisions struct {
+	Err error
+}
+
+func (err InvalidDecisions) Error() string {
+	return "Invalid " + DecisionsVarName + " in var file: " + err.Err.Error()
+}
+
+func (err InvalidDecisions) Unwrap() error {
+	return err.Err
+}
@@ -0,0 +1,93 @@
+package variables //nolint:testpackage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestAnswerRecorderVarFile(t *testing.T) {
+	t.Parallel()
+
+	recorder := NewAnswerRecorder()
+	recorder.RecordVariable(NewStringVariable("Name"), "web")
+	recorder.RecordVariable(NewIntVariable("Port"), "9090")
+	recorder.RecordVariable(NewStringVariable("Token").WithSensitive(true), "hunter2")
+	recorder.RecordDependency("backend", true)
+	recorder.RecordHook("hook_abc", false)
+	recorder.RecordShellCommand("shell_def", true)
+
+	backend := recorder.ForDependency("backend")
+	backend.RecordVariable(NewStringVariable("Owner"), "team-a")
+	backend.RecordDependency("database", false)
+
+	varFile, err := recorder.VarFile()
+	require.NoError(t, err)
+	assert.Contains(t, string(varFile), "pass them in some other way: Token\n")
+	assert.NotContains(t, string(varFile), "hunter2")
+
+	vars := map[string]any{}
+	require.NoError(t, yaml.Unmarshal(varFile, &vars))
+
+	decisions, err := ExtractDecisions(vars)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{"Name": "web", "Port": 9090, "backend.Owner": "team-a"}, vars)
+	assert.Equal(t, &Decisions{
+		Dependencies:  map[string]bool{"backend": true, "backend.database": false},
+		Hooks:         map[string]bool{"hook_abc": false},
+		ShellCommands: map[string]bool{"shell_def": true},
+	}, decisions)
+}
+
+func TestAnswerRecorderForgetVariable(t *testing.T) {
+	t.Parallel()
+
+	recorder := NewAnswerRecorder()
+	recorder.RecordVariable(NewStringVariable("Name"), "web")
+	recorder.RecordVariable(NewStringVariable("Token").WithSensitive(true), "hunter2")
+
+	backend := recorder.ForDependency("backend")
+	backend.RecordVariable(NewStringVariable("Name"), "api")
+
+	recorder.ForgetVariable(NewStringVariable("Name"))
+	recorder.ForgetVariable(NewStringVariable("Token").WithSensitive(true))
+
+	varFile, err := recorder.VarFile()
+	require.NoError(t, err)
+	assert.NotContains(t, string(varFile), "Token")
+
+	vars := map[string]any{}
+	require.NoError(t, yaml.Unmarshal(varFile, &vars))
+	assert.Equal(t, map[string]any{"backend.Name": "api"}, vars)
+}
+
+func TestExtractDecisionsWithoutDecisions(t *testing.T) {
+	t.Parallel()
+
+	vars := map[string]any{"Name": "web"}
+
+	decisions, err := ExtractDecisions(vars)
+	require.NoError(t, err)
+	assert.Nil(t, decisions)
+	assert.Equal(t, map[string]any{"Name": "web"}, vars)
+
+	_, err = ExtractDecisions(map[string]any{DecisionsVarName: "not-a-map"})
+	assert.ErrorAs(t, err, &InvalidDecisions{})
+}
+
+func TestNilAnswerRecorder(t *testing.T) {
+	t.Parallel()
+
+	var recorder *AnswerRecorder
+
+	recorder.RecordVariable(NewStringVariable("Name"), "web")
+	recorder.RecordDependency("backend", true)
+	assert.Nil(t, recorder.ForDependency("backend"))
+
+	varFile, err := recorder.VarFile()
+	require.NoError(t, err)
+	assert.Nil(t, varFile)
+}
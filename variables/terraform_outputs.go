@@ -0,0 +1,96 @@
+package variables
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// TerraformOutputVarFilePrefix marks a var file as the output of `terraform output -json`. It can be used as
+// "tfoutput:PATH", or as "tfoutput:PREFIX=PATH" to prepend PREFIX to the name of every output.
+const TerraformOutputVarFilePrefix = "tfoutput:"
+
+var terraformOutputPrefixRegex = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)=(.+)$`)
+
+// terraformOutput is a single output in the JSON produced by `terraform output -json`.
+type terraformOutput struct {
+	Value     json.RawMessage `json:"value"`
+	Type      json.RawMessage `json:"type"`
+	Sensitive bool            `json:"sensitive"`
+}
+
+// SplitTerraformOutputVarFile checks if the given var file uses TerraformOutputVarFilePrefix and, if it does, returns
+// the path of the file and the prefix to prepend to the name of every output.
+func SplitTerraformOutputVarFile(varFile string) (path string, prefix string, isTerraformOutput bool) {
+	rest, isTerraformOutput := strings.CutPrefix(varFile, TerraformOutputVarFilePrefix)
+	if !isTerraformOutput {
+		return varFile, "", false
+	}
+
+	if matches := terraformOutputPrefixRegex.FindStringSubmatch(rest); matches != nil {
+		return matches[2], matches[1], true
+	}
+
+	return rest, "", true
+}
+
+// ParseTerraformOutputsFromFile parses the `terraform output -json` file at the given path. See ParseTerraformOutputs.
+func ParseTerraformOutputsFromFile(path string, prefix string) (map[string]any, []string, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return map[string]any{}, nil, err
+	}
+
+	vars, sensitive, err := ParseTerraformOutputs(contents, prefix)
+	if err != nil {
+		return map[string]any{}, nil, InvalidVarFile{Err: err, Path: path, Format: VarFileFormatTerraformOutput}
+	}
+
+	return vars, sensitive, nil
+}
+
+// ParseTerraformOutputs parses the JSON produced by `terraform output -json`, which maps each output name to an object
+// with its value, type, and whether it is sensitive, into a map of variable name to variable value. The name of each
+// variable is the name of the output with the given prefix prepended. Also returns the names of the variables that
+// came from sensitive outputs.
+func ParseTerraformOutputs(contents []byte, prefix string) (map[string]any, []string, error) {
+	vars := map[string]any{}
+	sensitive := []string{}
+
+	if len(bytes.TrimSpace(contents)) == 0 {
+		return vars, sensitive, nil
+	}
+
+	outputs := map[string]terraformOutput{}
+	if err := json.Unmarshal(contents, &outputs); err != nil {
+		return nil, nil, err
+	}
+
+	for name, output := range outputs {
+		if output.Value == nil {
+			return nil, nil, TerraformOutputMissingValue(name)
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(output.Value))
+		decoder.UseNumber()
+
+		var value any
+		if err := decoder.Decode(&value); err != nil {
+			return nil, nil, err
+		}
+
+		varName := prefix + name
+		vars[varName] = normalizeVarFileValue(value)
+
+		if output.Sensitive {
+			sensitive = append(sensitive, varName)
+		}
+	}
+
+	slices.Sort(sensitive)
+
+	return vars, sensitive, nil
+}
@@ -0,0 +1,64 @@
+package variables //nolint:testpackage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const terraformOutputJSON = `{
+  "vpc_id": {"sensitive": false, "type": "string", "value": "vpc-123"},
+  "subnet_ids": {"sensitive": false, "type": ["list", "string"], "value": ["subnet-a", "subnet-b"]},
+  "instance_count": {"sensitive": false, "type": "number", "value": 3},
+  "db": {"sensitive": true, "type": ["object", {"password": "string", "port": "number"}], "value": {"password": "hunter2", "port": 5432}}
+}`
+
+func TestParseTerraformOutputs(t *testing.T) {
+	t.Parallel()
+
+	vars, sensitive, err := ParseTerraformOutputs([]byte(terraformOutputJSON), "")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"vpc_id":         "vpc-123",
+		"subnet_ids":     []any{"subnet-a", "subnet-b"},
+		"instance_count": 3,
+		"db":             map[string]any{"password": "hunter2", "port": 5432},
+	}, vars)
+	assert.Equal(t, []string{"db"}, sensitive)
+
+	vars, sensitive, err = ParseTerraformOutputs([]byte(terraformOutputJSON), "network_")
+	require.NoError(t, err)
+	assert.Equal(t, "vpc-123", vars["network_vpc_id"])
+	assert.NotContains(t, vars, "vpc_id")
+	assert.Equal(t, []string{"network_db"}, sensitive)
+
+	_, _, err = ParseTerraformOutputs([]byte(`{"vpc_id": "vpc-123"}`), "")
+	require.Error(t, err)
+
+	_, _, err = ParseTerraformOutputs([]byte(`{"vpc_id": {"type": "string"}}`), "")
+	require.ErrorIs(t, err, TerraformOutputMissingValue("vpc_id"))
+}
+
+func TestSplitTerraformOutputVarFile(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		varFile           string
+		expectedPath      string
+		expectedPrefix    string
+		isTerraformOutput bool
+	}{
+		{varFile: "vars.yml", expectedPath: "vars.yml"},
+		{varFile: "tfoutput:outputs.json", expectedPath: "outputs.json", isTerraformOutput: true},
+		{varFile: "tfoutput:network_=outputs.json", expectedPath: "outputs.json", expectedPrefix: "network_", isTerraformOutput: true},
+		{varFile: "tfoutput:../stacks/a=b/outputs.json", expectedPath: "../stacks/a=b/outputs.json", isTerraformOutput: true},
+	}
+
+	for _, testCase := range testCases {
+		path, prefix, isTerraformOutput := SplitTerraformOutputVarFile(testCase.varFile)
+		assert.Equal(t, testCase.expectedPath, path, testCase.varFile)
+		assert.Equal(t, testCase.expectedPrefix, prefix, testCase.varFile)
+		assert.Equal(t, testCase.isTerraformOutput, isTerraformOutput, testCase.varFile)
+	}
+}
@@ -2,6 +2,7 @@ package variables
 
 import (
 	"fmt"
+	"strings"
 )
 
 // BoilerplateType represents an enum that represents the types we support for boilerplate variables
@@ -15,9 +16,26 @@ var (
 	List   = BoilerplateType("list")
 	Map    = BoilerplateType("map")
 	Enum   = BoilerplateType("enum")
+	Object = BoilerplateType("object")
+
+	ListOfStrings = BoilerplateType("list(string)")
+	ListOfInts    = BoilerplateType("list(int)")
+	ListOfFloats  = BoilerplateType("list(float)")
+	ListOfBools   = BoilerplateType("list(bool)")
+	ListOfObjects = BoilerplateType("list(object)")
+	ListOfEnums   = BoilerplateType("list(enum)")
+
+	MapOfStrings = BoilerplateType("map(string)")
+	MapOfInts    = BoilerplateType("map(int)")
+	MapOfFloats  = BoilerplateType("map(float)")
+	MapOfBools   = BoilerplateType("map(bool)")
 )
 
-var allBoilerplateTypes = []BoilerplateType{String, Int, Float, Bool, List, Map, Enum}
+var allBoilerplateTypes = []BoilerplateType{
+	String, Int, Float, Bool, List, Map, Enum, Object,
+	ListOfStrings, ListOfInts, ListOfFloats, ListOfBools, ListOfObjects, ListOfEnums,
+	MapOfStrings, MapOfInts, MapOfFloats, MapOfBools,
+}
 var boilerplateTypeDefault = String
 
 // ParseBoilerplateType converts the given string to a BoilerplateType enum, or returns an error if this is not a valid value for the
@@ -32,6 +50,46 @@ func ParseBoilerplateType(str string) (*BoilerplateType, error) {
 	return nil, InvalidBoilerplateType(str)
 }
 
+// IsObject returns true if this type has a declared set of typed fields, which is the case for object and
+// list(object) variables.
+func (boilerplateType BoilerplateType) IsObject() bool {
+	return boilerplateType == Object || boilerplateType == ListOfObjects
+}
+
+// HasOptions returns true if the values of this type are chosen from a declared set of options, which is the case for
+// enum and list(enum) variables.
+func (boilerplateType BoilerplateType) HasOptions() bool {
+	return boilerplateType == Enum || boilerplateType == ListOfEnums
+}
+
+// BaseType returns the collection type of a typed collection, so List for list(int) and Map for map(int). For all
+// other types, this returns the type itself.
+func (boilerplateType BoilerplateType) BaseType() BoilerplateType {
+	base, _, isTyped := boilerplateType.splitElementType()
+	if !isTyped {
+		return boilerplateType
+	}
+
+	return base
+}
+
+// ElementType returns the type of the elements of a typed collection, so Int for list(int) and map(int). For all
+// other types, this returns an empty type.
+func (boilerplateType BoilerplateType) ElementType() BoilerplateType {
+	_, element, _ := boilerplateType.splitElementType()
+	return element
+}
+
+// splitElementType splits a type of the format <base>(<element>) into its base and element types.
+func (boilerplateType BoilerplateType) splitElementType() (BoilerplateType, BoilerplateType, bool) {
+	base, rest, hasElement := strings.Cut(boilerplateType.String(), "(")
+	if !hasElement || !strings.HasSuffix(rest, ")") {
+		return boilerplateType, "", false
+	}
+
+	return BoilerplateType(base), BoilerplateType(strings.TrimSuffix(rest, ")")), true
+}
+
 // String returns a string representation of this Type
 func (boilerplateType BoilerplateType) String() string {
 	return string(boilerplateType)
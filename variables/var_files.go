@@ -0,0 +1,278 @@
+package variables
+
+import (
+	"bufio"
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"math/big"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/pelletier/go-toml/v2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// VarFileFormat represents the format of a file passed in via --var-file or a dependency's var_files
+type VarFileFormat string
+
+const (
+	VarFileFormatYAML   = VarFileFormat("yaml")
+	VarFileFormatJSON   = VarFileFormat("json")
+	VarFileFormatTOML   = VarFileFormat("toml")
+	VarFileFormatEnv    = VarFileFormat("env")
+	VarFileFormatTfvars = VarFileFormat("tfvars")
+
+	VarFileFormatTerraformOutput = VarFileFormat("terraform output")
+)
+
+// VarFileFormatForPath detects the format of the var file at the given path from its extension. Files with an
+// unrecognized extension are treated as YAML.
+func VarFileFormatForPath(varFilePath string) VarFileFormat {
+	name := strings.ToLower(filepath.Base(varFilePath))
+
+	switch {
+	case strings.HasSuffix(name, ".tfvars.json"), strings.HasSuffix(name, ".json"):
+		return VarFileFormatJSON
+	case strings.HasSuffix(name, ".tfvars"):
+		return VarFileFormatTfvars
+	case strings.HasSuffix(name, ".toml"):
+		return VarFileFormatTOML
+	case strings.HasSuffix(name, ".env"):
+		return VarFileFormatEnv
+	default:
+		return VarFileFormatYAML
+	}
+}
+
+// Parse the variables in the given var file contents, which are in the given format, into a map of variable name to
+// variable value.
+func parseVariablesFromVarFileContentsWithFormat(varFileContents []byte, format VarFileFormat, varFilePath string) (map[string]any, error) {
+	switch format {
+	case VarFileFormatJSON:
+		return parseVariablesFromJSON(varFileContents)
+	case VarFileFormatTOML:
+		return parseVariablesFromTOML(varFileContents)
+	case VarFileFormatEnv:
+		return parseVariablesFromEnvFile(varFileContents)
+	case VarFileFormatTfvars:
+		return parseVariablesFromTfvars(varFileContents, varFilePath)
+	case VarFileFormatYAML:
+		return parseVariablesFromVarFileContents(varFileContents)
+	default:
+		return parseVariablesFromVarFileContents(varFileContents)
+	}
+}
+
+// Parse the variables in the given JSON contents. This also handles Terraform's .tfvars.json files, which are a JSON
+// object of variable name to variable value.
+func parseVariablesFromJSON(contents []byte) (map[string]any, error) {
+	vars := map[string]any{}
+
+	if len(bytes.TrimSpace(contents)) == 0 {
+		return vars, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(contents))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&vars); err != nil {
+		return nil, err
+	}
+
+	return normalizeVarFileMap(vars), nil
+}
+
+// Parse the variables in the given TOML contents.
+func parseVariablesFromTOML(contents []byte) (map[string]any, error) {
+	vars := map[string]any{}
+
+	if err := toml.Unmarshal(contents, &vars); err != nil {
+		return nil, err
+	}
+
+	return normalizeVarFileMap(vars), nil
+}
+
+// Parse the variables in the given .env contents. Each non-empty line that is not a comment is expected to be in the
+// format KEY=VALUE, optionally prefixed with "export". Quoted values are always strings; unquoted values are parsed as
+// YAML, the same way as values passed in via --var.
+func parseVariablesFromEnvFile(contents []byte) (map[string]any, error) {
+	vars := map[string]any{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			return vars, InvalidVarSyntax(line)
+		}
+
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return vars, VariableNameCannotBeEmpty(line)
+		}
+
+		parsedValue, err := parseEnvFileValue(strings.TrimSpace(value))
+		if err != nil {
+			return vars, err
+		}
+
+		vars[key] = parsedValue
+	}
+
+	if err := scanner.Err(); err != nil {
+		return vars, err
+	}
+
+	return vars, nil
+}
+
+// Parse a single value from a .env file. Double-quoted values support the usual escape sequences, single-quoted values
+// are taken literally, and anything after an unquoted " #" is treated as a comment.
+func parseEnvFileValue(value string) (any, error) {
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		return strconv.Unquote(value)
+	}
+
+	if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		return value[1 : len(value)-1], nil
+	}
+
+	if before, _, found := strings.Cut(value, " #"); found {
+		value = strings.TrimSpace(before)
+	}
+
+	return ParseYamlString(value)
+}
+
+// Parse the variables in the given Terraform .tfvars contents. As with Terraform itself, each attribute must be a
+// literal value: references to variables and function calls are not allowed.
+func parseVariablesFromTfvars(contents []byte, varFilePath string) (map[string]any, error) {
+	file, diags := hclsyntax.ParseConfig(contents, varFilePath, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	attributes, diags := file.Body.JustAttributes()
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	vars := map[string]any{}
+
+	for name, attribute := range attributes {
+		value, diags := attribute.Expr.Value(nil)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+
+		vars[name] = ctyValueToGo(value)
+	}
+
+	return vars, nil
+}
+
+// Convert a cty value parsed from a .tfvars file into the same Go types we get from parsing YAML.
+func ctyValueToGo(value cty.Value) any {
+	if value.IsNull() || !value.IsKnown() {
+		return nil
+	}
+
+	valueType := value.Type()
+
+	switch {
+	case valueType == cty.String:
+		return value.AsString()
+	case valueType == cty.Bool:
+		return value.True()
+	case valueType == cty.Number:
+		return bigFloatToGo(value.AsBigFloat())
+	case valueType.IsListType(), valueType.IsSetType(), valueType.IsTupleType():
+		list := []any{}
+
+		for it := value.ElementIterator(); it.Next(); {
+			_, element := it.Element()
+			list = append(list, ctyValueToGo(element))
+		}
+
+		return list
+	case valueType.IsMapType(), valueType.IsObjectType():
+		m := map[string]any{}
+
+		for it := value.ElementIterator(); it.Next(); {
+			key, element := it.Element()
+			m[key.AsString()] = ctyValueToGo(element)
+		}
+
+		return m
+	default:
+		return nil
+	}
+}
+
+// Convert a number to an int if it is a whole number that fits in one, or a float64 otherwise.
+func bigFloatToGo(number *big.Float) any {
+	if number.IsInt() {
+		if asInt, accuracy := number.Int64(); accuracy == big.Exact {
+			return int(asInt)
+		}
+	}
+
+	asFloat, _ := number.Float64()
+
+	return asFloat
+}
+
+// Normalize the values parsed from JSON and TOML var files so they use the same Go types we get from parsing YAML:
+// whole numbers become ints, and dates and times become strings.
+func normalizeVarFileMap(vars map[string]any) map[string]any {
+	for key, value := range vars {
+		vars[key] = normalizeVarFileValue(value)
+	}
+
+	return vars
+}
+
+func normalizeVarFileValue(value any) any {
+	switch typedValue := value.(type) {
+	case map[string]any:
+		return normalizeVarFileMap(typedValue)
+	case []any:
+		for index, element := range typedValue {
+			typedValue[index] = normalizeVarFileValue(element)
+		}
+
+		return typedValue
+	case json.Number:
+		if asInt, err := typedValue.Int64(); err == nil {
+			return int(asInt)
+		}
+
+		if asFloat, err := typedValue.Float64(); err == nil {
+			return asFloat
+		}
+
+		return typedValue.String()
+	case int64:
+		return int(typedValue)
+	case encoding.TextMarshaler:
+		text, err := typedValue.MarshalText()
+		if err != nil {
+			return value
+		}
+
+		return string(text)
+	default:
+		return value
+	}
+}
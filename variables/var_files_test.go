@@ -0,0 +1,152 @@
+package variables //nolint:testpackage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVarFileFormatForPath(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		path     string
+		expected VarFileFormat
+	}{
+		{path: "vars.yml", expected: VarFileFormatYAML},
+		{path: "vars.yaml", expected: VarFileFormatYAML},
+		{path: "vars", expected: VarFileFormatYAML},
+		{path: "vars.json", expected: VarFileFormatJSON},
+		{path: "terraform.tfvars.json", expected: VarFileFormatJSON},
+		{path: "vars.TOML", expected: VarFileFormatTOML},
+		{path: ".env", expected: VarFileFormatEnv},
+		{path: "dir.tfvars/prod.env", expected: VarFileFormatEnv},
+		{path: "prod.tfvars", expected: VarFileFormatTfvars},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, VarFileFormatForPath(testCase.path), testCase.path)
+	}
+}
+
+func TestParseVariablesFromVarFileFormats(t *testing.T) {
+	t.Parallel()
+
+	expected := map[string]any{
+		"Name":    "web",
+		"Port":    8080,
+		"Ratio":   0.5,
+		"Enabled": true,
+		"Tags":    []any{"a", "b"},
+		"Config":  map[string]any{"host": "example.com", "replicas": 3},
+	}
+
+	testCases := []struct {
+		fileName string
+		contents string
+	}{
+		{
+			fileName: "vars.yml",
+			contents: `
+Name: web
+Port: 8080
+Ratio: 0.5
+Enabled: true
+Tags: [a, b]
+Config:
+  host: example.com
+  replicas: 3
+`,
+		},
+		{
+			fileName: "vars.json",
+			contents: `{"Name": "web", "Port": 8080, "Ratio": 0.5, "Enabled": true, "Tags": ["a", "b"], "Config": {"host": "example.com", "replicas": 3}}`,
+		},
+		{
+			fileName: "terraform.tfvars.json",
+			contents: `{"Name": "web", "Port": 8080, "Ratio": 0.5, "Enabled": true, "Tags": ["a", "b"], "Config": {"host": "example.com", "replicas": 3}}`,
+		},
+		{
+			fileName: "vars.toml",
+			contents: `
+Name = "web"
+Port = 8080
+Ratio = 0.5
+Enabled = true
+Tags = ["a", "b"]
+
+[Config]
+host = "example.com"
+replicas = 3
+`,
+		},
+		{
+			fileName: "vars.env",
+			contents: `
+# Comments and blank lines are ignored
+Name="web"
+export Port=8080
+Ratio=0.5 # inline comment
+Enabled=true
+Tags=[a, b]
+Config={host: example.com, replicas: 3}
+`,
+		},
+		{
+			fileName: "terraform.tfvars",
+			contents: `
+Name    = "web"
+Port    = 8080
+Ratio   = 0.5
+Enabled = true
+Tags    = ["a", "b"]
+Config = {
+  host     = "example.com"
+  replicas = 3
+}
+`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.fileName, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), testCase.fileName)
+			require.NoError(t, os.WriteFile(path, []byte(testCase.contents), 0o644))
+
+			actual, err := ParseVariablesFromVarFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, expected, actual)
+		})
+	}
+}
+
+func TestParseVariablesFromEnvFileQuoting(t *testing.T) {
+	t.Parallel()
+
+	actual, err := parseVariablesFromEnvFile([]byte("A=\"line1\\nline2\"\nB='not # a comment'\nC=\"8080\"\nD=\n"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"A": "line1\nline2", "B": "not # a comment", "C": "8080", "D": nil}, actual)
+
+	_, err = parseVariablesFromEnvFile([]byte("NOT_A_PAIR\n"))
+	require.ErrorIs(t, err, InvalidVarSyntax("NOT_A_PAIR"))
+}
+
+func TestParseVariablesFromInvalidVarFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "terraform.tfvars")
+	require.NoError(t, os.WriteFile(path, []byte(`Name = var.other`), 0o644))
+
+	_, err := ParseVariablesFromVarFile(path)
+	require.Error(t, err)
+
+	invalidVarFile := InvalidVarFile{}
+	require.ErrorAs(t, err, &invalidVarFile)
+	assert.Equal(t, path, invalidVarFile.Path)
+	assert.Equal(t, VarFileFormatTfvars, invalidVarFile.Format)
+}
@@ -3,12 +3,15 @@ package variables
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
+	"math"
 	"reflect"
 	"regexp"
 	"slices"
 	"strconv"
 	"strings"
 
+	"github.com/gruntwork-io/boilerplate/pkg/logging"
 	"github.com/gruntwork-io/boilerplate/validation"
 )
 
@@ -30,15 +33,41 @@ type Variable interface {
 	// The user-defined sorting position of the variable
 	Order() int
 
+	// Other names the variable used to have. Values passed in under one of these names are used for the variable, so
+	// that renaming a variable doesn't break the var files that still use its old name.
+	Aliases() []string
+
+	// The message to log when a value is passed in for the variable, if it is deprecated
+	Deprecated() string
+
+	// The name of the group the variable belongs to, if any. Variables in the same group are prompted for together,
+	// under a heading with the name of the group.
+	Group() string
+
 	// The default value for the variable, if any
 	Default() any
 
 	// The name of another variable from which this variable should take its value
 	Reference() string
 
-	// The values this variable can take. Applies only if Type() is Enum.
+	// The values this variable can take. Applies only if Type() is Enum or ListOfEnums.
 	Options() []string
 
+	// A Go template expression that renders to the values this variable can take, once the variables resolved before
+	// this one are known. If it is set, Options() is empty until the expression is rendered. Applies only if Type() is
+	// Enum or ListOfEnums.
+	OptionsExpression() string
+
+	// The minimum number of options that must be selected. Applies only if Type() is ListOfEnums.
+	MinSelections() int
+
+	// The maximum number of options that may be selected, or 0 if there is no maximum. Applies only if Type() is
+	// ListOfEnums.
+	MaxSelections() int
+
+	// The typed fields of this variable. Applies only if Type() is Object or ListOfObjects.
+	Fields() []Variable
+
 	// Return a copy of this variable but with the name set to the given name
 	WithName(string) Variable
 
@@ -63,6 +92,31 @@ type Variable interface {
 	// Return a copy of this variable but with the confirm flag set to the given value
 	WithConfirm(bool) Variable
 
+	// The computed value of the variable, if any. A computed variable is never prompted for and always takes this
+	// value, which may use Go template syntax to derive it from other variables.
+	Value() any
+
+	// Return a copy of this variable but with the computed value set to the given value
+	WithValue(any) Variable
+
+	// Whether a computed variable may be overridden with a value passed in via --var or --var-file
+	Overridable() bool
+
+	// Whether the value of the variable is a secret, such as a password, that should not be echoed when prompted for
+	// and that should be redacted wherever values are logged or recorded
+	Sensitive() bool
+
+	// Return a copy of this variable but with the sensitive flag set to the given value
+	WithSensitive(bool) Variable
+
+	// A Go template expression that is rendered against the variables resolved before this one. The variable is only
+	// prompted for (or required) if the expression evaluates to "true". An empty string means the variable is always
+	// active.
+	When() string
+
+	// Return a copy of this variable but with the when expression set to the given value
+	WithWhen(string) Variable
+
 	// Validations that should be run on the variable
 	Validations() []validation.CustomValidationRule
 }
@@ -70,14 +124,25 @@ type Variable interface {
 // A private implementation of the Variable interface that forces all users to use our public constructors
 type defaultVariable struct {
 	defaultValue any
+	value        any
 	name         string
 	description  string
 	reference    string
+	when         string
+	group        string
+	deprecated   string
 	variableType BoilerplateType
 	options      []string
+	optionsExpr  string
+	aliases      []string
+	fields       []Variable
 	validations  []validation.CustomValidationRule
 	order        int
+	minSelected  int
+	maxSelected  int
 	confirm      bool
+	overridable  bool
+	sensitive    bool
 }
 
 // NewStringVariable creates a new variable that holds a string
@@ -137,6 +202,33 @@ func NewEnumVariable(name string, options []string) Variable {
 	}
 }
 
+// NewListOfEnumsVariable creates a new variable that holds a list of values chosen from the given possible values
+func NewListOfEnumsVariable(name string, options []string) Variable {
+	return &defaultVariable{
+		name:         name,
+		variableType: ListOfEnums,
+		options:      options,
+	}
+}
+
+// NewObjectVariable creates a new variable that holds an object with the given typed fields
+func NewObjectVariable(name string, fields []Variable) Variable {
+	return &defaultVariable{
+		name:         name,
+		variableType: Object,
+		fields:       fields,
+	}
+}
+
+// NewListOfObjectsVariable creates a new variable that holds a list of objects, each with the given typed fields
+func NewListOfObjectsVariable(name string, fields []Variable) Variable {
+	return &defaultVariable{
+		name:         name,
+		variableType: ListOfObjects,
+		fields:       fields,
+	}
+}
+
 func (variable *defaultVariable) Name() string {
 	return variable.name
 }
@@ -162,6 +254,18 @@ func (variable *defaultVariable) Order() int {
 	return variable.order
 }
 
+func (variable *defaultVariable) Group() string {
+	return variable.group
+}
+
+func (variable *defaultVariable) Aliases() []string {
+	return variable.aliases
+}
+
+func (variable *defaultVariable) Deprecated() string {
+	return variable.deprecated
+}
+
 func (variable *defaultVariable) Default() any {
 	return variable.defaultValue
 }
@@ -174,6 +278,22 @@ func (variable *defaultVariable) Options() []string {
 	return variable.options
 }
 
+func (variable *defaultVariable) OptionsExpression() string {
+	return variable.optionsExpr
+}
+
+func (variable *defaultVariable) MinSelections() int {
+	return variable.minSelected
+}
+
+func (variable *defaultVariable) MaxSelections() int {
+	return variable.maxSelected
+}
+
+func (variable *defaultVariable) Fields() []Variable {
+	return variable.fields
+}
+
 func (variable *defaultVariable) Validations() []validation.CustomValidationRule {
 	return variable.validations
 }
@@ -182,6 +302,37 @@ func (variable *defaultVariable) Confirm() bool {
 	return variable.confirm
 }
 
+func (variable *defaultVariable) Value() any {
+	return variable.value
+}
+
+func (variable *defaultVariable) WithValue(value any) Variable {
+	variable.value = value
+	return variable
+}
+
+func (variable *defaultVariable) Overridable() bool {
+	return variable.overridable
+}
+
+func (variable *defaultVariable) Sensitive() bool {
+	return variable.sensitive
+}
+
+func (variable *defaultVariable) WithSensitive(sensitive bool) Variable {
+	variable.sensitive = sensitive
+	return variable
+}
+
+func (variable *defaultVariable) When() string {
+	return variable.when
+}
+
+func (variable *defaultVariable) WithWhen(when string) Variable {
+	variable.when = when
+	return variable
+}
+
 func (variable *defaultVariable) WithConfirm(confirm bool) Variable {
 	variable.confirm = confirm
 	return variable
@@ -203,7 +354,7 @@ func (variable *defaultVariable) WithDefault(value any) Variable {
 }
 
 func (variable *defaultVariable) String() string {
-	return fmt.Sprintf("Variable {Name: '%s', Description: '%s', Type: '%v', Default: '%v', Options: '%v', Reference: '%v'}", variable.Name(), variable.Description(), variable.Type(), variable.Default(), variable.Options(), variable.Reference())
+	return fmt.Sprintf("Variable {Name: '%s', Description: '%s', Type: '%v', Default: '%v', Options: '%v', Reference: '%v'}", variable.Name(), variable.Description(), variable.Type(), RedactValue(variable, variable.Default()), variable.Options(), variable.Reference())
 }
 
 func (variable *defaultVariable) ExampleValue() string {
@@ -220,13 +371,54 @@ func (variable *defaultVariable) ExampleValue() string {
 		return "[foo, bar, baz]"
 	case Map:
 		return "{foo: bar, baz: blah}"
+	case ListOfStrings, ListOfInts, ListOfFloats, ListOfBools:
+		return fmt.Sprintf("[%s, %s]", exampleElementValue(variable.Type()), exampleElementValue(variable.Type()))
+	case MapOfStrings, MapOfInts, MapOfFloats, MapOfBools:
+		return fmt.Sprintf("{foo: %s, bar: %s}", exampleElementValue(variable.Type()), exampleElementValue(variable.Type()))
 	case Enum:
-		return fmt.Sprintf("must be one of: %s", variable.Options())
+		return fmt.Sprintf("must be one of: %s", variable.optionsDescription())
+	case ListOfEnums:
+		return fmt.Sprintf("a list of any of: %s", variable.optionsDescription())
+	case Object:
+		return exampleObjectValue(variable.Fields())
+	case ListOfObjects:
+		return fmt.Sprintf("[%s]", exampleObjectValue(variable.Fields()))
 	default:
 		return ""
 	}
 }
 
+// optionsDescription returns the options of this variable or, if they haven't been rendered yet, the expression they
+// are rendered from.
+func (variable *defaultVariable) optionsDescription() string {
+	if hasUnrenderedOptions(variable) {
+		return fmt.Sprintf("the options that %s renders to", variable.OptionsExpression())
+	}
+
+	return fmt.Sprint(variable.Options())
+}
+
+// exampleElementValue returns an example value for a single element of the given typed list or map.
+func exampleElementValue(collectionType BoilerplateType) string {
+	if collectionType.ElementType() == Bool {
+		return "true"
+	}
+
+	element := &defaultVariable{variableType: collectionType.ElementType()}
+
+	return element.ExampleValue()
+}
+
+// exampleObjectValue renders an example object in YAML flow syntax, using the example value of each field.
+func exampleObjectValue(fields []Variable) string {
+	parts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		parts = append(parts, fmt.Sprintf("%s: %s", field.Name(), field.ExampleValue()))
+	}
+
+	return fmt.Sprintf("{%s}", strings.Join(parts, ", "))
+}
+
 // Define a custom marshaler for YAML so that variables (and thus any struct using it) can be marshaled into YAML.
 func (variable *defaultVariable) MarshalYAML() (any, error) {
 	varYml := map[string]any{}
@@ -247,18 +439,70 @@ func (variable *defaultVariable) MarshalYAML() (any, error) {
 		varYml["default"] = variable.Default()
 	}
 
+	if variable.Value() != nil {
+		varYml["value"] = variable.Value()
+	}
+
+	if variable.Overridable() {
+		varYml["overridable"] = true
+	}
+
 	if variable.Reference() != "" {
 		varYml["reference"] = variable.Reference()
 	}
 
-	if len(variable.Options()) > 0 {
+	if variable.OptionsExpression() != "" {
+		varYml["options"] = variable.OptionsExpression()
+	} else if len(variable.Options()) > 0 {
 		varYml["options"] = variable.Options()
 	}
 
+	if variable.MinSelections() > 0 {
+		varYml["min_selections"] = variable.MinSelections()
+	}
+
+	if variable.MaxSelections() > 0 {
+		varYml["max_selections"] = variable.MaxSelections()
+	}
+
+	if len(variable.Fields()) > 0 {
+		fieldsYml := make([]any, 0, len(variable.Fields()))
+		for _, field := range variable.Fields() {
+			fieldYml, err := field.MarshalYAML()
+			if err != nil {
+				return nil, err
+			}
+
+			fieldsYml = append(fieldsYml, fieldYml)
+		}
+
+		varYml["fields"] = fieldsYml
+	}
+
 	if len(variable.Validations()) > 0 {
 		varYml["validations"] = variable.Validations()
 	}
 
+	if variable.When() != "" {
+		varYml["when"] = variable.When()
+	}
+
+	if variable.Group() != "" {
+		varYml["group"] = variable.Group()
+	}
+
+	if len(variable.Aliases()) > 0 {
+		varYml["aliases"] = variable.Aliases()
+	}
+
+	if variable.Deprecated() != "" {
+		varYml["deprecated"] = variable.Deprecated()
+	}
+
+	if variable.Sensitive() {
+		varYml["sensitive"] = true
+	}
+
 	if variable.Confirm() {
 		varYml["confirm"] = true
 	}
@@ -266,6 +510,123 @@ func (variable *defaultVariable) MarshalYAML() (any, error) {
 	return varYml, nil
 }
 
+// SensitiveValuePlaceholder is shown and recorded in place of the value of a sensitive variable
+const SensitiveValuePlaceholder = "(sensitive)"
+
+// RedactValue returns the given value of the given variable, or SensitiveValuePlaceholder if the variable is
+// sensitive and the value is set.
+func RedactValue(variable Variable, value any) any {
+	if variable != nil && variable.Sensitive() && value != nil {
+		return SensitiveValuePlaceholder
+	}
+
+	return value
+}
+
+// RedactSensitiveVariables returns a copy of the given variable values in which the value of each variable that is
+// declared as sensitive in the given list of variables is replaced with SensitiveValuePlaceholder.
+func RedactSensitiveVariables(values map[string]any, declared []Variable) map[string]any {
+	redacted := make(map[string]any, len(values))
+	maps.Copy(redacted, values)
+
+	for _, variable := range declared {
+		if value, hasValue := redacted[variable.Name()]; hasValue {
+			redacted[variable.Name()] = RedactValue(variable, value)
+		}
+	}
+
+	return redacted
+}
+
+// RedactNamedVariables returns a copy of the given variable values in which the value of each of the given variable
+// names is replaced with SensitiveValuePlaceholder.
+func RedactNamedVariables(values map[string]any, names []string) map[string]any {
+	redacted := make(map[string]any, len(values))
+	maps.Copy(redacted, values)
+
+	for _, name := range names {
+		if value, hasValue := redacted[name]; hasValue && value != nil {
+			redacted[name] = SensitiveValuePlaceholder
+		}
+	}
+
+	return redacted
+}
+
+// RedactSensitiveText returns the given text with every occurrence of the value of a variable that is declared as
+// sensitive in the given list of variables replaced with SensitiveValuePlaceholder. This is used for output, such as
+// hook commands, that is rendered from the variable values.
+func RedactSensitiveText(text string, values map[string]any, declared []Variable) string {
+	return redactValues(text, SensitiveValues(values, declared, nil))
+}
+
+// SensitiveValues returns the values, as text, of the variables that are declared as sensitive in the given list of
+// variables, or whose names are in the given list of sensitive names, such as sensitive Terraform outputs.
+func SensitiveValues(values map[string]any, declared []Variable, sensitiveNames []string) []string {
+	names := slices.Clone(sensitiveNames)
+
+	for _, variable := range declared {
+		if variable.Sensitive() {
+			names = append(names, variable.Name())
+		}
+	}
+
+	sensitiveValues := []string{}
+
+	for _, name := range names {
+		value, hasValue := values[name]
+		if !hasValue || value == nil {
+			continue
+		}
+
+		if asString := fmt.Sprintf("%v", value); asString != "" && !slices.Contains(sensitiveValues, asString) {
+			sensitiveValues = append(sensitiveValues, asString)
+		}
+	}
+
+	return sensitiveValues
+}
+
+func redactValues(text string, sensitiveValues []string) string {
+	for _, value := range sensitiveValues {
+		text = strings.ReplaceAll(text, value, SensitiveValuePlaceholder)
+	}
+
+	return text
+}
+
+// RedactingLogger returns a logger that writes each record to the given logger with every occurrence of the given
+// sensitive values replaced with SensitiveValuePlaceholder, so that secrets don't end up in the logs of commands, such
+// as hooks and shell helpers, whose arguments are rendered from the variable values.
+func RedactingLogger(l logging.Logger, sensitiveValues []string) logging.Logger {
+	if len(sensitiveValues) == 0 {
+		return l
+	}
+
+	return redactingLogger{logger: l, sensitiveValues: sensitiveValues}
+}
+
+type redactingLogger struct {
+	logger          logging.Logger
+	sensitiveValues []string
+}
+
+func (r redactingLogger) Debugf(format string, args ...any) {
+	r.logger.Debugf("%s", redactValues(fmt.Sprintf(format, args...), r.sensitiveValues))
+}
+
+func (r redactingLogger) Infof(format string, args ...any) {
+	r.logger.Infof("%s", redactValues(fmt.Sprintf(format, args...), r.sensitiveValues))
+}
+
+func (r redactingLogger) Warnf(format string, args ...any) {
+	r.logger.Warnf("%s", redactValues(fmt.Sprintf(format, args...), r.sensitiveValues))
+}
+
+func (r redactingLogger) Errorf(format string, args ...any) {
+	r.logger.Errorf("%s", redactValues(fmt.Sprintf(format, args...), r.sensitiveValues))
+}
+
 // ConvertType checks that the given value matches the type we're expecting in the given variable and returns an error if it doesn't
 func ConvertType(value any, variable Variable) (any, error) {
 	if value == nil {
@@ -296,6 +657,11 @@ func ConvertType(value any, variable Variable) (any, error) {
 			return asInt, nil
 		}
 
+		// JSON decodes every number as a float64, so accept floats that hold a whole number
+		if asFloat, isFloat := value.(float64); isFloat && asFloat == math.Trunc(asFloat) {
+			return int(asFloat), nil
+		}
+
 		if isString {
 			return strconv.Atoi(asString)
 		}
@@ -304,6 +670,10 @@ func ConvertType(value any, variable Variable) (any, error) {
 			return asFloat, nil
 		}
 
+		if asInt, isInt := value.(int); isInt {
+			return float64(asInt), nil
+		}
+
 		if isString {
 			return strconv.ParseFloat(asString, 64)
 		}
@@ -321,7 +691,7 @@ func ConvertType(value any, variable Variable) (any, error) {
 		}
 
 		if isString {
-			return parseStringAsList(asString)
+			return parseStringAsList(asString, String)
 		}
 	case Map:
 		if reflect.TypeOf(value).Kind() == reflect.Map {
@@ -334,33 +704,238 @@ func ConvertType(value any, variable Variable) (any, error) {
 		}
 
 		if isString {
-			return parseStringAsMap(asString)
+			return parseStringAsMap(asString, String)
+		}
+	case ListOfStrings, ListOfInts, ListOfFloats, ListOfBools:
+		if isString {
+			return parseStringAsList(asString, variable.Type().ElementType())
+		}
+
+		if reflect.TypeOf(value).Kind() == reflect.Slice {
+			return convertListElements(value, variable.Type().ElementType())
+		}
+	case MapOfStrings, MapOfInts, MapOfFloats, MapOfBools:
+		if isString {
+			return parseStringAsMap(asString, variable.Type().ElementType())
+		}
+
+		if reflect.TypeOf(value).Kind() == reflect.Map {
+			return convertMapElements(value, variable.Type().ElementType())
 		}
 	case Enum:
 		if isString {
-			if slices.Contains(variable.Options(), asString) {
+			if slices.Contains(variable.Options(), asString) || hasUnrenderedOptions(variable) {
 				return asString, nil
 			}
 		}
+	case ListOfEnums:
+		if isString {
+			selections, err := parseStringAsList(asString, String)
+			if err != nil {
+				return nil, err
+			}
+
+			return convertSelections(selections, variable)
+		}
+
+		if reflect.TypeOf(value).Kind() == reflect.Slice {
+			selections, err := convertListElements(value, String)
+			if err != nil {
+				return nil, err
+			}
+
+			return convertSelections(selections, variable)
+		}
+	case Object:
+		if isString {
+			parsed, err := ParseYamlString(asString)
+			if err != nil {
+				return nil, err
+			}
+
+			return convertObject(parsed, variable)
+		}
+
+		return convertObject(value, variable)
+	case ListOfObjects:
+		if isString {
+			parsed, err := ParseYamlString(asString)
+			if err != nil {
+				return nil, err
+			}
+
+			// An empty or blank string parses to nil, which is not a list
+			if parsed == nil {
+				return nil, InvalidVariableValue{Variable: variable, Value: value}
+			}
+
+			value = parsed
+		}
+
+		if reflect.TypeOf(value).Kind() == reflect.Slice {
+			return convertListOfObjects(value, variable)
+		}
 	}
 
 	return nil, InvalidVariableValue{Variable: variable, Value: value}
 }
 
+// convertSelections checks that each of the given selections is one of the options of the given list(enum) variable,
+// that none of them is selected twice, and that the number of selections is within the bounds of the variable.
+func convertSelections(selections []any, variable Variable) ([]any, error) {
+	for i, selection := range selections {
+		if !slices.Contains(variable.Options(), selection.(string)) && !hasUnrenderedOptions(variable) {
+			return nil, InvalidVariableValue{Variable: variable, Value: selection}
+		}
+
+		if slices.Contains(selections[:i], selection) {
+			return nil, DuplicateSelection{Variable: variable, Value: selection}
+		}
+	}
+
+	if len(selections) < variable.MinSelections() || (variable.MaxSelections() > 0 && len(selections) > variable.MaxSelections()) {
+		return nil, SelectionCountOutOfRange{Variable: variable, Count: len(selections)}
+	}
+
+	return selections, nil
+}
+
+// hasUnrenderedOptions returns true if the options of the given variable are rendered from an expression that hasn't
+// been rendered yet, in which case its values can't be checked against them.
+func hasUnrenderedOptions(variable Variable) bool {
+	return variable.OptionsExpression() != "" && len(variable.Options()) == 0
+}
+
+// WithRenderedOptions returns a copy of the given variable with the given options, which its options expression
+// rendered to. The given variable is not modified, as its options may render differently for each use of the config.
+func WithRenderedOptions(variable Variable, options []string) Variable {
+	original, isDefaultVariable := variable.(*defaultVariable)
+	if !isDefaultVariable {
+		return variable
+	}
+
+	copied := *original
+	copied.options = options
+
+	return &copied
+}
+
+// convertObject converts the given map to an object with the typed fields declared on the given variable. Each field
+// is converted to its declared type, fields that are missing from the map take their default value, and keys that are
+// not declared as fields result in an error.
+func convertObject(value any, variable Variable) (map[string]any, error) {
+	valueMap := reflect.ValueOf(value)
+	if valueMap.Kind() != reflect.Map {
+		return nil, InvalidVariableValue{Variable: variable, Value: value}
+	}
+
+	fieldsByName := map[string]Variable{}
+	for _, field := range variable.Fields() {
+		fieldsByName[field.Name()] = field
+	}
+
+	object := map[string]any{}
+
+	for _, key := range valueMap.MapKeys() {
+		fieldName := fmt.Sprintf("%v", key.Interface())
+
+		field, isField := fieldsByName[fieldName]
+		if !isField {
+			return nil, UnknownObjectField{Variable: variable, FieldName: fieldName}
+		}
+
+		fieldValue, err := ConvertType(valueMap.MapIndex(key).Interface(), field)
+		if err != nil {
+			return nil, err
+		}
+
+		object[fieldName] = fieldValue
+	}
+
+	for _, field := range variable.Fields() {
+		if _, isSet := object[field.Name()]; isSet {
+			continue
+		}
+
+		fieldValue, err := ConvertType(field.Default(), field)
+		if err != nil {
+			return nil, err
+		}
+
+		object[field.Name()] = fieldValue
+	}
+
+	return object, nil
+}
+
+// convertListOfObjects converts each item in the given list to an object with the typed fields declared on the given
+// variable.
+func convertListOfObjects(value any, variable Variable) ([]any, error) {
+	valueList := reflect.ValueOf(value)
+	objects := make([]any, 0, valueList.Len())
+
+	for i := 0; i < valueList.Len(); i++ {
+		object, err := convertObject(valueList.Index(i).Interface(), variable)
+		if err != nil {
+			return nil, err
+		}
+
+		objects = append(objects, object)
+	}
+
+	return objects, nil
+}
+
 var goListSyntaxRegex = regexp.MustCompile(`\[(.*)]`)
 var goMapSyntaxRegex = regexp.MustCompile(`map\[(.*)]`)
 
-// This method converts a string to a list. The string can either be a valid JSON list or the string output of a Go
-// list.
-func parseStringAsList(str string) ([]string, error) {
+// convertListElements converts each element of the given list to the given element type.
+func convertListElements(value any, elementType BoilerplateType) ([]any, error) {
+	elementVariable := &defaultVariable{name: "element", variableType: elementType}
+	valueList := reflect.ValueOf(value)
+	out := make([]any, 0, valueList.Len())
+
+	for i := 0; i < valueList.Len(); i++ {
+		element, err := ConvertType(valueList.Index(i).Interface(), elementVariable)
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, element)
+	}
+
+	return out, nil
+}
+
+// convertMapElements converts each value of the given map to the given element type. Keys are always strings.
+func convertMapElements(value any, elementType BoilerplateType) (map[string]any, error) {
+	elementVariable := &defaultVariable{name: "element", variableType: elementType}
+	valueMap := reflect.ValueOf(value)
+	out := make(map[string]any, valueMap.Len())
+
+	for _, key := range valueMap.MapKeys() {
+		element, err := ConvertType(valueMap.MapIndex(key).Interface(), elementVariable)
+		if err != nil {
+			return nil, err
+		}
+
+		out[fmt.Sprintf("%v", key.Interface())] = element
+	}
+
+	return out, nil
+}
+
+// This method converts a string to a list whose elements have the given type. The string can either be a valid JSON
+// list or the string output of a Go list.
+func parseStringAsList(str string, elementType BoilerplateType) ([]any, error) {
 	jsonOut, jsonErr := parseStringAsJSONList(str)
 	if jsonErr == nil {
-		return jsonOut, nil
+		return convertListElements(jsonOut, elementType)
 	}
 
 	goOut, goErr := parseStringAsGoList(str)
 	if goErr == nil {
-		return goOut, nil
+		return convertListElements(goOut, elementType)
 	}
 
 	return nil, &FormatNotJSONOrGo{
@@ -397,8 +972,8 @@ func parseStringAsGoList(str string) ([]string, error) {
 }
 
 // Parse a string as a JSON list
-func parseStringAsJSONList(str string) ([]string, error) {
-	var out []string
+func parseStringAsJSONList(str string) ([]any, error) {
+	var out []any
 
 	if err := json.Unmarshal([]byte(str), &out); err != nil {
 		return nil, err
@@ -407,16 +982,17 @@ func parseStringAsJSONList(str string) ([]string, error) {
 	return out, nil
 }
 
-// This method converts a string to a map. The string can either be a valid JSON map or the string output of a Go map.
-func parseStringAsMap(str string) (map[string]string, error) {
+// This method converts a string to a map whose values have the given type. The string can either be a valid JSON map
+// or the string output of a Go map.
+func parseStringAsMap(str string, elementType BoilerplateType) (map[string]any, error) {
 	jsonOut, jsonErr := parseStringAsJSONMap(str)
 	if jsonErr == nil {
-		return jsonOut, nil
+		return convertMapElements(jsonOut, elementType)
 	}
 
 	goOut, goErr := parseStringAsGoMap(str)
 	if goErr == nil {
-		return goOut, nil
+		return convertMapElements(goOut, elementType)
 	}
 
 	return nil, &FormatNotJSONOrGo{
@@ -470,8 +1046,8 @@ func parseStringAsGoMap(str string) (map[string]string, error) {
 }
 
 // Parse a string as a JSON map
-func parseStringAsJSONMap(str string) (map[string]string, error) {
-	var out map[string]string
+func parseStringAsJSONMap(str string) (map[string]any, error) {
+	var out map[string]any
 
 	if err := json.Unmarshal([]byte(str), &out); err != nil {
 		return nil, err
@@ -510,9 +1086,34 @@ func UnmarshalVariablesFromBoilerplateConfigYaml(fields map[string]any) ([]Varia
 		unmarshalledVariables = append(unmarshalledVariables, variable)
 	}
 
+	if err := checkAliases(unmarshalledVariables); err != nil {
+		return unmarshalledVariables, err
+	}
+
 	return unmarshalledVariables, nil
 }
 
+// checkAliases returns an error if an alias of one of the given variables is the name or an alias of another variable,
+// as it would be ambiguous which variable a value passed in under that name is for.
+func checkAliases(declared []Variable) error {
+	owners := map[string]string{}
+	for _, variable := range declared {
+		owners[variable.Name()] = variable.Name()
+	}
+
+	for _, variable := range declared {
+		for _, alias := range variable.Aliases() {
+			if owner, isTaken := owners[alias]; isTaken {
+				return AliasConflict{Alias: alias, VariableName: variable.Name(), OtherVariableName: owner}
+			}
+
+			owners[alias] = variable.Name()
+		}
+	}
+
+	return nil
+}
+
 // UnmarshalVariableFromBoilerplateConfigYaml given a map of key:value pairs read from a Boilerplate YAML config file of the format:
 //
 // name: <n>
@@ -565,12 +1166,28 @@ func UnmarshalVariableFromBoilerplateConfigYaml(fields map[string]any) (Variable
 		variable.reference = *reference
 	}
 
-	options, err := unmarshalOptionsField(fields, *name, variableType)
+	options, optionsExpression, err := unmarshalOptionsField(fields, *name, variableType)
 	if err != nil {
 		return nil, err
 	}
 
 	variable.options = options
+	variable.optionsExpr = optionsExpression
+
+	minSelections, maxSelections, err := unmarshalSelectionBounds(fields, *name, variableType, len(options))
+	if err != nil {
+		return nil, err
+	}
+
+	variable.minSelected = minSelections
+	variable.maxSelected = maxSelections
+
+	fieldsForObject, err := unmarshalFieldsField(fields, *name, variableType)
+	if err != nil {
+		return nil, err
+	}
+
+	variable.fields = fieldsForObject
 
 	validationRules, err := validation.UnmarshalValidationsField(fields)
 	if err != nil {
@@ -580,6 +1197,22 @@ func UnmarshalVariableFromBoilerplateConfigYaml(fields map[string]any) (Variable
 	variable.validations = validationRules
 
 	variable.defaultValue = fields["default"]
+	variable.value = fields["value"]
+
+	if variable.value != nil && variable.defaultValue != nil {
+		return nil, ValueAndDefaultBothSet{Context: *name}
+	}
+
+	overridable, err := unmarshalBooleanField(fields, "overridable", false, *name)
+	if err != nil {
+		return nil, err
+	}
+
+	if overridable && variable.value == nil {
+		return nil, OverridableRequiresValue{Context: *name}
+	}
+
+	variable.overridable = overridable
 
 	confirm, err := unmarshalBooleanField(fields, "confirm", false, *name)
 	if err != nil {
@@ -588,6 +1221,47 @@ func UnmarshalVariableFromBoilerplateConfigYaml(fields map[string]any) (Variable
 
 	variable.confirm = confirm
 
+	sensitive, err := unmarshalBooleanField(fields, "sensitive", false, *name)
+	if err != nil {
+		return nil, err
+	}
+
+	variable.sensitive = sensitive
+
+	when, err := unmarshalStringField(fields, "when", false, *name)
+	if err != nil {
+		return nil, err
+	}
+
+	if when != nil {
+		variable.when = *when
+	}
+
+	group, err := unmarshalStringField(fields, "group", false, *name)
+	if err != nil {
+		return nil, err
+	}
+
+	if group != nil {
+		variable.group = *group
+	}
+
+	aliases, err := UnmarshalListOfStrings(fields, "aliases")
+	if err != nil {
+		return nil, err
+	}
+
+	variable.aliases = aliases
+
+	deprecated, err := unmarshalStringField(fields, "deprecated", false, *name)
+	if err != nil {
+		return nil, err
+	}
+
+	if deprecated != nil {
+		variable.deprecated = *deprecated
+	}
+
 	return &variable, nil
 }
 
@@ -614,3 +1288,17 @@ type FormatNotJSONOrGo struct {
 func (err *FormatNotJSONOrGo) Error() string {
 	return fmt.Sprintf("Expected a string in JSON format (e.g., %s) or Go format (e.g., %s), but got: %s. JSON parsing error: %v. Go parsing error: %v.", err.ExpectedJSONFormat, err.ExpectedGoFormat, err.ActualFormat, err.JSONErr, err.GoErr)
 }
+
+type AliasConflict struct {
+	Alias             string
+	VariableName      string
+	OtherVariableName string
+}
+
+func (err AliasConflict) Error() string {
+	if err.VariableName == err.OtherVariableName {
+		return fmt.Sprintf("Variable %s has the alias %s more than once, or as its own name.", err.VariableName, err.Alias)
+	}
+
+	return fmt.Sprintf("Variable %s has the alias %s, which is already the name or an alias of variable %s. Each name must refer to a single variable.", err.VariableName, err.Alias, err.OtherVariableName)
+}
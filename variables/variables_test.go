@@ -1,10 +1,13 @@
 package variables //nolint:testpackage
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/boilerplate/pkg/logging"
 )
 
 func TestParseStringAsList(t *testing.T) {
@@ -13,21 +16,21 @@ func TestParseStringAsList(t *testing.T) {
 	testCases := []struct {
 		testName     string
 		str          string
-		expectedList []string
+		expectedList []any
 	}{
-		{testName: "empty-list", str: "[]", expectedList: []string{}},
-		{testName: "one-item", str: "[a]", expectedList: []string{"a"}},
-		{testName: "three-items", str: "[a b c]", expectedList: []string{"a", "b", "c"}},
-		{testName: "leading-trailing-whitespace", str: "[ a b c ]", expectedList: []string{"a", "b", "c"}},
-		{testName: "json-list-one-item", str: `["a"]`, expectedList: []string{"a"}},
-		{testName: "json-list-three-items", str: `["a", "b", "c"]`, expectedList: []string{"a", "b", "c"}},
+		{testName: "empty-list", str: "[]", expectedList: []any{}},
+		{testName: "one-item", str: "[a]", expectedList: []any{"a"}},
+		{testName: "three-items", str: "[a b c]", expectedList: []any{"a", "b", "c"}},
+		{testName: "leading-trailing-whitespace", str: "[ a b c ]", expectedList: []any{"a", "b", "c"}},
+		{testName: "json-list-one-item", str: `["a"]`, expectedList: []any{"a"}},
+		{testName: "json-list-three-items", str: `["a", "b", "c"]`, expectedList: []any{"a", "b", "c"}},
 	}
 
 	for _, testCase := range testCases {
 		t.Run(testCase.testName, func(t *testing.T) {
 			t.Parallel()
 
-			actualList, err := parseStringAsList(testCase.str)
+			actualList, err := parseStringAsList(testCase.str, String)
 			require.NoError(t, err, "Got unexpected error for string '%s': %v", testCase.str, err)
 			assert.Equal(t, testCase.expectedList, actualList, "For string '%s'", testCase.str)
 		})
@@ -57,11 +60,14 @@ func TestConvertType(t *testing.T) {
 		{testName: "int-to-int", value: 42, variableType: Int, expectedValue: 42, expectError: false},
 		{testName: "string-to-int-valid", value: "123", variableType: Int, expectedValue: 123, expectError: false},
 		{testName: "string-to-int-invalid", value: "not-a-number", variableType: Int, expectedValue: nil, expectError: true},
+		{testName: "whole-float64-to-int", value: 42.0, variableType: Int, expectedValue: 42, expectError: false},
+		{testName: "fractional-float64-to-int-invalid", value: 42.5, variableType: Int, expectedValue: nil, expectError: true},
 
 		// Float type tests
 		{testName: "float64-to-float", value: 3.14, variableType: Float, expectedValue: 3.14, expectError: false},
 		{testName: "string-to-float-valid", value: "3.14", variableType: Float, expectedValue: 3.14, expectError: false},
 		{testName: "string-to-float-invalid", value: "not-a-float", variableType: Float, expectedValue: nil, expectError: true},
+		{testName: "int-to-float", value: 3, variableType: Float, expectedValue: 3.0, expectError: false},
 
 		// Bool type tests
 		{testName: "bool-to-bool-true", value: true, variableType: Bool, expectedValue: true, expectError: false},
@@ -115,6 +121,61 @@ func TestConvertType(t *testing.T) {
 	}
 }
 
+func TestConvertTypeTypedCollections(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		value         any
+		expectedValue any
+		testName      string
+		variableType  BoilerplateType
+		expectError   bool
+	}{
+		{testName: "list-of-ints", value: []any{1, "2", 3.0}, variableType: ListOfInts, expectedValue: []any{1, 2, 3}},
+		{testName: "list-of-ints-json", value: `[1, 2, 3]`, variableType: ListOfInts, expectedValue: []any{1, 2, 3}},
+		{testName: "list-of-ints-go", value: `[1 2 3]`, variableType: ListOfInts, expectedValue: []any{1, 2, 3}},
+		{testName: "list-of-ints-invalid", value: []any{1, "two"}, variableType: ListOfInts, expectError: true},
+		{testName: "list-of-bools", value: `[true false]`, variableType: ListOfBools, expectedValue: []any{true, false}},
+		{testName: "list-of-floats", value: []any{1, 2.5}, variableType: ListOfFloats, expectedValue: []any{1.0, 2.5}},
+		{testName: "list-of-strings", value: []any{1, "b"}, variableType: ListOfStrings, expectedValue: []any{"1", "b"}},
+		{testName: "map-of-ints", value: map[string]any{"a": 1, "b": "2"}, variableType: MapOfInts, expectedValue: map[string]any{"a": 1, "b": 2}},
+		{testName: "map-of-ints-json", value: `{"a": 1, "b": 2}`, variableType: MapOfInts, expectedValue: map[string]any{"a": 1, "b": 2}},
+		{testName: "map-of-bools-go", value: `map[a:true b:false]`, variableType: MapOfBools, expectedValue: map[string]any{"a": true, "b": false}},
+		{testName: "map-of-ints-invalid", value: map[string]any{"a": "one"}, variableType: MapOfInts, expectError: true},
+		{testName: "map-of-strings-not-a-map", value: []any{"a"}, variableType: MapOfStrings, expectError: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.testName, func(t *testing.T) {
+			t.Parallel()
+
+			variable, err := UnmarshalVariableFromBoilerplateConfigYaml(map[string]any{"name": "test-var", "type": testCase.variableType.String()})
+			require.NoError(t, err)
+
+			actualValue, err := ConvertType(testCase.value, variable)
+
+			if testCase.expectError {
+				require.Error(t, err, "Expected error for test case: %s", testCase.testName)
+			} else {
+				require.NoError(t, err, "Got unexpected error for test case '%s': %v", testCase.testName, err)
+				assert.Equal(t, testCase.expectedValue, actualValue, "For test case '%s'", testCase.testName)
+			}
+		})
+	}
+}
+
+func TestBoilerplateTypeElementType(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, List, ListOfInts.BaseType())
+	assert.Equal(t, Int, ListOfInts.ElementType())
+	assert.Equal(t, Map, MapOfBools.BaseType())
+	assert.Equal(t, Bool, MapOfBools.ElementType())
+	assert.Equal(t, String, String.BaseType())
+	assert.Equal(t, BoilerplateType(""), String.ElementType())
+	assert.Equal(t, BoilerplateType(""), List.ElementType())
+}
+
 func TestUnmarshalVariableWithConfirm(t *testing.T) {
 	t.Parallel()
 
@@ -147,6 +208,97 @@ func TestUnmarshalVariableWithoutConfirm(t *testing.T) {
 	assert.False(t, variable.Confirm())
 }
 
+func TestUnmarshalVariableWithWhen(t *testing.T) {
+	t.Parallel()
+
+	fields := map[string]any{
+		"name": "MyVar",
+		"when": "{{ .EnableFeature }}",
+	}
+
+	variable, err := UnmarshalVariableFromBoilerplateConfigYaml(fields)
+	require.NoError(t, err)
+	assert.Equal(t, "{{ .EnableFeature }}", variable.When())
+
+	marshaled, err := variable.MarshalYAML()
+	require.NoError(t, err)
+
+	asMap, ok := marshaled.(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "{{ .EnableFeature }}", asMap["when"])
+}
+
+func TestUnmarshalVariableWithGroup(t *testing.T) {
+	t.Parallel()
+
+	variable, err := UnmarshalVariableFromBoilerplateConfigYaml(map[string]any{"name": "MyVar", "group": "Network"})
+	require.NoError(t, err)
+	assert.Equal(t, "Network", variable.Group())
+
+	marshaled, err := variable.MarshalYAML()
+	require.NoError(t, err)
+
+	asMap, ok := marshaled.(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "Network", asMap["group"])
+}
+
+func TestUnmarshalVariableWithValue(t *testing.T) {
+	t.Parallel()
+
+	variable, err := UnmarshalVariableFromBoilerplateConfigYaml(map[string]any{
+		"name":        "MyVar",
+		"value":       "{{ .Other }}-suffix",
+		"overridable": true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "{{ .Other }}-suffix", variable.Value())
+	assert.True(t, variable.Overridable())
+
+	_, err = UnmarshalVariableFromBoilerplateConfigYaml(map[string]any{"name": "MyVar", "value": "a", "default": "b"})
+	require.ErrorAs(t, err, &ValueAndDefaultBothSet{})
+
+	_, err = UnmarshalVariableFromBoilerplateConfigYaml(map[string]any{"name": "MyVar", "overridable": true})
+	require.ErrorAs(t, err, &OverridableRequiresValue{})
+}
+
+func TestRedactSensitiveVariables(t *testing.T) {
+	t.Parallel()
+
+	password, err := UnmarshalVariableFromBoilerplateConfigYaml(map[string]any{"name": "Password", "sensitive": true})
+	require.NoError(t, err)
+	assert.True(t, password.Sensitive())
+
+	declared := []Variable{NewStringVariable("Name"), password, NewStringVariable("Token").WithSensitive(true)}
+	values := map[string]any{"Name": "app", "Password": "hunter2"}
+
+	redacted := RedactSensitiveVariables(values, declared)
+	assert.Equal(t, map[string]any{"Name": "app", "Password": SensitiveValuePlaceholder}, redacted)
+	assert.Equal(t, "hunter2", values["Password"], "the original values must not be modified")
+
+	assert.Equal(t, "psql --user app --password "+SensitiveValuePlaceholder, RedactSensitiveText("psql --user app --password hunter2", values, declared))
+	assert.Equal(t, SensitiveValuePlaceholder, RedactValue(password, "hunter2"))
+	assert.Nil(t, RedactValue(password, nil))
+
+	assert.Equal(t, []string{"tf-secret", "hunter2"}, SensitiveValues(map[string]any{"Password": "hunter2", "net_key": "tf-secret"}, declared, []string{"net_key"}))
+}
+
+func TestRedactingLogger(t *testing.T) {
+	t.Parallel()
+
+	var logs bytes.Buffer
+
+	l := RedactingLogger(logging.New(&logs, logging.LevelDebug), []string{"hunter2"})
+	l.Debugf("Running command: %s %s", "psql", "--password=hunter2")
+	l.Warnf("Failed to connect with %v", []string{"hunter2"})
+
+	assert.NotContains(t, logs.String(), "hunter2")
+	assert.Contains(t, logs.String(), "Running command: psql --password="+SensitiveValuePlaceholder)
+
+	discard := logging.Discard()
+	assert.Equal(t, discard, RedactingLogger(discard, nil), "there is nothing to redact without sensitive values")
+}
+
 func TestMarshalVariableWithConfirm(t *testing.T) {
 	t.Parallel()
 
@@ -179,27 +331,326 @@ func TestParseStringAsMap(t *testing.T) {
 	t.Parallel()
 
 	testCases := []struct {
-		expectedMap map[string]string
+		expectedMap map[string]any
 		testName    string
 		str         string
 	}{
-		{testName: "empty-map", str: "map[]", expectedMap: map[string]string{}},
-		{testName: "one-item", str: "map[a:b]", expectedMap: map[string]string{"a": "b"}},
-		{testName: "three-items", str: "map[a:b c:d e:f]", expectedMap: map[string]string{"a": "b", "c": "d", "e": "f"}},
-		{testName: "multiple-colons", str: "map[a:b:c:d:e]", expectedMap: map[string]string{"a:b:c:d": "e"}},
-		{testName: "leading-trailing-whitespace", str: "map[ a:b c:d e:f ]", expectedMap: map[string]string{"a": "b", "c": "d", "e": "f"}},
-		{testName: "json-map-empty", str: `{}`, expectedMap: map[string]string{}},
-		{testName: "json-map-one-item", str: `{"a": "b"}`, expectedMap: map[string]string{"a": "b"}},
-		{testName: "json-map-three-items", str: `{"a": "b", "c": "d", "e": "f"}`, expectedMap: map[string]string{"a": "b", "c": "d", "e": "f"}},
+		{testName: "empty-map", str: "map[]", expectedMap: map[string]any{}},
+		{testName: "one-item", str: "map[a:b]", expectedMap: map[string]any{"a": "b"}},
+		{testName: "three-items", str: "map[a:b c:d e:f]", expectedMap: map[string]any{"a": "b", "c": "d", "e": "f"}},
+		{testName: "multiple-colons", str: "map[a:b:c:d:e]", expectedMap: map[string]any{"a:b:c:d": "e"}},
+		{testName: "leading-trailing-whitespace", str: "map[ a:b c:d e:f ]", expectedMap: map[string]any{"a": "b", "c": "d", "e": "f"}},
+		{testName: "json-map-empty", str: `{}`, expectedMap: map[string]any{}},
+		{testName: "json-map-one-item", str: `{"a": "b"}`, expectedMap: map[string]any{"a": "b"}},
+		{testName: "json-map-three-items", str: `{"a": "b", "c": "d", "e": "f"}`, expectedMap: map[string]any{"a": "b", "c": "d", "e": "f"}},
 	}
 
 	for _, testCase := range testCases {
 		t.Run(testCase.testName, func(t *testing.T) {
 			t.Parallel()
 
-			actualMap, err := parseStringAsMap(testCase.str)
+			actualMap, err := parseStringAsMap(testCase.str, String)
 			require.NoError(t, err, "Got unexpected error for string '%s': %v", testCase.str, err)
 			assert.Equal(t, testCase.expectedMap, actualMap, "For string '%s'", testCase.str)
 		})
 	}
 }
+
+func TestConvertTypeObject(t *testing.T) {
+	t.Parallel()
+
+	subnet := NewObjectVariable("Subnet", []Variable{
+		NewStringVariable("CIDR"),
+		NewStringVariable("AZ").WithDefault("us-east-1a"),
+		NewIntVariable("Size").WithDefault(24),
+	})
+
+	testCases := []struct {
+		value         any
+		expectedValue any
+		testName      string
+		expectError   bool
+	}{
+		{testName: "map-with-all-fields", value: map[string]any{"CIDR": "10.0.0.0/24", "AZ": "us-east-1b", "Size": 16}, expectedValue: map[string]any{"CIDR": "10.0.0.0/24", "AZ": "us-east-1b", "Size": 16}},
+		{testName: "map-with-field-defaults", value: map[string]any{"CIDR": "10.0.0.0/24"}, expectedValue: map[string]any{"CIDR": "10.0.0.0/24", "AZ": "us-east-1a", "Size": 24}},
+		{testName: "map-with-string-field-values", value: map[string]any{"CIDR": "10.0.0.0/24", "Size": "16"}, expectedValue: map[string]any{"CIDR": "10.0.0.0/24", "AZ": "us-east-1a", "Size": 16}},
+		{testName: "json-string", value: `{"CIDR": "10.0.0.0/24"}`, expectedValue: map[string]any{"CIDR": "10.0.0.0/24", "AZ": "us-east-1a", "Size": 24}},
+		{testName: "unknown-field", value: map[string]any{"CIDR": "10.0.0.0/24", "Zone": "a"}, expectError: true},
+		{testName: "invalid-field-type", value: map[string]any{"CIDR": "10.0.0.0/24", "Size": "large"}, expectError: true},
+		{testName: "not-a-map", value: []any{"10.0.0.0/24"}, expectError: true},
+		{testName: "empty-string", value: "", expectError: true},
+		{testName: "whitespace-only-string", value: "  \n  ", expectError: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.testName, func(t *testing.T) {
+			t.Parallel()
+
+			actualValue, err := ConvertType(testCase.value, subnet)
+			if testCase.expectError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedValue, actualValue)
+		})
+	}
+}
+
+func TestConvertTypeListOfObjects(t *testing.T) {
+	t.Parallel()
+
+	subnets := NewListOfObjectsVariable("Subnets", []Variable{
+		NewStringVariable("CIDR"),
+		NewStringVariable("AZ").WithDefault("us-east-1a"),
+	})
+
+	actualValue, err := ConvertType([]any{
+		map[string]any{"CIDR": "10.0.0.0/24"},
+		map[string]any{"CIDR": "10.0.1.0/24", "AZ": "us-east-1b"},
+	}, subnets)
+	require.NoError(t, err)
+	assert.Equal(t, []any{
+		map[string]any{"CIDR": "10.0.0.0/24", "AZ": "us-east-1a"},
+		map[string]any{"CIDR": "10.0.1.0/24", "AZ": "us-east-1b"},
+	}, actualValue)
+
+	actualValue, err = ConvertType(`[{"CIDR": "10.0.0.0/24"}]`, subnets)
+	require.NoError(t, err)
+	assert.Equal(t, []any{map[string]any{"CIDR": "10.0.0.0/24", "AZ": "us-east-1a"}}, actualValue)
+
+	invalidValues := []struct {
+		value    any
+		testName string
+	}{
+		{testName: "list-of-strings", value: []any{"10.0.0.0/24"}},
+		{testName: "empty-string", value: ""},
+		{testName: "whitespace-only-string", value: "  \n  "},
+		{testName: "object-string", value: `{"CIDR": "10.0.0.0/24"}`},
+	}
+
+	for _, testCase := range invalidValues {
+		t.Run(testCase.testName, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ConvertType(testCase.value, subnets)
+			require.ErrorAs(t, err, &InvalidVariableValue{})
+		})
+	}
+}
+
+func TestUnmarshalVariableWithFields(t *testing.T) {
+	t.Parallel()
+
+	fields := map[string]any{
+		"name": "Subnets",
+		"type": "list(object)",
+		"fields": []any{
+			map[string]any{"name": "CIDR", "validations": []any{"required"}},
+			map[string]any{"name": "AZ", "default": "us-east-1a"},
+		},
+	}
+
+	variable, err := UnmarshalVariableFromBoilerplateConfigYaml(fields)
+	require.NoError(t, err)
+	assert.Equal(t, ListOfObjects, variable.Type())
+	require.Len(t, variable.Fields(), 2)
+	assert.Equal(t, "CIDR", variable.Fields()[0].Name())
+	assert.Len(t, variable.Fields()[0].Validations(), 1)
+	assert.Equal(t, "us-east-1a", variable.Fields()[1].Default())
+}
+
+func TestUnmarshalVariableFieldsErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := UnmarshalVariableFromBoilerplateConfigYaml(map[string]any{"name": "Obj", "type": "object"})
+	require.ErrorAs(t, err, &FieldsMissing{})
+
+	_, err = UnmarshalVariableFromBoilerplateConfigYaml(map[string]any{
+		"name":   "Str",
+		"type":   "string",
+		"fields": []any{map[string]any{"name": "A"}},
+	})
+	require.ErrorAs(t, err, &FieldsCanOnlyBeUsedWithObject{})
+
+	_, err = UnmarshalVariableFromBoilerplateConfigYaml(map[string]any{
+		"name":   "Obj",
+		"type":   "object",
+		"fields": []any{map[string]any{"name": "A"}, map[string]any{"name": "A"}},
+	})
+	require.ErrorAs(t, err, &DuplicateFieldName{})
+}
+
+func TestConvertTypeListOfEnums(t *testing.T) {
+	t.Parallel()
+
+	variable, err := UnmarshalVariableFromBoilerplateConfigYaml(map[string]any{
+		"name":           "Components",
+		"type":           "list(enum)",
+		"options":        []any{"api", "worker", "cron"},
+		"min_selections": 1,
+		"max_selections": 2,
+	})
+	require.NoError(t, err)
+
+	actual, err := ConvertType([]any{"api", "cron"}, variable)
+	require.NoError(t, err)
+	assert.Equal(t, []any{"api", "cron"}, actual)
+
+	actual, err = ConvertType(`["worker"]`, variable)
+	require.NoError(t, err)
+	assert.Equal(t, []any{"worker"}, actual)
+
+	actual, err = ConvertType("[api worker]", variable)
+	require.NoError(t, err)
+	assert.Equal(t, []any{"api", "worker"}, actual)
+
+	_, err = ConvertType([]any{"api", "db"}, variable)
+	require.ErrorAs(t, err, &InvalidVariableValue{})
+
+	_, err = ConvertType([]string{"api", "api"}, variable)
+	require.ErrorAs(t, err, &DuplicateSelection{})
+
+	_, err = ConvertType([]any{}, variable)
+	require.ErrorAs(t, err, &SelectionCountOutOfRange{})
+
+	_, err = ConvertType([]any{"api", "worker", "cron"}, variable)
+	require.ErrorAs(t, err, &SelectionCountOutOfRange{})
+}
+
+func TestUnmarshalVariableSelectionBounds(t *testing.T) {
+	t.Parallel()
+
+	variable, err := UnmarshalVariableFromBoilerplateConfigYaml(map[string]any{
+		"name":           "Components",
+		"type":           "list(enum)",
+		"options":        []any{"api", "worker"},
+		"max_selections": 1,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api", "worker"}, variable.Options())
+	assert.Equal(t, 0, variable.MinSelections())
+	assert.Equal(t, 1, variable.MaxSelections())
+
+	marshaled, err := variable.MarshalYAML()
+	require.NoError(t, err)
+
+	asMap, ok := marshaled.(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, 1, asMap["max_selections"])
+	assert.NotContains(t, asMap, "min_selections")
+
+	_, err = UnmarshalVariableFromBoilerplateConfigYaml(map[string]any{"name": "Components", "type": "list(enum)"})
+	require.ErrorIs(t, err, OptionsMissing("Components"))
+
+	_, err = UnmarshalVariableFromBoilerplateConfigYaml(map[string]any{"name": "Tags", "type": "list(string)", "min_selections": 1})
+	require.ErrorAs(t, err, &SelectionBoundsCanOnlyBeUsedWithListOfEnums{})
+
+	_, err = UnmarshalVariableFromBoilerplateConfigYaml(map[string]any{
+		"name":           "Components",
+		"type":           "list(enum)",
+		"options":        []any{"api", "worker"},
+		"min_selections": 2,
+		"max_selections": 1,
+	})
+	require.ErrorAs(t, err, &InvalidSelectionBounds{})
+
+	_, err = UnmarshalVariableFromBoilerplateConfigYaml(map[string]any{
+		"name":           "Components",
+		"type":           "list(enum)",
+		"options":        []any{"api", "worker"},
+		"min_selections": 3,
+	})
+	require.ErrorAs(t, err, &InvalidSelectionBounds{})
+}
+
+func TestUnmarshalVariableWithOptionsExpression(t *testing.T) {
+	t.Parallel()
+
+	variable, err := UnmarshalVariableFromBoilerplateConfigYaml(map[string]any{
+		"name":    "Region",
+		"type":    "enum",
+		"options": "{{ .Regions }}",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "{{ .Regions }}", variable.OptionsExpression())
+	assert.Empty(t, variable.Options())
+
+	marshaled, err := variable.MarshalYAML()
+	require.NoError(t, err)
+
+	asMap, ok := marshaled.(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "{{ .Regions }}", asMap["options"])
+
+	// Until the options are rendered, any value is accepted
+	actual, err := ConvertType("us-east-1", variable)
+	require.NoError(t, err)
+	assert.Equal(t, "us-east-1", actual)
+
+	rendered := WithRenderedOptions(variable, []string{"eu-west-1"})
+	assert.Empty(t, variable.Options())
+	assert.Equal(t, []string{"eu-west-1"}, rendered.Options())
+
+	_, err = ConvertType("us-east-1", rendered)
+	require.ErrorAs(t, err, &InvalidVariableValue{})
+
+	// A string without template syntax is not an expression
+	_, err = UnmarshalVariableFromBoilerplateConfigYaml(map[string]any{"name": "Region", "type": "enum", "options": "us-east-1"})
+	require.ErrorAs(t, err, &InvalidTypeForField{})
+}
+
+func TestParseRenderedOptions(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		rendered string
+		expected []string
+	}{
+		{"[us-east-1 eu-west-1]", []string{"us-east-1", "eu-west-1"}},
+		{`["us-east-1", "eu-west-1"]`, []string{"us-east-1", "eu-west-1"}},
+		{"\n- us-east-1\n- eu-west-1\n", []string{"us-east-1", "eu-west-1"}},
+		{"us-east-1\n\neu-west-1", []string{"us-east-1", "eu-west-1"}},
+		{"  ", []string{}},
+	}
+
+	for _, testCase := range testCases {
+		actual, err := ParseRenderedOptions(testCase.rendered)
+		require.NoError(t, err, testCase.rendered)
+		assert.Equal(t, testCase.expected, actual, testCase.rendered)
+	}
+}
+
+func TestUnmarshalVariableWithAliasesAndDeprecated(t *testing.T) {
+	t.Parallel()
+
+	variable, err := UnmarshalVariableFromBoilerplateConfigYaml(map[string]any{
+		"name":       "ServiceName",
+		"aliases":    []any{"Name"},
+		"deprecated": "Use ServiceId instead",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Name"}, variable.Aliases())
+	assert.Equal(t, "Use ServiceId instead", variable.Deprecated())
+
+	marshaled, err := variable.MarshalYAML()
+	require.NoError(t, err)
+
+	asMap, ok := marshaled.(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, []string{"Name"}, asMap["aliases"])
+	assert.Equal(t, "Use ServiceId instead", asMap["deprecated"])
+
+	_, err = UnmarshalVariablesFromBoilerplateConfigYaml(map[string]any{"variables": []any{
+		map[string]any{"name": "ServiceName", "aliases": []any{"Name"}},
+		map[string]any{"name": "Name"},
+	}})
+	require.ErrorAs(t, err, &AliasConflict{})
+
+	_, err = UnmarshalVariablesFromBoilerplateConfigYaml(map[string]any{"variables": []any{
+		map[string]any{"name": "ServiceName", "aliases": []any{"Name"}},
+		map[string]any{"name": "AppName", "aliases": []any{"Name"}},
+	}})
+	require.ErrorAs(t, err, &AliasConflict{})
+}
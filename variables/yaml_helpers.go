@@ -63,6 +63,11 @@ func unmarshalMapOfStrings(fields map[string]any, fieldName string) (map[string]
 	return stringMap, nil
 }
 
+// UnmarshalMapOfStrings is the public convenience interface for unmarshalMapOfStrings.
+func UnmarshalMapOfStrings(fields map[string]any, fieldName string) (map[string]string, error) {
+	return unmarshalMapOfStrings(fields, fieldName)
+}
+
 // UnmarshalListOfStrings given a map of key:value pairs read from a Boilerplate YAML config file of the format:
 //
 // fieldName:
@@ -145,30 +150,153 @@ func unmarshalListOfFields(fields map[string]any, fieldName string) ([]map[strin
 //   - baz
 //
 // This method takes looks up the options object in the map and unmarshals the data inside of it it into a list of
-// strings. This is meant to be used to parse the options field of an Enum variable. If the given variableType is not
-// an Enum and options have been specified, or this variable is an Enum and options have not been specified, this
-// method will return an error.
-func unmarshalOptionsField(fields map[string]any, context string, variableType BoilerplateType) ([]string, error) {
+// strings. The options may also be a string with Go template syntax that renders to a list, such as
+// "{{ index .RegionsByProvider .Provider }}", in which case the expression is returned instead, to be rendered once
+// the variables it refers to are known. This is meant to be used to parse the options field of an Enum or ListOfEnums
+// variable. If the given variableType is not one of those and options have been specified, or it is one of those and
+// options have not been specified, this method will return an error.
+func unmarshalOptionsField(fields map[string]any, context string, variableType BoilerplateType) ([]string, string, error) {
 	options, hasOptions := fields["options"]
 
 	if !hasOptions {
-		if variableType == Enum {
-			return nil, OptionsMissing(context)
+		if variableType.HasOptions() {
+			return nil, "", OptionsMissing(context)
 		} else {
-			return nil, nil
+			return nil, "", nil
 		}
 	}
 
-	if variableType != Enum {
-		return nil, OptionsCanOnlyBeUsedWithEnum{Context: context, Type: variableType}
+	if !variableType.HasOptions() {
+		return nil, "", OptionsCanOnlyBeUsedWithEnum{Context: context, Type: variableType}
+	}
+
+	if expression, isString := options.(string); isString && strings.Contains(expression, "{{") {
+		return nil, expression, nil
 	}
 
 	optionsAsList, isList := options.([]any)
 	if !isList {
-		return nil, InvalidTypeForField{FieldName: "options", ExpectedType: "List", ActualType: reflect.TypeOf(options), Context: context}
+		return nil, "", InvalidTypeForField{FieldName: "options", ExpectedType: "List", ActualType: reflect.TypeOf(options), Context: context}
+	}
+
+	return util.ToStringList(optionsAsList), "", nil
+}
+
+// ParseRenderedOptions parses the options that the options expression of a variable rendered to. This can be a JSON
+// list, the string output of a Go list, such as "[foo bar baz]", or one option per line, optionally prefixed with "- "
+// as in a YAML list.
+func ParseRenderedOptions(rendered string) ([]string, error) {
+	trimmed := strings.TrimSpace(rendered)
+
+	if strings.HasPrefix(trimmed, "[") {
+		options, err := parseStringAsList(trimmed, String)
+		if err != nil {
+			return nil, err
+		}
+
+		return util.ToStringList(options), nil
+	}
+
+	options := []string{}
+
+	for line := range strings.Lines(trimmed) {
+		if option := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "- ")); option != "" {
+			options = append(options, option)
+		}
+	}
+
+	return options, nil
+}
+
+// Given a map of key:value pairs read from a Boilerplate YAML config file of the format:
+//
+// min_selections: <MIN>
+// max_selections: <MAX>
+//
+// This method looks up the bounds on the number of options that may be selected for a ListOfEnums variable with the
+// given number of options. Both are optional, and a max of 0 means there is no maximum. If the given variableType is
+// not ListOfEnums and either bound has been specified, or the bounds can't be met, this method will return an error.
+func unmarshalSelectionBounds(fields map[string]any, context string, variableType BoilerplateType, numOptions int) (int, int, error) {
+	bounds := [2]int{}
+
+	for i, fieldName := range []string{"min_selections", "max_selections"} {
+		bound, err := unmarshalIntField(fields, fieldName, false, context)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		if bound == nil {
+			continue
+		}
+
+		if variableType != ListOfEnums {
+			return 0, 0, SelectionBoundsCanOnlyBeUsedWithListOfEnums{Context: context, FieldName: fieldName, Type: variableType}
+		}
+
+		bounds[i] = *bound
+	}
+
+	minSelections, maxSelections := bounds[0], bounds[1]
+	// The number of options is only known up front if they are not rendered from an expression
+	if minSelections < 0 || maxSelections < 0 || (numOptions > 0 && minSelections > numOptions) || (maxSelections > 0 && minSelections > maxSelections) {
+		return 0, 0, InvalidSelectionBounds{Context: context, Min: minSelections, Max: maxSelections, NumOptions: numOptions}
 	}
 
-	return util.ToStringList(optionsAsList), nil
+	return minSelections, maxSelections, nil
+}
+
+// Given a map of key:value pairs read from a Boilerplate YAML config file of the format:
+//
+// fields:
+//   - name: <NAME>
+//     type: <TYPE>
+//   - name: <NAME>
+//     default: <DEFAULT>
+//
+// This method takes looks up the fields object in the map and unmarshals the data inside of it into a list of
+// Variables, one per field. This is meant to be used to parse the fields of an Object or ListOfObjects variable. If
+// the given variableType is not one of those and fields have been specified, or it is one of those and fields have not
+// been specified, this method will return an error.
+func unmarshalFieldsField(fields map[string]any, context string, variableType BoilerplateType) ([]Variable, error) {
+	if _, hasFields := fields["fields"]; !hasFields {
+		if variableType.IsObject() {
+			return nil, FieldsMissing{Context: context, Type: variableType}
+		}
+
+		return nil, nil
+	}
+
+	if !variableType.IsObject() {
+		return nil, FieldsCanOnlyBeUsedWithObject{Context: context, Type: variableType}
+	}
+
+	listOfFields, err := unmarshalListOfFields(fields, "fields")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(listOfFields) == 0 {
+		return nil, FieldsMissing{Context: context, Type: variableType}
+	}
+
+	objectFields := make([]Variable, 0, len(listOfFields))
+	fieldNames := []string{}
+
+	for _, fieldFields := range listOfFields {
+		field, err := UnmarshalVariableFromBoilerplateConfigYaml(fieldFields)
+		if err != nil {
+			return nil, err
+		}
+
+		if util.ListContains(field.Name(), fieldNames) {
+			return nil, DuplicateFieldName{Context: context, FieldName: field.Name()}
+		}
+
+		fieldNames = append(fieldNames, field.Name())
+		objectFields = append(objectFields, field)
+	}
+
+	return objectFields, nil
 }
 
 // Given a map of key:value pairs read from a Boilerplate YAML config file of the format:
@@ -338,32 +466,54 @@ func ParseYamlString(str string) (any, error) {
 	return parsedValue, nil
 }
 
-// Parse a list of YAML files that define variables into a map from variable name to variable value. Along the way,
-// each value is parsed as YAML.
-func parseVariablesFromVarFiles(varFileList []string) (map[string]any, error) {
+// Parse a list of var files that define variables into a map from variable name to variable value. Later files
+// override earlier ones. Also returns the names of the variables that the var files marked as sensitive.
+func parseVariablesFromVarFiles(varFileList []string) (map[string]any, []string, error) {
 	vars := map[string]any{}
+	sensitive := []string{}
 
 	for _, varFile := range varFileList {
-		varsInFile, err := ParseVariablesFromVarFile(varFile)
+		varsInFile, sensitiveInFile, err := parseVarFile(varFile)
 		if err != nil {
-			return vars, err
+			return vars, sensitive, err
 		}
 
 		vars = util.MergeMaps(vars, varsInFile)
+		sensitive = append(sensitive, sensitiveInFile...)
 	}
 
-	return vars, nil
+	return vars, sensitive, nil
+}
+
+// Parse the given var file, which is either a path to a var file or a `terraform output -json` file using
+// TerraformOutputVarFilePrefix.
+func parseVarFile(varFile string) (map[string]any, []string, error) {
+	if path, prefix, isTerraformOutput := SplitTerraformOutputVarFile(varFile); isTerraformOutput {
+		return ParseTerraformOutputsFromFile(path, prefix)
+	}
+
+	vars, err := ParseVariablesFromVarFile(varFile)
+
+	return vars, nil, err
 }
 
-// ParseVariablesFromVarFile parses the variables in the given YAML file into a map of variable name to variable value. Along the way, each value
-// is parsed as YAML.
+// ParseVariablesFromVarFile parses the variables in the given var file into a map of variable name to variable value.
+// The format of the file is detected from its extension (see VarFileFormatForPath): YAML, JSON, TOML, .env, and
+// Terraform's .tfvars and .tfvars.json are supported.
 func ParseVariablesFromVarFile(varFilePath string) (map[string]any, error) {
 	bytes, err := os.ReadFile(varFilePath)
 	if err != nil {
 		return map[string]any{}, err
 	}
 
-	return parseVariablesFromVarFileContents(bytes)
+	format := VarFileFormatForPath(varFilePath)
+
+	vars, err := parseVariablesFromVarFileContentsWithFormat(bytes, format, varFilePath)
+	if err != nil {
+		return map[string]any{}, InvalidVarFile{Err: err, Path: varFilePath, Format: format}
+	}
+
+	return vars, nil
 }
 
 // Parse the variables in the given YAML contents into a map of variable name to variable value. Along the way, each
@@ -389,27 +539,34 @@ func parseVariablesFromVarFileContents(varFileContents []byte) (map[string]any,
 }
 
 // ParseVars parses variables passed in via command line options, either as a list of NAME=VALUE variable pairs in varsList, or a
-// list of paths to YAML files that define NAME: VALUE pairs. Return a map of the NAME: VALUE pairs. Along the way,
+// list of paths to var files that define NAME: VALUE pairs. Return a map of the NAME: VALUE pairs. Along the way,
 // each VALUE is parsed as YAML.
 func ParseVars(varsList []string, varFileList []string) (map[string]any, error) {
+	vars, _, err := ParseVarsWithSensitive(varsList, varFileList)
+	return vars, err
+}
+
+// ParseVarsWithSensitive is like ParseVars, but also returns the names of the variables that their source marked as
+// sensitive, such as the sensitive outputs in a `terraform output -json` var file.
+func ParseVarsWithSensitive(varsList []string, varFileList []string) (map[string]any, []string, error) {
 	variables := map[string]any{}
 
 	varsFromEnv, err := parseVariablesFromEnvironmentVariables()
 	if err != nil {
-		return variables, err
+		return variables, nil, err
 	}
 
 	varsFromVarsList, err := parseVariablesFromKeyValuePairs(varsList)
 	if err != nil {
-		return variables, err
+		return variables, nil, err
 	}
 
-	varsFromVarFiles, err := parseVariablesFromVarFiles(varFileList)
+	varsFromVarFiles, sensitive, err := parseVariablesFromVarFiles(varFileList)
 	if err != nil {
-		return variables, err
+		return variables, nil, err
 	}
 
-	return util.MergeMaps(varsFromEnv, varsFromVarsList, varsFromVarFiles), nil
+	return util.MergeMaps(varsFromEnv, varsFromVarsList, varsFromVarFiles), sensitive, nil
 }
 
 // ConvertYAMLToStringMap recursively walks a YAML-unmarshaled value and ensures
@@ -451,10 +608,58 @@ func (err YAMLConversionErr) Error() string {
 	return fmt.Sprintf("YAML value has type %s and cannot be cast to to the correct type.", reflect.TypeOf(err.Key))
 }
 
+type InvalidVarFile struct {
+	Err    error
+	Path   string
+	Format VarFileFormat
+}
+
+func (err InvalidVarFile) Error() string {
+	return fmt.Sprintf("Error parsing var file %s as %s: %v", err.Path, err.Format, err.Err)
+}
+
+func (err InvalidVarFile) Unwrap() error {
+	return err.Err
+}
+
+type TerraformOutputMissingValue string
+
+func (err TerraformOutputMissingValue) Error() string {
+	return fmt.Sprintf("Output %s does not have a value. Expected the JSON produced by 'terraform output -json'.", string(err))
+}
+
+type InvalidOverridePath string
+
+func (err InvalidOverridePath) Error() string {
+	return fmt.Sprintf("Invalid path %s. Expected a dot-separated path such as NAME.KEY, with no empty parts.", string(err))
+}
+
+type InvalidOverride struct {
+	Err  error
+	Path string
+}
+
+func (err InvalidOverride) Error() string {
+	return fmt.Sprintf("Cannot set %s: %v", err.Path, err.Err)
+}
+
+func (err InvalidOverride) Unwrap() error {
+	return err.Err
+}
+
+type OverrideTargetNotMap struct {
+	Value any
+	Key   string
+}
+
+func (err OverrideTargetNotMap) Error() string {
+	return fmt.Sprintf("cannot set key %s on value '%v', as it is not a map or object", err.Key, err.Value)
+}
+
 type OptionsMissing string
 
 func (err OptionsMissing) Error() string {
-	return fmt.Sprintf("%s has type %s but does not specify any options. You must specify at least one option.", string(err), Enum)
+	return fmt.Sprintf("%s has type %s or %s but does not specify any options. You must specify at least one option.", string(err), Enum, ListOfEnums)
 }
 
 type InvalidVariableValue struct {
@@ -463,8 +668,8 @@ type InvalidVariableValue struct {
 }
 
 func (err InvalidVariableValue) Error() string {
-	message := fmt.Sprintf("Value '%v' is not a valid value for variable '%s' with type '%s'.", err.Value, err.Variable.Name(), err.Variable.Type().String())
-	if err.Variable.Type() == Enum {
+	message := fmt.Sprintf("Value '%v' is not a valid value for variable '%s' with type '%s'.", RedactValue(err.Variable, err.Value), err.Variable.Name(), err.Variable.Type().String())
+	if err.Variable.Type().HasOptions() {
 		message = fmt.Sprintf("%s. Value must be one of: %s.", message, err.Variable.Options())
 	}
 
@@ -477,7 +682,107 @@ type OptionsCanOnlyBeUsedWithEnum struct {
 }
 
 func (err OptionsCanOnlyBeUsedWithEnum) Error() string {
-	return fmt.Sprintf("%s has type %s and tries to specify options. Options may only be specified for the %s type.", err.Context, err.Type.String(), Enum)
+	return fmt.Sprintf("%s has type %s and tries to specify options. Options may only be specified for the %s and %s types.", err.Context, err.Type.String(), Enum, ListOfEnums)
+}
+
+type SelectionBoundsCanOnlyBeUsedWithListOfEnums struct {
+	Context   string
+	FieldName string
+	Type      BoilerplateType
+}
+
+func (err SelectionBoundsCanOnlyBeUsedWithListOfEnums) Error() string {
+	return fmt.Sprintf("%s has type %s and tries to specify %s. It may only be specified for the %s type.", err.Context, err.Type.String(), err.FieldName, ListOfEnums)
+}
+
+type InvalidSelectionBounds struct {
+	Context    string
+	Min        int
+	Max        int
+	NumOptions int
+}
+
+func (err InvalidSelectionBounds) Error() string {
+	return fmt.Sprintf("%s has invalid bounds on the number of selections: min_selections is %d and max_selections is %d, with %d options. Neither may be negative, and min_selections may be neither greater than max_selections nor greater than the number of options.", err.Context, err.Min, err.Max, err.NumOptions)
+}
+
+type DuplicateSelection struct {
+	Value    any
+	Variable Variable
+}
+
+func (err DuplicateSelection) Error() string {
+	return fmt.Sprintf("Value '%v' is selected more than once for variable '%s'.", RedactValue(err.Variable, err.Value), err.Variable.Name())
+}
+
+type SelectionCountOutOfRange struct {
+	Variable Variable
+	Count    int
+}
+
+func (err SelectionCountOutOfRange) Error() string {
+	if err.Variable.MaxSelections() > 0 {
+		return fmt.Sprintf("Variable '%s' must have between %d and %d values selected, but has %d.", err.Variable.Name(), err.Variable.MinSelections(), err.Variable.MaxSelections(), err.Count)
+	}
+
+	return fmt.Sprintf("Variable '%s' must have at least %d values selected, but has %d.", err.Variable.Name(), err.Variable.MinSelections(), err.Count)
+}
+
+type FieldsMissing struct {
+	Context string
+	Type    BoilerplateType
+}
+
+func (err FieldsMissing) Error() string {
+	return fmt.Sprintf("%s has type %s but does not specify any fields. You must specify at least one field.", err.Context, err.Type.String())
+}
+
+type ValueAndDefaultBothSet struct {
+	Context string
+}
+
+func (err ValueAndDefaultBothSet) Error() string {
+	return fmt.Sprintf("%s specifies both value and default. A computed variable with a value cannot also have a default.", err.Context)
+}
+
+type OverridableRequiresValue struct {
+	Context string
+}
+
+func (err OverridableRequiresValue) Error() string {
+	return fmt.Sprintf("%s sets overridable but does not specify a value. Only computed variables can be overridable.", err.Context)
+}
+
+type FieldsCanOnlyBeUsedWithObject struct {
+	Context string
+	Type    BoilerplateType
+}
+
+func (err FieldsCanOnlyBeUsedWithObject) Error() string {
+	return fmt.Sprintf("%s has type %s and tries to specify fields. Fields may only be specified for the %s and %s types.", err.Context, err.Type.String(), Object, ListOfObjects)
+}
+
+type DuplicateFieldName struct {
+	Context   string
+	FieldName string
+}
+
+func (err DuplicateFieldName) Error() string {
+	return fmt.Sprintf("%s has a duplicate field named %s. All field names must be unique!", err.Context, err.FieldName)
+}
+
+type UnknownObjectField struct {
+	Variable  Variable
+	FieldName string
+}
+
+func (err UnknownObjectField) Error() string {
+	fieldNames := make([]string, 0, len(err.Variable.Fields()))
+	for _, field := range err.Variable.Fields() {
+		fieldNames = append(fieldNames, field.Name())
+	}
+
+	return fmt.Sprintf("Field '%s' is not declared on variable '%s' with type '%s'. Declared fields are: %s.", err.FieldName, err.Variable.Name(), err.Variable.Type().String(), fieldNames)
 }
 
 type InvalidTypeForField struct {